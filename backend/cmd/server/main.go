@@ -5,10 +5,13 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/blytz/live/backend/internal/app"
 	"github.com/blytz/live/backend/internal/infrastructure/cache/redis"
+	"github.com/blytz/live/backend/internal/infrastructure/payment/stripe"
 	"github.com/blytz/live/backend/internal/infrastructure/persistence/postgres"
 )
 
@@ -16,7 +19,19 @@ func main() {
 	cfg := &app.Config{
 		Environment: getEnv("ENV", "development"),
 		Port:        getEnv("PORT", "8080"),
-		JWTSecret:   getEnv("JWT_SECRET", "dev-secret-change-in-production"),
+		JWT: app.JWTConfig{
+			Secret:       getEnv("JWT_SECRET", app.DefaultJWTSecret),
+			KeysDir:      getEnv("JWT_KEYS_DIR", ""),
+			SigningKeyID: getEnv("JWT_SIGNING_KEY_ID", ""),
+		},
+		Auth: app.AuthConfig{
+			AppURL:               getEnv("APP_URL", "http://localhost:3000"),
+			RequireVerifiedEmail: getEnv("REQUIRE_VERIFIED_EMAIL", "false") == "true",
+		},
+		Mail: app.MailConfig{
+			From:      getEnv("MAIL_FROM", "Blytz <no-reply@blytz.live>"),
+			OutboxDir: getEnv("MAIL_OUTBOX_DIR", ""),
+		},
 		Database: postgres.Config{
 			Host:            getEnv("DB_HOST", "localhost"),
 			Port:            getEnv("DB_PORT", "5432"),
@@ -27,6 +42,21 @@ func main() {
 			MaxOpenConns:    100,
 			MaxIdleConns:    50,
 		},
+		Payment: app.PaymentConfig{
+			Provider:      getEnv("PAYMENT_PROVIDER", "fake"),
+			WebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", app.DefaultWebhookSecret),
+			Stripe: stripe.Config{
+				SecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
+				WebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+				BaseURL:       getEnv("STRIPE_BASE_URL", ""),
+			},
+		},
+		Ledger: app.LedgerConfig{
+			DefaultFeeBps:  getEnvInt("PLATFORM_FEE_BPS", 1000),
+			MinimumPayout:  float64(getEnvInt("PAYOUT_MINIMUM", 10)),
+			PayoutInterval: time.Duration(getEnvInt("PAYOUT_INTERVAL_MINUTES", 60)) * time.Minute,
+		},
+		TaxRatesFile: getEnv("TAX_RATES_FILE", "config/tax_rates.json"),
 		Redis: redis.Config{
 			Host:     getEnv("REDIS_HOST", "localhost"),
 			Port:     getEnv("REDIS_PORT", "6379"),
@@ -66,3 +96,12 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
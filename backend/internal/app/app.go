@@ -2,22 +2,44 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"github.com/blytz/live/backend/internal/application/auction"
 	"github.com/blytz/live/backend/internal/application/auth"
+	"github.com/blytz/live/backend/internal/application/cart"
 	"github.com/blytz/live/backend/internal/application/category"
+	"github.com/blytz/live/backend/internal/application/deposit"
+	"github.com/blytz/live/backend/internal/application/dispute"
+	"github.com/blytz/live/backend/internal/application/flashsale"
+	"github.com/blytz/live/backend/internal/application/inventory"
+	"github.com/blytz/live/backend/internal/application/invoice"
+	"github.com/blytz/live/backend/internal/application/ledger"
+	"github.com/blytz/live/backend/internal/application/order"
+	"github.com/blytz/live/backend/internal/application/payment"
 	"github.com/blytz/live/backend/internal/application/product"
+	"github.com/blytz/live/backend/internal/application/promotion"
+	"github.com/blytz/live/backend/internal/application/shipping"
 	"github.com/blytz/live/backend/internal/application/upload"
+	paymentDomain "github.com/blytz/live/backend/internal/domain/payment"
 	userDomain "github.com/blytz/live/backend/internal/domain/user"
 	"github.com/blytz/live/backend/internal/infrastructure/cache/redis"
 	httpInfra "github.com/blytz/live/backend/internal/infrastructure/http"
+	fileMail "github.com/blytz/live/backend/internal/infrastructure/mail/file"
+	invoicePDF "github.com/blytz/live/backend/internal/infrastructure/invoice/pdf"
 	redisMessaging "github.com/blytz/live/backend/internal/infrastructure/messaging/redis"
+	fakePayment "github.com/blytz/live/backend/internal/infrastructure/payment/fake"
+	fakePayout "github.com/blytz/live/backend/internal/infrastructure/payout/fake"
+	"github.com/blytz/live/backend/internal/infrastructure/payment/stripe"
 	"github.com/blytz/live/backend/internal/infrastructure/persistence/postgres"
+	"github.com/blytz/live/backend/internal/infrastructure/storage/r2"
+	taxRules "github.com/blytz/live/backend/internal/infrastructure/tax/rules"
 	"github.com/blytz/live/backend/internal/infrastructure/websocket"
 	"github.com/blytz/live/backend/internal/interfaces/http/handlers"
+	"github.com/blytz/live/backend/internal/interfaces/middleware"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
@@ -34,15 +56,29 @@ type Application struct {
 	productService  *product.Service
 	categoryService *category.Service
 	uploadService   *upload.Service
+	orderService    *order.Service
+	cartService     *cart.Service
+	paymentService  *payment.Service
+	inventoryService *inventory.Service
+	ledgerService   *ledger.Service
+	shippingService *shipping.Service
+	promotionService *promotion.Service
+	flashSaleService *flashsale.Service
+	invoiceService   *invoice.Service
+	disputeService   *dispute.Service
+	depositService   *deposit.Service
 	
 	// Infrastructure
 	r2Client    *r2.Client
 	
 	// Infrastructure
+	auctionCache *redis.AuctionCache
 	httpServer  *httpInfra.Server
 	wsHub       *websocket.Hub
 	eventBus    *redisMessaging.EventBus
 	tokenManager userDomain.TokenManager
+	jwks         *httpInfra.JWKSet
+	sessionStore *redis.SessionStore
 }
 
 // Config holds application configuration
@@ -51,8 +87,55 @@ type Config struct {
 	Port        string
 	Database    postgres.Config
 	Redis       redis.Config
-	JWTSecret   string
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Mail        MailConfig
 	R2          r2.Config
+	Payment     PaymentConfig
+	Ledger      LedgerConfig
+	TaxRatesFile string // JSON tax rate table; no tax is charged if it is missing
+}
+
+// DefaultJWTSecret is the development HS256 secret; the application refuses
+// to start with it in production
+const DefaultJWTSecret = "dev-secret-change-in-production"
+
+// JWTConfig holds token signing configuration. With KeysDir set, tokens are
+// signed with asymmetric keys and Secret is ignored.
+type JWTConfig struct {
+	Secret       string // HS256 secret used when no key directory is configured
+	KeysDir      string // PEM keys named <kid>.pem, RS256 (RSA) or EdDSA (Ed25519)
+	SigningKeyID string // kid signing new tokens; optional when there is a single key
+}
+
+// AuthConfig holds account verification settings
+type AuthConfig struct {
+	AppURL               string // frontend base URL of verification and reset links
+	RequireVerifiedEmail bool   // only users with a verified email may bid or sell
+}
+
+// MailConfig holds outgoing mail configuration
+type MailConfig struct {
+	From      string
+	OutboxDir string // dev sender writes .eml files here; empty logs them instead
+}
+
+// DefaultWebhookSecret is the development secret for fake gateway webhooks.
+// Only the fake gateway uses it, and that is refused in production.
+const DefaultWebhookSecret = "dev-webhook-secret"
+
+// PaymentConfig holds payment provider configuration
+type PaymentConfig struct {
+	Provider      string // "stripe" or "fake"
+	Stripe        stripe.Config
+	WebhookSecret string // signs fake gateway webhooks
+}
+
+// LedgerConfig holds marketplace fee and payout configuration
+type LedgerConfig struct {
+	DefaultFeeBps  int     // platform fee in basis points when no fee rule matches
+	MinimumPayout  float64 // smallest available balance paid out
+	PayoutInterval time.Duration
 }
 
 // New creates a new Application instance
@@ -82,14 +165,14 @@ func New(cfg *Config) (*Application, error) {
 		return nil, fmt.Errorf("failed to initialize services: %w", err)
 	}
 
-	if err := app.initHTTPServer(); err != nil {
-		return nil, fmt.Errorf("failed to initialize HTTP server: %w", err)
-	}
-
 	if err := app.initWebSocketHub(); err != nil {
 		return nil, fmt.Errorf("failed to initialize WebSocket hub: %w", err)
 	}
 
+	if err := app.initHTTPServer(); err != nil {
+		return nil, fmt.Errorf("failed to initialize HTTP server: %w", err)
+	}
+
 	return app, nil
 }
 
@@ -111,6 +194,37 @@ func (a *Application) Run(ctx context.Context) error {
 		return a.wsHub.Start(ctx)
 	})
 
+	// Start domain event consumer
+	g.Go(func() error {
+		log.Println("Event consumer starting...")
+		return a.eventBus.Consume(ctx, a.handleEvent)
+	})
+
+	// Start expired cart cleanup
+	g.Go(func() error {
+		return a.cartService.RunCleanup(ctx, time.Hour)
+	})
+
+	// Start seller payouts
+	g.Go(func() error {
+		return a.ledgerService.RunPayouts(ctx, a.config.Ledger.PayoutInterval)
+	})
+
+	// Start expired stock reservation release
+	g.Go(func() error {
+		return a.inventoryService.RunExpiry(ctx, time.Minute)
+	})
+
+	// Start cancellation of auction orders their winners did not pay
+	g.Go(func() error {
+		return a.orderService.RunPaymentDeadlines(ctx, 15*time.Minute)
+	})
+
+	// Start escalation of disputes past their response deadline
+	g.Go(func() error {
+		return a.disputeService.RunEscalation(ctx, 15*time.Minute)
+	})
+
 	// Wait for shutdown signal
 	<-ctx.Done()
 	log.Println("Shutdown signal received, gracefully stopping...")
@@ -195,14 +309,51 @@ func (a *Application) initRedis() error {
 
 // initTokenManager initializes JWT token manager
 func (a *Application) initTokenManager() error {
-	a.tokenManager = httpInfra.NewJWTTokenManager(
-		a.config.JWTSecret,
+	keys, signingKeyID, err := a.loadJWTKeys()
+	if err != nil {
+		return err
+	}
+
+	manager, err := httpInfra.NewJWTTokenManager(
+		keys,
+		signingKeyID,
 		time.Hour,          // Access token expiry
 		7*24*time.Hour,     // Refresh token expiry
 	)
+	if err != nil {
+		return err
+	}
+	a.tokenManager = manager
+	a.jwks = manager.JWKS()
+	log.Printf("Signing tokens with key %q (%d keys loaded)", signingKeyID, len(keys))
 	return nil
 }
 
+// loadJWTKeys loads the configured signing keys, falling back to the shared
+// HS256 secret when no key directory is set
+func (a *Application) loadJWTKeys() ([]*httpInfra.JWTKey, string, error) {
+	cfg := a.config.JWT
+	if cfg.KeysDir == "" {
+		if a.config.Environment == "production" && (cfg.Secret == "" || cfg.Secret == DefaultJWTSecret) {
+			return nil, "", errors.New("refusing to start in production with the default JWT secret; set JWT_SECRET or JWT_KEYS_DIR")
+		}
+		return []*httpInfra.JWTKey{httpInfra.NewHMACKey("hs256", cfg.Secret)}, "hs256", nil
+	}
+
+	keys, err := httpInfra.LoadJWTKeys(cfg.KeysDir)
+	if err != nil {
+		return nil, "", err
+	}
+	signingKeyID := cfg.SigningKeyID
+	if signingKeyID == "" {
+		if len(keys) != 1 {
+			return nil, "", errors.New("JWT_SIGNING_KEY_ID is required when several keys are loaded")
+		}
+		signingKeyID = keys[0].ID
+	}
+	return keys, signingKeyID, nil
+}
+
 // initEventBus initializes the event bus
 func (a *Application) initEventBus() error {
 	a.eventBus = redisMessaging.NewEventBus(a.redis.GetClient())
@@ -225,20 +376,72 @@ func (a *Application) initServices() error {
 	auctionRepo := postgres.NewAuctionRepository(a.db)
 	productRepo := postgres.NewProductRepository(a.db)
 	categoryRepo := postgres.NewCategoryRepository(a.db)
+	orderRepo := postgres.NewOrderRepository(a.db)
+	cartRepo := postgres.NewCartRepository(a.db)
+	paymentRepo := postgres.NewPaymentRepository(a.db)
+	paymentMethodRepo := postgres.NewPaymentMethodRepository(a.db)
+	reservationRepo := postgres.NewReservationRepository(a.db)
+	ledgerRepo := postgres.NewLedgerRepository(a.db)
+	shippingProfileRepo := postgres.NewShippingProfileRepository(a.db)
+	addressRepo := postgres.NewAddressRepository(a.db)
+	couponRepo := postgres.NewPromotionRepository(a.db)
+	flashSaleRepo := postgres.NewFlashSaleRepository(a.db)
+	invoiceRepo := postgres.NewInvoiceRepository(a.db)
+	disputeRepo := postgres.NewDisputeRepository(a.db)
+	strikeRepo := postgres.NewStrikeRepository(a.db)
+	depositRepo := postgres.NewDepositRepository(a.db)
+	recoveryCodeRepo := postgres.NewRecoveryCodeRepository(a.db)
+	twoFactorPolicyRepo := postgres.NewTwoFactorPolicyRepository(a.db)
+	
+	// Initialize tax calculator
+	taxCalculator, err := a.newTaxCalculator()
+	if err != nil {
+		return fmt.Errorf("failed to load tax rates: %w", err)
+	}
+	
+	// Initialize caches
+	a.auctionCache = redis.NewAuctionCache(a.redis)
+	
+	// Payments and bidder deposits go through the same provider
+	paymentGateway, err := a.newPaymentGateway()
+	if err != nil {
+		return err
+	}
 	
-	// Initialize auth service
+	// Initialize mail sender (development sender writing to an outbox)
+	mailer, err := fileMail.NewSender(a.config.Mail.OutboxDir, a.config.Mail.From)
+	if err != nil {
+		return fmt.Errorf("failed to initialize mail sender: %w", err)
+	}
+	
+	// Initialize auth service (sessions and single-use email tokens live in Redis)
+	a.sessionStore = redis.NewSessionStore(a.redis)
 	a.authService = auth.NewService(
 		userRepo,
-		nil, // Session repo - TODO: implement Redis session store
+		a.sessionStore,
 		a.tokenManager,
+		redis.NewActionTokenStore(a.redis),
+		mailer,
+		recoveryCodeRepo,
+		twoFactorPolicyRepo,
+		redis.NewAttemptCounter(a.redis),
+		auth.Config{AppURL: a.config.Auth.AppURL},
 	)
 	
-	// Initialize auction service
-	a.auctionService = auction.NewService(
-		auctionRepo,
-		nil, // Cache - TODO: implement Redis cache
-		a.eventBus,
-	)
+	// Initialize flash sale service (sale prices, with Redis absorbing the purchase rush)
+	a.flashSaleService = flashsale.NewService(flashSaleRepo, redis.NewFlashSaleCounter(a.redis), productRepo)
+	
+	// Initialize inventory service (stock holds shared by auctions, orders and payments)
+	a.inventoryService = inventory.NewService(reservationRepo, orderRepo, couponRepo, paymentRepo, a.flashSaleService)
+	
+	// Initialize ledger service (fees, seller balances and payouts)
+	a.ledgerService = ledger.NewService(ledgerRepo, productRepo, userRepo, fakePayout.NewProvider(), ledger.Config{
+		DefaultFeeBps: a.config.Ledger.DefaultFeeBps,
+		MinimumPayout: paymentDomain.ToMinorUnits(a.config.Ledger.MinimumPayout),
+	})
+	
+	// Initialize deposit service (bidder holds and payment method checks for auctions)
+	a.depositService = deposit.NewService(depositRepo, auctionRepo, paymentMethodRepo, paymentGateway)
 	
 	// Initialize product service
 	a.productService = product.NewService(productRepo, categoryRepo)
@@ -248,11 +451,77 @@ func (a *Application) initServices() error {
 	
 	// Initialize upload service
 	a.uploadService = upload.NewService(a.r2Client)
+	
+	// Initialize shipping service (address book, seller rates and quotes)
+	a.shippingService = shipping.NewService(shippingProfileRepo, addressRepo, productRepo)
+	
+	// Initialize promotion service (coupons)
+	a.promotionService = promotion.NewService(couponRepo)
+	
+	// Initialize order service
+	a.orderService = order.NewService(orderRepo, auctionRepo, productRepo, a.inventoryService, a.ledgerService, a.shippingService, taxCalculator, a.promotionService, a.flashSaleService, strikeRepo, a.depositService)
+	
+	// Initialize auction service
+	a.auctionService = auction.NewService(
+		auctionRepo,
+		a.auctionCache,
+		a.eventBus,
+		a.inventoryService,
+		orderRepo,
+		a.orderService,
+		productRepo,
+		strikeRepo,
+		a.depositService,
+	)
+	
+	// Initialize cart service
+	a.cartService = cart.NewService(cartRepo, productRepo, orderRepo, a.inventoryService, a.shippingService, taxCalculator, a.promotionService, a.flashSaleService)
+	
+	// Initialize invoice service (numbered invoices rendered to PDF and kept in R2)
+	a.invoiceService = invoice.NewService(invoiceRepo, orderRepo, userRepo, productRepo, invoicePDF.NewRenderer(), a.r2Client)
+	
+	// Initialize payment service
+	a.paymentService = payment.NewService(paymentRepo, paymentMethodRepo, orderRepo, paymentGateway, a.inventoryService, a.ledgerService, a.invoiceService, a.eventBus, a.depositService)
+	
+	// Initialize dispute service (evidence goes through uploads, refunds through payments)
+	a.disputeService = dispute.NewService(disputeRepo, orderRepo, a.paymentService, a.uploadService)
 
 	log.Println("Services initialized")
 	return nil
 }
 
+// newTaxCalculator loads the configured tax rate table
+func (a *Application) newTaxCalculator() (*taxRules.Calculator, error) {
+	calc, err := taxRules.LoadFile(a.config.TaxRatesFile)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Tax rates file %q not found, no tax will be charged", a.config.TaxRatesFile)
+		return taxRules.NewCalculator(&taxRules.File{})
+	}
+	return calc, err
+}
+
+// newPaymentGateway creates the configured payment gateway. The fake gateway,
+// which approves any card, is refused in production.
+func (a *Application) newPaymentGateway() (paymentDomain.Gateway, error) {
+	cfg := a.config.Payment
+	production := a.config.Environment == "production"
+
+	switch cfg.Provider {
+	case "stripe":
+		if production && (cfg.Stripe.SecretKey == "" || cfg.Stripe.WebhookSecret == "") {
+			return nil, errors.New("refusing to start in production without Stripe keys; set STRIPE_SECRET_KEY and STRIPE_WEBHOOK_SECRET")
+		}
+		log.Println("Using Stripe payment gateway")
+		return stripe.NewGateway(cfg.Stripe), nil
+	default:
+		if production {
+			return nil, errors.New("refusing to start in production with the fake payment gateway; set PAYMENT_PROVIDER=stripe")
+		}
+		log.Println("Using fake payment gateway")
+		return fakePayment.NewGateway(cfg.WebhookSecret), nil
+	}
+}
+
 // initHTTPServer initializes the HTTP server
 func (a *Application) initHTTPServer() error {
 	handlers := &httpInfra.Handlers{
@@ -260,14 +529,34 @@ func (a *Application) initHTTPServer() error {
 		Auction:   handlers.NewAuctionHandler(a.auctionService),
 		Product:   handlers.NewProductHandler(a.productService),
 		Category:  handlers.NewCategoryHandler(a.categoryService),
-		AuctionWS: handlers.NewAuctionWSHandler(a.wsHub),
+		AuctionWS: handlers.NewAuctionWSHandler(a.wsHub, a.auctionService),
 		Upload:    handlers.NewUploadHandler(a.uploadService),
+		Order:     handlers.NewOrderHandler(a.orderService),
+		Cart:      handlers.NewCartHandler(a.cartService),
+		Promotion: handlers.NewPromotionHandler(a.promotionService),
+		FlashSale: handlers.NewFlashSaleHandler(a.flashSaleService),
+		Invoice:   handlers.NewInvoiceHandler(a.invoiceService),
+		Dispute:   handlers.NewDisputeHandler(a.disputeService),
+		Deposit:   handlers.NewDepositHandler(a.depositService),
+		Payment:   handlers.NewPaymentHandler(a.paymentService),
+		Ledger:    handlers.NewLedgerHandler(a.ledgerService),
+		Shipping:  handlers.NewShippingHandler(a.shippingService),
+	}
+
+	// Left nil unless required, so the middleware lets everyone through
+	var emailVerifier middleware.EmailVerifier
+	if a.config.Auth.RequireVerifiedEmail {
+		emailVerifier = a.authService
 	}
 
 	a.httpServer = httpInfra.NewServer(
 		a.config.Port,
 		handlers,
 		a.tokenManager,
+		a.sessionStore,
+		emailVerifier,
+		a.authService,
+		a.jwks,
 		a.redis,
 	)
 	return nil
@@ -275,6 +564,6 @@ func (a *Application) initHTTPServer() error {
 
 // initWebSocketHub initializes the WebSocket hub
 func (a *Application) initWebSocketHub() error {
-	a.wsHub = websocket.NewHub(a.redis.GetClient(), a.eventBus)
+	a.wsHub = websocket.NewHub(a.redis.GetClient(), a.eventBus, a.auctionCache)
 	return nil
 }
\ No newline at end of file
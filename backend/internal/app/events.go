@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+	"log"
+
+	redisMessaging "github.com/blytz/live/backend/internal/infrastructure/messaging/redis"
+)
+
+// handleEvent reacts to domain events published on the event bus. Anything
+// that must not be lost with a message, such as an auction winner's order,
+// is done by the publishing service instead.
+func (a *Application) handleEvent(ctx context.Context, event redisMessaging.Event) {
+	switch event.Type {
+	case redisMessaging.EventOrderRefunded:
+		a.handleOrderRefunded(ctx, event)
+	}
+}
+
+// handleOrderRefunded tells the buyer about a refund. No notification
+// channel exists yet, so the notice is only logged.
+func (a *Application) handleOrderRefunded(ctx context.Context, event redisMessaging.Event) {
+	buyerID, _ := event.Payload["buyer_id"].(string)
+	amount, _ := event.Payload["amount"].(float64)
+	log.Printf("Order %s refunded %.2f to buyer %s", event.OrderID, amount, buyerID)
+}
@@ -2,28 +2,47 @@ package auction
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	depositApp "github.com/blytz/live/backend/internal/application/deposit"
+	inventoryApp "github.com/blytz/live/backend/internal/application/inventory"
+	orderApp "github.com/blytz/live/backend/internal/application/order"
 	"github.com/blytz/live/backend/internal/domain/auction"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/blytz/live/backend/internal/domain/user"
 	appErrors "github.com/blytz/live/backend/pkg/errors"
 	"github.com/google/uuid"
 )
 
 // Service provides auction use cases
 type Service struct {
-	repo      auction.Repository
-	cache     auction.Cache
-	eventBus  auction.EventBus
+	repo        auction.Repository
+	cache       auction.Cache
+	eventBus    auction.EventBus
+	inventory   *inventoryApp.Service
+	orderRepo   order.Repository
+	orders      *orderApp.Service
+	productRepo product.Repository
+	strikes     user.StrikeRepository
+	deposits    *depositApp.Service
 }
 
 // NewService creates a new auction service
-func NewService(repo auction.Repository, cache auction.Cache, eventBus auction.EventBus) *Service {
+func NewService(repo auction.Repository, cache auction.Cache, eventBus auction.EventBus, inventory *inventoryApp.Service, orderRepo order.Repository, orders *orderApp.Service, productRepo product.Repository, strikes user.StrikeRepository, deposits *depositApp.Service) *Service {
 	return &Service{
-		repo:     repo,
-		cache:    cache,
-		eventBus: eventBus,
+		repo:        repo,
+		cache:       cache,
+		eventBus:    eventBus,
+		inventory:   inventory,
+		orderRepo:   orderRepo,
+		orders:      orders,
+		productRepo: productRepo,
+		strikes:     strikes,
+		deposits:    deposits,
 	}
 }
 
@@ -36,26 +55,61 @@ func (s *Service) CreateAuction(ctx context.Context, req *CreateAuctionRequest)
 	if req.EndTime.Before(req.StartTime) {
 		return nil, appErrors.New(appErrors.ErrValidation, "end time must be after start time")
 	}
+	if !req.BidderRequirement.IsValid() {
+		return nil, appErrors.New(appErrors.ErrValidation, "invalid bidder requirement")
+	}
+	if req.BidderRequirement == auction.RequireDepositHold && req.DepositAmount <= 0 {
+		return nil, appErrors.New(appErrors.ErrValidation, "deposit amount must be positive")
+	}
+	if req.BidderRequirement != auction.RequireDepositHold && req.DepositAmount != 0 {
+		return nil, appErrors.New(appErrors.ErrValidation, "deposit amount requires the deposit_hold bidder requirement")
+	}
+
+	// Sellers can only auction their own products
+	p, err := s.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		if errors.Is(err, product.ErrProductNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "product not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get product")
+	}
+	if p.SellerID != req.SellerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "you can only auction your own products")
+	}
 
 	a := &auction.Auction{
-		ID:           uuid.New(),
-		ProductID:    req.ProductID,
-		SellerID:     req.SellerID,
-		Title:        req.Title,
-		Description:  req.Description,
-		StartTime:    req.StartTime,
-		EndTime:      req.EndTime,
-		Status:       auction.StatusScheduled,
-		StartPrice:   req.StartPrice,
-		ReservePrice: req.ReservePrice,
-		BuyNowPrice:  req.BuyNowPrice,
-		LiveKitRoom:  fmt.Sprintf("auction-%s", uuid.New().String()),
-		AutoExtend:   true,
-		ExtendTime:   300, // 5 minutes
-		IsFeatured:   req.IsFeatured,
+		ID:                uuid.New(),
+		ProductID:         req.ProductID,
+		SellerID:          req.SellerID,
+		Title:             req.Title,
+		Description:       req.Description,
+		StartTime:         req.StartTime,
+		EndTime:           req.EndTime,
+		Status:            auction.StatusScheduled,
+		StartPrice:        req.StartPrice,
+		ReservePrice:      req.ReservePrice,
+		BuyNowPrice:       req.BuyNowPrice,
+		BidderRequirement: req.BidderRequirement,
+		DepositAmount:     req.DepositAmount,
+		LiveKitRoom:       fmt.Sprintf("auction-%s", uuid.New().String()),
+		AutoExtend:        true,
+		ExtendTime:        300, // 5 minutes
+		IsFeatured:        req.IsFeatured,
+		RelistedFrom:      req.RelistedFrom,
+	}
+
+	// Hold one unit so the item cannot be sold elsewhere while auctioned
+	if err := s.inventory.ReserveAuction(ctx, a.ProductID, a.SellerID, a.ID); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(ctx, a); err != nil {
+		if releaseErr := s.inventory.ReleaseAuction(ctx, a.ID, "auction not created"); releaseErr != nil {
+			log.Printf("Failed to release stock held for auction %s: %v", a.ID, releaseErr)
+		}
+		if errors.Is(err, auction.ErrAlreadyRelisted) {
+			return nil, appErrors.New(appErrors.ErrConflict, "auction has already been relisted")
+		}
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create auction")
 	}
 
@@ -88,6 +142,7 @@ func (s *Service) GetAuction(ctx context.Context, id uuid.UUID) (*auction.Auctio
 				BidCount:  state.BidCount,
 				CurrentBid: state.CurrentBid,
 			}
+			s.loadViewerCount(ctx, a)
 			return a, nil
 		}
 	}
@@ -109,20 +164,39 @@ func (s *Service) GetAuction(ctx context.Context, id uuid.UUID) (*auction.Auctio
 			LastUpdated: time.Now(),
 		}
 		s.cache.SetAuctionState(ctx, id, state, time.Hour)
+		s.loadViewerCount(ctx, a)
 	}
 
 	return a, nil
 }
 
+// loadViewerCount fills in the cluster-wide viewer count
+func (s *Service) loadViewerCount(ctx context.Context, a *auction.Auction) {
+	count, err := s.cache.GetViewerCount(ctx, a.ID)
+	if err != nil {
+		log.Printf("Failed to get viewer count: %v", err)
+		return
+	}
+	a.ViewerCount = count
+}
+
 // PlaceBid places a bid on an auction
 func (s *Service) PlaceBid(ctx context.Context, auctionID, userID uuid.UUID, amount float64, isAutoBid bool) (*auction.Bid, error) {
+	if err := s.ensureCanBid(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Get auction with lock
 	a, err := s.repo.GetWithBids(ctx, auctionID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.deposits.CheckBidder(ctx, a, userID); err != nil {
+		return nil, err
+	}
 
 	// Validate and place bid using domain logic
+	previousEndTime := a.EndTime
 	bid, err := a.PlaceBid(userID, amount, time.Now())
 	if err != nil {
 		// Map domain errors to app errors
@@ -154,6 +228,12 @@ func (s *Service) PlaceBid(ctx context.Context, auctionID, userID uuid.UUID, amo
 		if err := s.eventBus.PublishBidPlaced(ctx, auctionID, bid); err != nil {
 			log.Printf("Failed to publish bid event: %v", err)
 		}
+		// Late bids push the end time out; clients count down from the new one
+		if a.EndTime.After(previousEndTime) {
+			if err := s.eventBus.PublishAuctionExtended(ctx, auctionID, a.EndTime); err != nil {
+				log.Printf("Failed to publish extension event: %v", err)
+			}
+		}
 	}
 
 	// Update cache
@@ -212,12 +292,16 @@ func (s *Service) StartAuction(ctx context.Context, auctionID uuid.UUID) error {
 	return nil
 }
 
-// EndAuction ends an auction
+// EndAuction ends an auction and creates the winner's order. Ending an
+// auction that already ended with a winner retries creating the order.
 func (s *Service) EndAuction(ctx context.Context, auctionID uuid.UUID) error {
 	a, err := s.repo.GetWithBids(ctx, auctionID)
 	if err != nil {
 		return err
 	}
+	if a.Status == auction.StatusEnded && a.WinnerID != nil {
+		return s.createWinnerOrder(ctx, a)
+	}
 
 	if err := a.End(time.Now()); err != nil {
 		return err
@@ -227,6 +311,25 @@ func (s *Service) EndAuction(ctx context.Context, auctionID uuid.UUID) error {
 		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to end auction")
 	}
 
+	// The order is created here rather than from the ended event, which may
+	// be lost; an error leaves the auction ended for the caller to retry
+	var orderErr error
+	if a.WinnerID != nil {
+		orderErr = s.createWinnerOrder(ctx, a)
+	}
+
+	// Unsold items go back to available stock; sold ones move to the winner's order
+	if a.WinnerID == nil {
+		if err := s.inventory.ReleaseAuction(ctx, auctionID, "auction ended without winner"); err != nil {
+			log.Printf("Failed to release stock of auction %s: %v", auctionID, err)
+		}
+	}
+
+	// Losing bidders get their deposits back now; the winner's is released once they pay
+	if err := s.deposits.ReleaseAuction(ctx, auctionID, a.WinnerID); err != nil {
+		log.Printf("Failed to release deposits of auction %s: %v", auctionID, err)
+	}
+
 	// Publish event
 	if s.eventBus != nil {
 		s.eventBus.PublishAuctionEnded(ctx, auctionID, a.WinnerID)
@@ -237,9 +340,69 @@ func (s *Service) EndAuction(ctx context.Context, auctionID uuid.UUID) error {
 		s.cache.DeleteAuctionState(ctx, auctionID)
 	}
 
+	return orderErr
+}
+
+// createWinnerOrder creates the pending order the winner of an ended auction
+// pays through. It is idempotent, so retries never create a second order.
+func (s *Service) createWinnerOrder(ctx context.Context, a *auction.Auction) error {
+	o, err := s.orders.CreateFromAuction(ctx, a.ID, *a.WinnerID)
+	if err != nil {
+		log.Printf("Failed to create order for auction %s: %v", a.ID, err)
+		return err
+	}
+	log.Printf("Order %s pending payment for auction %s", o.ID, a.ID)
 	return nil
 }
 
+// RelistAuction reopens the item of an auction that ended unsold, or whose
+// winner never paid, as a new scheduled auction with the original's settings
+func (s *Service) RelistAuction(ctx context.Context, auctionID, sellerID uuid.UUID, startTime, endTime time.Time) (*auction.Auction, error) {
+	a, err := s.repo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if a.SellerID != sellerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "only the seller can relist this auction")
+	}
+	if a.Status != auction.StatusEnded && a.Status != auction.StatusCancelled {
+		return nil, appErrors.New(appErrors.ErrConflict, "auction has not ended")
+	}
+	if a.RelistedAs != nil {
+		return nil, appErrors.New(appErrors.ErrConflict, "auction has already been relisted").
+			WithDetails("relisted_as", a.RelistedAs.String())
+	}
+
+	if a.WinnerID != nil {
+		o, err := s.orderRepo.GetByAuctionID(ctx, auctionID)
+		if err != nil {
+			if errors.Is(err, order.ErrOrderNotFound) {
+				return nil, appErrors.New(appErrors.ErrConflict, "winner's order is still being created")
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get auction order")
+		}
+		if o.Status != order.StatusCancelled {
+			return nil, appErrors.New(appErrors.ErrConflict, "auction item has been sold to its winner")
+		}
+	}
+
+	return s.CreateAuction(ctx, &CreateAuctionRequest{
+		ProductID:         a.ProductID,
+		SellerID:          a.SellerID,
+		Title:             a.Title,
+		Description:       a.Description,
+		StartTime:         startTime,
+		EndTime:           endTime,
+		StartPrice:        a.StartPrice,
+		ReservePrice:      a.ReservePrice,
+		BuyNowPrice:       a.BuyNowPrice,
+		BidderRequirement: a.BidderRequirement,
+		DepositAmount:     a.DepositAmount,
+		IsFeatured:        a.IsFeatured,
+		RelistedFrom:      &a.ID,
+	})
+}
+
 // ListLiveAuctions lists currently live auctions
 func (s *Service) ListLiveAuctions(ctx context.Context, page, pageSize int) ([]*auction.Auction, error) {
 	return s.repo.GetLiveAuctions(ctx, pageSize, (page-1)*pageSize)
@@ -247,6 +410,10 @@ func (s *Service) ListLiveAuctions(ctx context.Context, page, pageSize int) ([]*
 
 // SetAutoBid sets up automatic bidding
 func (s *Service) SetAutoBid(ctx context.Context, auctionID, userID uuid.UUID, maxAmount, increment float64) (*auction.AutoBid, error) {
+	if err := s.ensureCanBid(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Check if auto-bid exists
 	autoBids, err := s.repo.GetActiveAutoBids(ctx, auctionID)
 	if err != nil {
@@ -282,6 +449,20 @@ func (s *Service) SetAutoBid(ctx context.Context, auctionID, userID uuid.UUID, m
 	return autoBid, nil
 }
 
+// ensureCanBid rejects users suspended from bidding for not paying for
+// auctions they won
+func (s *Service) ensureCanBid(ctx context.Context, userID uuid.UUID) error {
+	strikes, err := s.strikes.CountSince(ctx, userID, time.Now().Add(-user.StrikeLifetime))
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to check bidding eligibility")
+	}
+	if strikes >= user.MaxActiveStrikes {
+		return appErrors.New(appErrors.ErrBiddingSuspended, "bidding is suspended after repeated unpaid auctions").
+			WithDetails("strikes", strikes)
+	}
+	return nil
+}
+
 // processAutoBids processes automatic bids
 func (s *Service) processAutoBids(ctx context.Context, auctionID uuid.UUID, currentBid float64) {
 	autoBids, err := s.repo.GetActiveAutoBids(ctx, auctionID)
@@ -314,14 +495,17 @@ func (s *Service) processAutoBids(ctx context.Context, auctionID uuid.UUID, curr
 // Request/Response types
 
 type CreateAuctionRequest struct {
-	ProductID    uuid.UUID
-	SellerID     uuid.UUID
-	Title        string
-	Description  string
-	StartTime    time.Time
-	EndTime      time.Time
-	StartPrice   float64
-	ReservePrice *float64
-	BuyNowPrice  *float64
-	IsFeatured   bool
+	ProductID         uuid.UUID
+	SellerID          uuid.UUID
+	Title             string
+	Description       string
+	StartTime         time.Time
+	EndTime           time.Time
+	StartPrice        float64
+	ReservePrice      *float64
+	BuyNowPrice       *float64
+	BidderRequirement auction.BidderRequirement
+	DepositAmount     float64
+	IsFeatured        bool
+	RelistedFrom      *uuid.UUID // set when relisting an ended auction
 }
\ No newline at end of file
@@ -2,27 +2,53 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/blytz/live/backend/internal/domain/mail"
 	"github.com/blytz/live/backend/internal/domain/user"
 	appErrors "github.com/blytz/live/backend/pkg/errors"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Config holds account email settings
+type Config struct {
+	AppURL string // frontend base URL emailed links point at
+}
+
+// errWrongSecondFactor marks a two-factor code that did not check out
+var errWrongSecondFactor = errors.New("invalid two-factor code")
+
 // Service provides authentication use cases
 type Service struct {
-	userRepo       user.Repository
-	sessionRepo    user.SessionRepository
-	tokenManager   user.TokenManager
+	userRepo      user.Repository
+	sessionRepo   user.SessionRepository
+	tokenManager  user.TokenManager
+	actionTokens  user.ActionTokenStore
+	mailer        mail.Sender
+	recoveryCodes user.RecoveryCodeRepository
+	policyRepo    user.TwoFactorPolicyRepository
+	attempts      user.AttemptCounter
+	config        Config
 }
 
 // NewService creates a new auth service
-func NewService(userRepo user.Repository, sessionRepo user.SessionRepository, tokenManager user.TokenManager) *Service {
+func NewService(userRepo user.Repository, sessionRepo user.SessionRepository, tokenManager user.TokenManager, actionTokens user.ActionTokenStore, mailer mail.Sender, recoveryCodes user.RecoveryCodeRepository, policyRepo user.TwoFactorPolicyRepository, attempts user.AttemptCounter, config Config) *Service {
 	return &Service{
-		userRepo:     userRepo,
-		sessionRepo:  sessionRepo,
-		tokenManager: tokenManager,
+		userRepo:      userRepo,
+		sessionRepo:   sessionRepo,
+		tokenManager:  tokenManager,
+		actionTokens:  actionTokens,
+		mailer:        mailer,
+		recoveryCodes: recoveryCodes,
+		policyRepo:    policyRepo,
+		attempts:      attempts,
+		config:        config,
 	}
 }
 
@@ -34,20 +60,54 @@ type RegisterRequest struct {
 	LastName  string
 	Phone     string
 	Role      user.Role
+	Client    ClientInfo
 }
 
 // LoginRequest represents login request
 type LoginRequest struct {
 	Email    string
 	Password string
+	Client   ClientInfo
+}
+
+// ClientInfo describes the device a session is signed in from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
 }
 
-// AuthResponse represents authentication response
+// TwoFactorLoginRequest represents the second step of a two-factor login
+type TwoFactorLoginRequest struct {
+	ChallengeToken string
+	Code           string // authenticator code or recovery code
+	Client         ClientInfo
+}
+
+// AuthResponse represents authentication response. When the user has
+// two-factor authentication on, a password login only sets ChallengeToken,
+// valid for ExpiresIn seconds, and no session is started.
 type AuthResponse struct {
-	User         *user.User
-	AccessToken  string
-	RefreshToken string
-	ExpiresIn    int
+	User           *user.User
+	AccessToken    string
+	RefreshToken   string
+	ExpiresIn      int
+	ChallengeToken string
+	// TwoFactorSetupRequired is set when the user's role requires two-factor
+	// authentication they have not enabled yet
+	TwoFactorSetupRequired bool
+}
+
+// TwoFactorSetup is what an authenticator app needs to enrol
+type TwoFactorSetup struct {
+	Secret          string
+	ProvisioningURI string // otpauth:// URI, shown as a QR code
+}
+
+// TwoFactorStatus describes a user's two-factor authentication
+type TwoFactorStatus struct {
+	Enabled           bool
+	Required          bool
+	RecoveryCodesLeft int
 }
 
 // Register creates a new user account
@@ -82,14 +142,21 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 		LastName:      req.LastName,
 		Phone:         req.Phone,
 		EmailVerified: false,
+		SellerTier:    user.DefaultSellerTier,
 	}
 
 	if err := s.userRepo.Create(ctx, u); err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create user")
 	}
 
+	// The account works without verification, so a mail failure only means
+	// the user has to ask for another link
+	if err := s.sendEmailVerification(ctx, u); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", u.ID, err)
+	}
+
 	// Generate tokens
-	return s.generateTokens(ctx, u)
+	return s.startSession(ctx, u, req.Client)
 }
 
 // Login authenticates a user
@@ -108,36 +175,157 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*AuthResponse,
 		return nil, appErrors.New(appErrors.ErrUnauthorized, "invalid email or password")
 	}
 
-	// Update last login
-	now := time.Now()
-	u.LastLoginAt = &now
-	s.userRepo.Update(ctx, u)
+	// With two-factor authentication on, the password only earns a
+	// challenge to be completed with a code
+	if u.TwoFactorEnabled {
+		token, err := s.issueActionToken(ctx, u, user.PurposeTwoFactorLogin, user.TwoFactorChallengeTTL)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to issue login challenge")
+		}
+		return &AuthResponse{
+			User:           u,
+			ChallengeToken: token,
+			ExpiresIn:      int(user.TwoFactorChallengeTTL.Seconds()),
+		}, nil
+	}
 
-	// Generate tokens
-	return s.generateTokens(ctx, u)
+	return s.completeLogin(ctx, u, req.Client)
+}
+
+// VerifyTwoFactorLogin completes a two-factor login with the challenge from
+// Login and a code from the user's authenticator or a recovery code
+func (s *Service) VerifyTwoFactorLogin(ctx context.Context, req *TwoFactorLoginRequest) (*AuthResponse, error) {
+	claims, err := s.tokenManager.ValidateActionToken(req.ChallengeToken, user.PurposeTwoFactorLogin)
+	if err != nil {
+		return nil, appErrors.New(appErrors.ErrUnauthorized, "invalid or expired login challenge")
+	}
+
+	u, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.TwoFactorEnabled {
+		return nil, appErrors.New(appErrors.ErrUnauthorized, "invalid or expired login challenge")
+	}
+	challengeKey := "2fa-challenge:" + claims.ID.String()
+	if err := s.verifySecondFactor(ctx, u, req.Code); err != nil {
+		if !errors.Is(err, errWrongSecondFactor) {
+			return nil, err
+		}
+		// A mistyped code can be retried, but only a few times per challenge
+		failures, countErr := s.attempts.Fail(ctx, challengeKey, user.TwoFactorChallengeTTL)
+		if countErr != nil {
+			log.Printf("Failed to count two-factor failure of user %s: %v", u.ID, countErr)
+		}
+		if countErr != nil || failures >= user.MaxChallengeFailures {
+			if _, burnErr := s.actionTokens.Consume(ctx, user.PurposeTwoFactorLogin, u.ID, claims.ID); burnErr != nil {
+				log.Printf("Failed to discard login challenge of user %s: %v", u.ID, burnErr)
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrUnauthorized, "too many invalid two-factor codes, sign in again")
+		}
+		return nil, err
+	}
+
+	// The challenge is spent only once the code checks out
+	ok, err := s.actionTokens.Consume(ctx, user.PurposeTwoFactorLogin, u.ID, claims.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to consume login challenge")
+	}
+	if !ok {
+		return nil, appErrors.New(appErrors.ErrUnauthorized, "invalid or expired login challenge")
+	}
+
+	return s.completeLogin(ctx, u, req.Client)
 }
 
-// RefreshToken generates new tokens from refresh token
+// RefreshToken exchanges a refresh token for a new token pair. Refresh tokens
+// are single use: presenting one that was already exchanged means it was
+// copied, so the whole session is revoked and both holders must sign in again.
 func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*AuthResponse, error) {
 	// Validate refresh token
-	claims, err := s.tokenManager.Validate(refreshToken)
+	claims, err := s.tokenManager.ValidateRefresh(refreshToken)
+	if err != nil {
+		return nil, appErrors.New(appErrors.ErrUnauthorized, "invalid refresh token")
+	}
+
+	// Tokens of revoked sessions are no longer honoured
+	session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
 	if err != nil {
+		if errors.Is(err, user.ErrSessionNotFound) {
+			return nil, appErrors.New(appErrors.ErrUnauthorized, "session has been revoked")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get session")
+	}
+
+	if session.UserID != claims.UserID {
 		return nil, appErrors.New(appErrors.ErrUnauthorized, "invalid refresh token")
 	}
 
 	// Get user
-	u, err := s.userRepo.GetByID(ctx, claims.UserID)
+	u, err := s.userRepo.GetByID(ctx, session.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Generate new tokens
-	return s.generateTokens(ctx, u)
+	// Generate new tokens within the same session
+	session.Token, session.RefreshToken, err = s.tokenManager.Generate(session.ID, u.ID, u.Email, u.Role)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to generate tokens")
+	}
+	if err := s.sessionRepo.Rotate(ctx, session, refreshToken); err != nil {
+		switch {
+		case errors.Is(err, user.ErrRefreshTokenReused):
+			log.Printf("Refresh token reused for session %s of user %s, revoking session", session.ID, session.UserID)
+			if err := s.sessionRepo.Delete(ctx, session.ID); err != nil {
+				log.Printf("Failed to revoke session %s after refresh token reuse: %v", session.ID, err)
+			}
+			return nil, appErrors.New(appErrors.ErrUnauthorized, "refresh token has already been used")
+		case errors.Is(err, user.ErrSessionNotFound):
+			return nil, appErrors.New(appErrors.ErrUnauthorized, "session has been revoked")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to rotate session tokens")
+	}
+	return s.authResponse(u, session), nil
 }
 
 // Logout invalidates a session
 func (s *Service) Logout(ctx context.Context, sessionID uuid.UUID) error {
-	return s.sessionRepo.Delete(ctx, sessionID)
+	if err := s.sessionRepo.Delete(ctx, sessionID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to revoke session")
+	}
+	return nil
+}
+
+// LogoutAll invalidates every session of a user, signing them out everywhere
+func (s *Service) LogoutAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.sessionRepo.DeleteByUserID(ctx, userID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to revoke sessions")
+	}
+	return nil
+}
+
+// ListSessions lists a user's signed-in sessions, newest first
+func (s *Service) ListSessions(ctx context.Context, userID uuid.UUID) ([]*user.Session, error) {
+	sessions, err := s.sessionRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list sessions")
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of a user's sessions out
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, user.ErrSessionNotFound) {
+			return appErrors.New(appErrors.ErrNotFound, "session not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to get session")
+	}
+	if session.UserID != userID {
+		return appErrors.New(appErrors.ErrNotFound, "session not found")
+	}
+	return s.Logout(ctx, sessionID)
 }
 
 // GetUser gets user by ID
@@ -167,29 +355,547 @@ func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, currentP
 	return s.userRepo.Update(ctx, u)
 }
 
-// generateTokens creates access and refresh tokens
-func (s *Service) generateTokens(ctx context.Context, u *user.User) (*AuthResponse, error) {
-	accessToken, refreshToken, err := s.tokenManager.Generate(u.ID, u.Email, u.Role)
+// RequestEmailVerification emails the user a new verification link,
+// superseding any earlier one
+func (s *Service) RequestEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.EmailVerified {
+		return appErrors.New(appErrors.ErrConflict, "email is already verified")
+	}
+	if err := s.sendEmailVerification(ctx, u); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to send verification email")
+	}
+	return nil
+}
+
+// ConfirmEmail marks the user's email verified with a token from a
+// verification link
+func (s *Service) ConfirmEmail(ctx context.Context, token string) (*user.User, error) {
+	claims, err := s.consumeActionToken(ctx, token, user.PurposeEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// A link sent to an earlier address does not verify the current one
+	if !strings.EqualFold(u.Email, claims.Email) {
+		return nil, appErrors.Wrap(user.ErrActionTokenInvalid, appErrors.ErrValidation, "invalid or expired token")
+	}
+	if u.EmailVerified {
+		return u, nil
+	}
+
+	u.EmailVerified = true
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update user")
+	}
+	return u, nil
+}
+
+// RequestPasswordReset emails a password reset link to the account with the
+// given email. Unknown addresses are ignored without error so the endpoint
+// does not reveal which emails are registered.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	u, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if appErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueActionToken(ctx, u, user.PurposePasswordReset, user.PasswordResetTTL)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to issue reset token")
+	}
+	err = s.mailer.Send(ctx, &mail.Message{
+		To:      u.Email,
+		Subject: "Reset your Blytz password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your Blytz account.\n\n"+
+			"Choose a new password here within %s:\n%s\n\n"+
+			"If it wasn't you, you can ignore this email.\n", formatTTL(user.PasswordResetTTL), s.link("/reset-password", token)),
+	})
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to send reset email")
+	}
+	return nil
+}
+
+// ResetPassword sets a new password with a token from a reset link. All of
+// the user's sessions are signed out.
+func (s *Service) ResetPassword(ctx context.Context, token, newPassword string) error {
+	claims, err := s.consumeActionToken(ctx, token, user.PurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	u, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to hash password")
+	}
+	u.PasswordHash = string(hash)
+	// Following the link proves the user reads this inbox
+	if strings.EqualFold(u.Email, claims.Email) {
+		u.EmailVerified = true
+	}
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to update user")
+	}
+
+	if err := s.sessionRepo.DeleteByUserID(ctx, u.ID); err != nil {
+		log.Printf("Failed to revoke sessions of user %s after password reset: %v", u.ID, err)
+	}
+	return nil
+}
+
+// IsEmailVerified reports whether a user has verified their email
+func (s *Service) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return u.EmailVerified, nil
+}
+
+// SetupTwoFactor starts two-factor enrolment with a new TOTP secret, which
+// takes effect once ConfirmTwoFactor is called with a code from it
+func (s *Service) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*TwoFactorSetup, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TwoFactorEnabled {
+		return nil, appErrors.New(appErrors.ErrConflict, "two-factor authentication is already enabled")
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to generate secret")
+	}
+	u.TOTPSecret = secret
+	u.TOTPLastStep = 0
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update user")
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totpURI(secret, u.Email),
+	}, nil
+}
+
+// ConfirmTwoFactor turns two-factor authentication on once the user proves
+// their authenticator works, returning their recovery codes. These are only
+// ever shown now. The user's other sessions, which only needed a password,
+// are signed out.
+func (s *Service) ConfirmTwoFactor(ctx context.Context, userID, sessionID uuid.UUID, code string) ([]string, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TwoFactorEnabled {
+		return nil, appErrors.New(appErrors.ErrConflict, "two-factor authentication is already enabled")
+	}
+	if u.TOTPSecret == "" {
+		return nil, appErrors.New(appErrors.ErrValidation, "two-factor setup has not been started")
+	}
+
+	step, ok := verifyTOTP(u.TOTPSecret, strings.TrimSpace(code), time.Now(), u.TOTPLastStep)
+	if !ok {
+		return nil, appErrors.New(appErrors.ErrValidation, "invalid two-factor code")
+	}
+
+	codes, err := s.newRecoveryCodes(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	u.TwoFactorEnabled = true
+	u.TOTPLastStep = step
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update user")
+	}
+
+	s.revokeOtherSessions(ctx, u.ID, sessionID)
+	return codes, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off, which takes the
+// user's password and a current code. Users whose role requires it cannot.
+func (s *Service) DisableTwoFactor(ctx context.Context, userID uuid.UUID, password, code string) error {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !u.TwoFactorEnabled {
+		return appErrors.New(appErrors.ErrValidation, "two-factor authentication is not enabled")
+	}
+
+	required, err := s.twoFactorRequired(ctx, u.Role)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to check two-factor policy")
+	}
+	if required {
+		return appErrors.New(appErrors.ErrForbidden, "two-factor authentication is required for your role")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return appErrors.New(appErrors.ErrUnauthorized, "password is incorrect")
+	}
+	if err := s.verifySecondFactor(ctx, u, code); err != nil {
+		return err
+	}
+
+	u.TwoFactorEnabled = false
+	u.TOTPSecret = ""
+	u.TOTPLastStep = 0
+	if err := s.userRepo.Update(ctx, u); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to update user")
+	}
+	if err := s.recoveryCodes.DeleteByUserID(ctx, u.ID); err != nil {
+		log.Printf("Failed to delete recovery codes of user %s: %v", u.ID, err)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, which takes a
+// current code
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.TwoFactorEnabled {
+		return nil, appErrors.New(appErrors.ErrValidation, "two-factor authentication is not enabled")
+	}
+	if err := s.verifySecondFactor(ctx, u, code); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(ctx, u.ID)
+}
+
+// GetTwoFactorStatus describes the user's two-factor authentication
+func (s *Service) GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*TwoFactorStatus, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := s.twoFactorRequired(ctx, u.Role)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to check two-factor policy")
+	}
+	status := &TwoFactorStatus{Enabled: u.TwoFactorEnabled, Required: required}
+	if u.TwoFactorEnabled {
+		status.RecoveryCodesLeft, err = s.recoveryCodes.CountUnused(ctx, u.ID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to count recovery codes")
+		}
+	}
+	return status, nil
+}
+
+// TwoFactorSatisfied reports whether a user meets their role's two-factor
+// requirement
+func (s *Service) TwoFactorSatisfied(ctx context.Context, userID uuid.UUID) (bool, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if u.TwoFactorEnabled {
+		return true, nil
+	}
+	required, err := s.twoFactorRequired(ctx, u.Role)
+	if err != nil {
+		return false, err
+	}
+	return !required, nil
+}
+
+// ListTwoFactorPolicy lists the roles that must use two-factor authentication
+func (s *Service) ListTwoFactorPolicy(ctx context.Context) ([]user.Role, error) {
+	roles, err := s.policyRepo.RequiredRoles(ctx)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list two-factor policy")
+	}
+	return roles, nil
+}
+
+// SetTwoFactorPolicy makes two-factor authentication mandatory, or optional
+// again, for a role. Users of the role without it are limited to their
+// account settings until they enrol.
+func (s *Service) SetTwoFactorPolicy(ctx context.Context, adminID uuid.UUID, role user.Role, required bool) error {
+	if !user.CanRequireTwoFactor(role) {
+		return appErrors.New(appErrors.ErrValidation, "two-factor authentication can only be required for sellers and admins")
+	}
+	if err := s.policyRepo.SetRequired(ctx, role, required, adminID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to update two-factor policy")
+	}
+	log.Printf("Admin %s set two-factor requirement for role %s to %t", adminID, role, required)
+	return nil
+}
+
+// verifySecondFactor accepts a current authenticator code, which cannot be
+// replayed, or an unused recovery code, which is spent. Users who keep
+// entering wrong codes are locked out for a while.
+func (s *Service) verifySecondFactor(ctx context.Context, u *user.User, code string) error {
+	// Wrong codes are counted per user as well as per login challenge, so
+	// starting new challenges does not buy more guesses
+	userKey := "2fa-user:" + u.ID.String()
+	failures, err := s.attempts.Failures(ctx, userKey)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to check two-factor attempts")
+	}
+	if failures >= user.MaxTwoFactorFailures {
+		return appErrors.New(appErrors.ErrRateLimit, "too many invalid two-factor codes, try again later")
+	}
+
+	ok, err := s.checkSecondFactor(ctx, u, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if _, err := s.attempts.Fail(ctx, userKey, user.TwoFactorLockout); err != nil {
+			log.Printf("Failed to count two-factor failure of user %s: %v", u.ID, err)
+		}
+		return appErrors.Wrap(errWrongSecondFactor, appErrors.ErrUnauthorized, "invalid two-factor code")
+	}
+
+	if err := s.attempts.Reset(ctx, userKey); err != nil {
+		log.Printf("Failed to reset two-factor failures of user %s: %v", u.ID, err)
+	}
+	return nil
+}
+
+// checkSecondFactor checks a TOTP or recovery code, using it up if it is
+// right
+func (s *Service) checkSecondFactor(ctx context.Context, u *user.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	now := time.Now()
+
+	if step, ok := verifyTOTP(u.TOTPSecret, code, now, u.TOTPLastStep); ok {
+		// A concurrent request may have used the same code since u was loaded
+		advanced, err := s.userRepo.AdvanceTOTPStep(ctx, u.ID, step)
+		if err != nil {
+			return false, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update user")
+		}
+		if advanced {
+			u.TOTPLastStep = step
+		}
+		return advanced, nil
+	}
+
+	// Recovery codes are longer than authenticator codes
+	if len(code) > totpDigits {
+		codes, err := s.recoveryCodes.ListUnused(ctx, u.ID)
+		if err != nil {
+			return false, appErrors.Wrap(err, appErrors.ErrInternal, "failed to check recovery code")
+		}
+		for _, c := range codes {
+			if !matchRecoveryCode(c.CodeHash, code) {
+				continue
+			}
+			used, err := s.recoveryCodes.MarkUsed(ctx, c.ID, now)
+			if err != nil {
+				return false, appErrors.Wrap(err, appErrors.ErrInternal, "failed to use recovery code")
+			}
+			if used {
+				log.Printf("Recovery code used by user %s", u.ID)
+			}
+			return used, nil
+		}
+	}
+	return false, nil
+}
+
+// newRecoveryCodes replaces the user's recovery codes, returning the new
+// codes in plain text
+func (s *Service) newRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	now := time.Now()
+	plain := make([]string, user.RecoveryCodeCount)
+	codes := make([]*user.RecoveryCode, user.RecoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to generate recovery codes")
+		}
+		hash, err := hashRecoveryCode(code)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to generate recovery codes")
+		}
+		plain[i] = code
+		codes[i] = &user.RecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CodeHash:  hash,
+			CreatedAt: now,
+		}
+	}
+
+	if err := s.recoveryCodes.Replace(ctx, userID, codes); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to save recovery codes")
+	}
+	return plain, nil
+}
+
+// twoFactorRequired reports whether admins require two-factor
+// authentication for role
+func (s *Service) twoFactorRequired(ctx context.Context, role user.Role) (bool, error) {
+	roles, err := s.policyRepo.RequiredRoles(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range roles {
+		if r == role {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// revokeOtherSessions signs the user out everywhere but the current session
+func (s *Service) revokeOtherSessions(ctx context.Context, userID, currentID uuid.UUID) {
+	sessions, err := s.sessionRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to list sessions of user %s: %v", userID, err)
+		return
+	}
+	for _, session := range sessions {
+		if session.ID == currentID {
+			continue
+		}
+		if err := s.sessionRepo.Delete(ctx, session.ID); err != nil {
+			log.Printf("Failed to revoke session %s: %v", session.ID, err)
+		}
+	}
+}
+
+func (s *Service) sendEmailVerification(ctx context.Context, u *user.User) error {
+	token, err := s.issueActionToken(ctx, u, user.PurposeEmailVerification, user.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, &mail.Message{
+		To:      u.Email,
+		Subject: "Verify your Blytz email",
+		Body: fmt.Sprintf("Welcome to Blytz!\n\n"+
+			"Confirm your email address here within %s:\n%s\n", formatTTL(user.EmailVerificationTTL), s.link("/verify-email", token)),
+	})
+}
+
+// issueActionToken signs a token for purpose and records it as the user's
+// outstanding one
+func (s *Service) issueActionToken(ctx context.Context, u *user.User, purpose user.TokenPurpose, ttl time.Duration) (string, error) {
+	claims := user.ActionClaims{
+		ID:      uuid.New(),
+		UserID:  u.ID,
+		Purpose: purpose,
+		Email:   u.Email,
+	}
+	token, err := s.tokenManager.GenerateActionToken(claims, ttl)
+	if err != nil {
+		return "", err
+	}
+	if err := s.actionTokens.Save(ctx, purpose, u.ID, claims.ID, ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// link returns the frontend link to path carrying token
+func (s *Service) link(path, token string) string {
+	return fmt.Sprintf("%s%s?token=%s", strings.TrimSuffix(s.config.AppURL, "/"), path, url.QueryEscape(token))
+}
+
+// consumeActionToken validates a token for purpose and uses it up
+func (s *Service) consumeActionToken(ctx context.Context, token string, purpose user.TokenPurpose) (*user.ActionClaims, error) {
+	claims, err := s.tokenManager.ValidateActionToken(token, purpose)
+	if err != nil {
+		return nil, appErrors.Wrap(user.ErrActionTokenInvalid, appErrors.ErrValidation, "invalid or expired token")
+	}
+	ok, err := s.actionTokens.Consume(ctx, purpose, claims.UserID, claims.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to consume token")
+	}
+	if !ok {
+		return nil, appErrors.Wrap(user.ErrActionTokenInvalid, appErrors.ErrValidation, "invalid or expired token")
+	}
+	return claims, nil
+}
+
+// formatTTL renders a link lifetime for an email, e.g. "48 hours"
+func formatTTL(d time.Duration) string {
+	if h := int(d.Hours()); h > 1 {
+		return fmt.Sprintf("%d hours", h)
+	}
+	if d >= time.Hour {
+		return "1 hour"
+	}
+	return fmt.Sprintf("%d minutes", int(d.Minutes()))
+}
+
+// completeLogin records the login and starts a session
+func (s *Service) completeLogin(ctx context.Context, u *user.User, client ClientInfo) (*AuthResponse, error) {
+	// Update last login
+	now := time.Now()
+	u.LastLoginAt = &now
+	s.userRepo.Update(ctx, u)
+
+	// Generate tokens
+	return s.startSession(ctx, u, client)
+}
+
+// startSession creates a session lasting as long as its refresh token and
+// issues the session's first tokens
+func (s *Service) startSession(ctx context.Context, u *user.User, client ClientInfo) (*AuthResponse, error) {
+	sessionID := uuid.New()
+	accessToken, refreshToken, err := s.tokenManager.Generate(sessionID, u.ID, u.Email, u.Role)
 	if err != nil {
 		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to generate tokens")
 	}
 
 	// Create session
+	now := time.Now()
 	session := &user.Session{
-		ID:           uuid.New(),
+		ID:           sessionID,
 		UserID:       u.ID,
 		Token:        accessToken,
 		RefreshToken: refreshToken,
-		ExpiresAt:    time.Now().Add(24 * time.Hour),
+		UserAgent:    client.UserAgent,
+		IPAddress:    client.IPAddress,
+		ExpiresAt:    now.Add(s.tokenManager.RefreshTokenTTL()),
+		CreatedAt:    now,
 	}
 	if err := s.sessionRepo.Create(ctx, session); err != nil {
-		return nil, err
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create session")
 	}
 
+	resp := s.authResponse(u, session)
+	if !u.TwoFactorEnabled {
+		required, err := s.twoFactorRequired(ctx, u.Role)
+		if err != nil {
+			log.Printf("Failed to check two-factor policy for user %s: %v", u.ID, err)
+		}
+		resp.TwoFactorSetupRequired = required
+	}
+	return resp, nil
+}
+
+func (s *Service) authResponse(u *user.User, session *user.Session) *AuthResponse {
 	return &AuthResponse{
 		User:         u,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    3600, // 1 hour
-	}, nil
+		AccessToken:  session.Token,
+		RefreshToken: session.RefreshToken,
+		ExpiresIn:    int(s.tokenManager.AccessTokenTTL().Seconds()),
+	}
 }
\ No newline at end of file
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/blytz/live/backend/internal/infrastructure/cache/redis"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// fakeTokenManager issues numbered tokens, so every refresh hands out a
+// distinct pair
+type fakeTokenManager struct {
+	user.TokenManager
+
+	mu     sync.Mutex
+	n      int
+	claims map[string]*user.TokenClaims
+}
+
+func (m *fakeTokenManager) Generate(sessionID, userID uuid.UUID, email string, role user.Role) (string, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.n++
+	access, refresh := fmt.Sprintf("access-%d", m.n), fmt.Sprintf("refresh-%d", m.n)
+	m.claims[refresh] = &user.TokenClaims{UserID: userID, SessionID: sessionID, Email: email, Role: role}
+	return access, refresh, nil
+}
+
+func (m *fakeTokenManager) ValidateRefresh(token string) (*user.TokenClaims, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	claims, ok := m.claims[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func (m *fakeTokenManager) AccessTokenTTL() time.Duration {
+	return 15 * time.Minute
+}
+
+// fakeUserRepository serves a single user
+type fakeUserRepository struct {
+	user.Repository
+	user *user.User
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if id != r.user.ID {
+		return nil, user.ErrUserNotFound
+	}
+	return r.user, nil
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	ctx := context.Background()
+
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient(redis.Config{Host: mr.Host(), Port: mr.Port()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	sessions := redis.NewSessionStore(client)
+
+	u := &user.User{ID: uuid.New(), Email: "buyer@example.com", Role: user.RoleBuyer}
+	tokens := &fakeTokenManager{claims: make(map[string]*user.TokenClaims)}
+	s := NewService(&fakeUserRepository{user: u}, sessions, tokens, nil, nil, nil, nil, nil, Config{})
+
+	// Sign in: the session starts with refresh token N
+	now := time.Now()
+	session := &user.Session{ID: uuid.New(), UserID: u.ID, ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	session.Token, session.RefreshToken, _ = tokens.Generate(session.ID, u.ID, u.Email, u.Role)
+	if err := sessions.Create(ctx, session); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	stolen := session.RefreshToken
+
+	// The legitimate client rotates N to N+1
+	rotated, err := s.RefreshToken(ctx, stolen)
+	if err != nil {
+		t.Fatalf("RefreshToken(N): %v", err)
+	}
+
+	// The thief replays N
+	_, err = s.RefreshToken(ctx, stolen)
+	assertUnauthorized(t, "replayed RefreshToken(N)", err)
+
+	// The whole session is revoked, so neither token works any more
+	if _, err := sessions.GetByID(ctx, session.ID); !errors.Is(err, user.ErrSessionNotFound) {
+		t.Errorf("GetByID after reuse: err = %v, want %v", err, user.ErrSessionNotFound)
+	}
+	for _, token := range []string{rotated.AccessToken, rotated.RefreshToken} {
+		if _, err := sessions.GetByToken(ctx, token); !errors.Is(err, user.ErrSessionNotFound) {
+			t.Errorf("GetByToken(%q) after reuse: err = %v, want %v", token, err, user.ErrSessionNotFound)
+		}
+	}
+	_, err = s.RefreshToken(ctx, rotated.RefreshToken)
+	assertUnauthorized(t, "RefreshToken(N+1)", err)
+	_, err = s.RefreshToken(ctx, stolen)
+	assertUnauthorized(t, "RefreshToken(N) after revocation", err)
+}
+
+func assertUnauthorized(t *testing.T, call string, err error) {
+	t.Helper()
+	var appErr *appErrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != appErrors.ErrUnauthorized {
+		t.Errorf("%s: err = %v, want %s", call, err, appErrors.ErrUnauthorized)
+	}
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TOTP parameters (RFC 6238). These are the defaults every authenticator
+// app supports, so they are not configurable.
+const (
+	totpIssuer     = "Blytz"
+	totpPeriod     = 30 * time.Second
+	totpDigits     = 6
+	totpSkew       = 1  // steps of clock drift accepted either side
+	totpSecretSize = 20 // bytes, the size of a SHA-1 block output
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newTOTPSecret generates a random base32 TOTP secret
+func newTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// totpURI builds the otpauth:// provisioning URI authenticator apps read
+// from a QR code
+func totpURI(secret, account string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", totpIssuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(totpDigits))
+	v.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	label := url.PathEscape(totpIssuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// verifyTOTP checks code against the secret within the accepted clock skew.
+// Steps at or before lastStep were already used and are rejected. It returns
+// the matching step.
+func verifyTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := now.Unix() / int64(totpPeriod.Seconds())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode computes the code for a time step (RFC 4226 dynamic truncation)
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+// newRecoveryCode generates a recovery code of 50 random bits, such as
+// "k3v7q-7xm2p"
+func newRecoveryCode() (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz234567" // 32 symbols, so b%32 is unbiased
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return string(b[:5]) + "-" + string(b[5:]), nil
+}
+
+// hashRecoveryCode hashes a recovery code with bcrypt, like passwords
+func hashRecoveryCode(code string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(code)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// matchRecoveryCode reports whether a code as entered matches a hash from
+// hashRecoveryCode
+func matchRecoveryCode(hash, code string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(normalizeRecoveryCode(code))) == nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes in an entered code
+func normalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the RFC 6238 SHA-1 test key "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestVerifyTOTP(t *testing.T) {
+	at := func(unix int64) time.Time { return time.Unix(unix, 0) }
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		now      time.Time
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		// RFC 6238 appendix B vectors, truncated to six digits
+		{name: "rfc vector 59", secret: rfcSecret, code: "287082", now: at(59), wantStep: 1, wantOK: true},
+		{name: "rfc vector 1111111109", secret: rfcSecret, code: "081804", now: at(1111111109), wantStep: 37037036, wantOK: true},
+		{name: "rfc vector 1111111111", secret: rfcSecret, code: "050471", now: at(1111111111), wantStep: 37037037, wantOK: true},
+		{name: "rfc vector 1234567890", secret: rfcSecret, code: "005924", now: at(1234567890), wantStep: 41152263, wantOK: true},
+		{name: "rfc vector 2000000000", secret: rfcSecret, code: "279037", now: at(2000000000), wantStep: 66666666, wantOK: true},
+
+		{name: "lowercase secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: "287082", now: at(59), wantStep: 1, wantOK: true},
+		{name: "previous step within skew", secret: rfcSecret, code: "287082", now: at(89), wantStep: 1, wantOK: true},
+		{name: "next step within skew", secret: rfcSecret, code: "287082", now: at(29), wantStep: 1, wantOK: true},
+		{name: "two steps late", secret: rfcSecret, code: "287082", now: at(119)},
+		{name: "two steps early", secret: rfcSecret, code: "081804", now: at(1111111020)},
+		{name: "replayed step", secret: rfcSecret, code: "287082", now: at(59), lastStep: 1},
+		{name: "step after last used", secret: rfcSecret, code: "081804", now: at(1111111109), lastStep: 37037035, wantStep: 37037036, wantOK: true},
+		{name: "wrong code", secret: rfcSecret, code: "287083", now: at(59)},
+		{name: "short code", secret: rfcSecret, code: "28708", now: at(59)},
+		{name: "long code", secret: rfcSecret, code: "94287082", now: at(59)},
+		{name: "invalid secret", secret: "not base32!", code: "287082", now: at(59)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := verifyTOTP(tt.secret, tt.code, tt.now, tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("verifyTOTP() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
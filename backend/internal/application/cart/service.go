@@ -0,0 +1,569 @@
+package cart
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	flashsaleApp "github.com/blytz/live/backend/internal/application/flashsale"
+	inventoryApp "github.com/blytz/live/backend/internal/application/inventory"
+	promotionApp "github.com/blytz/live/backend/internal/application/promotion"
+	shippingApp "github.com/blytz/live/backend/internal/application/shipping"
+	"github.com/blytz/live/backend/internal/domain/cart"
+	"github.com/blytz/live/backend/internal/domain/flashsale"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/payment"
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/blytz/live/backend/internal/domain/promotion"
+	"github.com/blytz/live/backend/internal/domain/shipping"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Service provides shopping cart use cases
+type Service struct {
+	repo        cart.Repository
+	productRepo product.Repository
+	orderRepo   order.Repository
+	inventory   *inventoryApp.Service
+	shipping    *shippingApp.Service
+	taxes       order.TaxCalculator
+	promotions  *promotionApp.Service
+	flashSales  *flashsaleApp.Service
+}
+
+// NewService creates a new cart service
+func NewService(repo cart.Repository, productRepo product.Repository, orderRepo order.Repository, inventory *inventoryApp.Service, shipping *shippingApp.Service, taxes order.TaxCalculator, promotions *promotionApp.Service, flashSales *flashsaleApp.Service) *Service {
+	return &Service{
+		repo:        repo,
+		productRepo: productRepo,
+		orderRepo:   orderRepo,
+		inventory:   inventory,
+		shipping:    shipping,
+		taxes:       taxes,
+		promotions:  promotions,
+		flashSales:  flashSales,
+	}
+}
+
+// Owner identifies whose cart is being accessed: a signed-in user, a guest token, or both
+// (a user who still holds a guest token from before logging in).
+type Owner struct {
+	UserID *uuid.UUID
+	Token  string
+}
+
+// View is a cart together with the current state of its products. When a
+// coupon is applied, Discount holds its breakdown, or CouponError explains
+// why it no longer applies.
+type View struct {
+	Cart        *cart.Cart
+	Lines       []Line
+	Subtotal    float64
+	Discount    *promotion.Discount
+	CouponError string
+	Total       float64
+}
+
+// Line is a cart item with its product's current availability and price.
+// While the product is on flash sale, Price is the sale price.
+type Line struct {
+	Item         cart.Item
+	Product      *product.Product
+	FlashSale    *flashsale.Sale
+	Price        float64
+	Available    bool
+	PriceChanged bool
+}
+
+// GetCart returns the owner's cart, creating an empty one if needed
+func (s *Service) GetCart(ctx context.Context, owner Owner) (*View, error) {
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, c)
+}
+
+// AddItem adds quantity of a product to the cart
+func (s *Service) AddItem(ctx context.Context, owner Owner, productID uuid.UUID, quantity int) (*View, error) {
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, ok := c.Find(productID); ok {
+		quantity += existing.Quantity
+	}
+	if err := s.setItem(ctx, c, productID, quantity); err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, c)
+}
+
+// UpdateItem sets the quantity of a product already in the cart
+func (s *Service) UpdateItem(ctx context.Context, owner Owner, productID uuid.UUID, quantity int) (*View, error) {
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := c.Find(productID); !ok {
+		return nil, appErrors.New(appErrors.ErrNotFound, "item not in cart")
+	}
+	if err := s.setItem(ctx, c, productID, quantity); err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, c)
+}
+
+// RemoveItem removes a product from the cart
+func (s *Service) RemoveItem(ctx context.Context, owner Owner, productID uuid.UUID) (*View, error) {
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.RemoveItem(productID, time.Now()); err != nil {
+		return nil, appErrors.New(appErrors.ErrNotFound, "item not in cart")
+	}
+
+	return s.save(ctx, c)
+}
+
+// ClearCart removes all products from the cart
+func (s *Service) ClearCart(ctx context.Context, owner Owner) (*View, error) {
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Clear(time.Now())
+	return s.save(ctx, c)
+}
+
+// ApplyCoupon applies a coupon code to the cart. The coupon must discount
+// the cart as it is now; it is checked again at checkout.
+func (s *Service) ApplyCoupon(ctx context.Context, owner Owner, code string) (*View, error) {
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.view(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.promotions.Evaluate(ctx, code, owner.UserID, couponLines(v.Lines))
+	if err != nil {
+		return nil, err
+	}
+
+	c.SetCoupon(d.Coupon.Code, time.Now())
+	return s.save(ctx, c)
+}
+
+// RemoveCoupon removes the coupon from the cart
+func (s *Service) RemoveCoupon(ctx context.Context, owner Owner) (*View, error) {
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	c.SetCoupon("", time.Now())
+	return s.save(ctx, c)
+}
+
+// MergeGuestCart merges the guest cart identified by token into the user's cart
+func (s *Service) MergeGuestCart(ctx context.Context, userID uuid.UUID, token string) (*View, error) {
+	return s.GetCart(ctx, Owner{UserID: &userID, Token: token})
+}
+
+// Checkout turns the user's cart into one pending order per seller, priced
+// with the cart's coupon, shipping and tax, and holds the stock until the payment window closes.
+// If any product's price changed since it was added, the snapshots are
+// refreshed and checkout must be retried.
+func (s *Service) Checkout(ctx context.Context, owner Owner, dest shippingApp.Destination) ([]*order.Order, error) {
+	if owner.UserID == nil {
+		return nil, appErrors.New(appErrors.ErrUnauthorized, "sign in to check out")
+	}
+
+	shippingAddress, err := s.shipping.ResolveDestination(ctx, *owner.UserID, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Items) == 0 {
+		return nil, appErrors.New(appErrors.ErrValidation, "cart is empty")
+	}
+
+	v, err := s.view(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var sellers []uuid.UUID
+	itemsBySeller := make(map[uuid.UUID][]order.Item)
+	parcels := make(map[uuid.UUID][]shipping.Parcel)
+	categories := make(map[uuid.UUID]*uuid.UUID)
+	flashSales := make(map[uuid.UUID]*flashsale.Sale)
+	priceChanged := false
+	for _, line := range v.Lines {
+		if line.Product == nil || !line.Available {
+			return nil, appErrors.New(appErrors.ErrConflict, "some items are no longer available").
+				WithDetails("product_id", line.Item.ProductID.String())
+		}
+		if line.PriceChanged {
+			if item, ok := c.Find(line.Item.ProductID); ok {
+				item.UnitPrice = line.Price
+			}
+			priceChanged = true
+			continue
+		}
+
+		categories[line.Item.ProductID] = line.Product.CategoryID
+		if line.FlashSale != nil {
+			flashSales[line.Item.ProductID] = line.FlashSale
+		}
+
+		sellerID := line.Product.SellerID
+		if _, ok := itemsBySeller[sellerID]; !ok {
+			sellers = append(sellers, sellerID)
+		}
+		parcels[sellerID] = append(parcels[sellerID], shippingApp.ParcelOf(line.Product, line.Item.Quantity))
+		itemsBySeller[sellerID] = append(itemsBySeller[sellerID], order.Item{
+			ProductID: line.Item.ProductID,
+			Quantity:  line.Item.Quantity,
+			UnitPrice: line.Item.UnitPrice,
+		})
+	}
+
+	if priceChanged {
+		if err := s.repo.Save(ctx, c); err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to save cart")
+		}
+		return nil, appErrors.New(appErrors.ErrConflict, "prices changed, please review your cart")
+	}
+
+	var discount *promotion.Discount
+	if c.CouponCode != "" {
+		discount, err = s.promotions.Evaluate(ctx, c.CouponCode, owner.UserID, couponLines(v.Lines))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	orders := make([]*order.Order, 0, len(sellers))
+	for _, sellerID := range sellers {
+		o := order.NewOrder(*owner.UserID, sellerID, itemsBySeller[sellerID], order.CheckoutPaymentWindow, now)
+		if discount != nil {
+			o.ApplyDiscount(discount.Coupon.Code, discount.ForProduct)
+		}
+
+		quote, err := s.shipping.Quote(ctx, sellerID, parcels[sellerID], o.Subtotal, shippingAddress)
+		if err != nil {
+			s.abortCheckout(ctx, orders)
+			return nil, err
+		}
+		o.SetShipping(shippingAddress, quote.Cost)
+
+		taxQuote, err := s.taxes.Calculate(ctx, order.TaxRequest{
+			Destination: shippingAddress,
+			Lines:       o.TaxLines(func(productID uuid.UUID) *uuid.UUID { return categories[productID] }),
+		})
+		if err != nil {
+			s.abortCheckout(ctx, orders)
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to calculate tax")
+		}
+		o.ApplyTax(taxQuote)
+
+		if err := s.flashSales.Claim(ctx, *owner.UserID, o, flashSales); err != nil {
+			s.abortCheckout(ctx, orders)
+			return nil, err
+		}
+		if err := s.inventory.ReserveOrder(ctx, o); err != nil {
+			s.flashSales.ReleaseOrder(ctx, o.ID)
+			s.abortCheckout(ctx, orders)
+			return nil, err
+		}
+		if err := s.orderRepo.Create(ctx, o); err != nil {
+			s.inventory.ReleaseOrder(ctx, o.ID, "checkout failed")
+			s.flashSales.ReleaseOrder(ctx, o.ID)
+			s.abortCheckout(ctx, orders)
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create order")
+		}
+		orders = append(orders, o)
+	}
+
+	if discount != nil {
+		if err := s.promotions.Redeem(ctx, discount, *owner.UserID, orders); err != nil {
+			s.abortCheckout(ctx, orders)
+			return nil, err
+		}
+	}
+
+	c.Clear(now)
+	c.SetCoupon("", now)
+	if err := s.repo.Save(ctx, c); err != nil {
+		log.Printf("Failed to clear cart %s after checkout: %v", c.ID, err)
+	}
+
+	return orders, nil
+}
+
+// abortCheckout cancels orders created by a checkout that failed part way
+func (s *Service) abortCheckout(ctx context.Context, orders []*order.Order) {
+	now := time.Now()
+	for _, o := range orders {
+		s.inventory.ReleaseOrder(ctx, o.ID, "checkout failed")
+		from := o.Status
+		if err := o.Cancel("checkout failed", now); err == nil {
+			s.orderRepo.TransitionStatus(ctx, o, from)
+		}
+		if o.CouponCode != "" {
+			s.promotions.ReleaseOrder(ctx, o.ID)
+		}
+		s.flashSales.ReleaseOrder(ctx, o.ID)
+	}
+}
+
+// CleanupExpired deletes expired carts
+func (s *Service) CleanupExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, time.Now())
+}
+
+// RunCleanup deletes expired carts periodically until ctx is cancelled
+func (s *Service) RunCleanup(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			deleted, err := s.CleanupExpired(ctx)
+			if err != nil {
+				log.Printf("Failed to clean up expired carts: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d expired carts", deleted)
+			}
+		}
+	}
+}
+
+// resolve loads the owner's cart. For signed-in users a guest cart carried
+// over from before login is merged into the user's cart and then deleted.
+func (s *Service) resolve(ctx context.Context, owner Owner) (*cart.Cart, error) {
+	now := time.Now()
+
+	var guest *cart.Cart
+	if owner.Token != "" {
+		c, err := s.repo.GetByToken(ctx, owner.Token)
+		switch {
+		case err == nil && c.IsExpired(now):
+			s.repo.Delete(ctx, c.ID)
+		case err == nil:
+			guest = c
+		case !errors.Is(err, cart.ErrCartNotFound):
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load cart")
+		}
+	}
+
+	if owner.UserID == nil {
+		if guest != nil && guest.IsGuest() {
+			return guest, nil
+		}
+		return s.create(ctx, nil, now)
+	}
+
+	if guest != nil && !guest.IsGuest() {
+		if *guest.UserID != *owner.UserID {
+			return nil, appErrors.New(appErrors.ErrForbidden, "cart belongs to another user")
+		}
+		return guest, nil
+	}
+
+	c, err := s.repo.GetByUserID(ctx, *owner.UserID)
+	if err != nil {
+		if !errors.Is(err, cart.ErrCartNotFound) {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load cart")
+		}
+		if guest != nil {
+			// Adopt the guest cart instead of creating a new one
+			guest.UserID = owner.UserID
+			guest.Touch(now)
+			if err := s.repo.Save(ctx, guest); err != nil {
+				return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to claim cart")
+			}
+			return guest, nil
+		}
+		return s.create(ctx, owner.UserID, now)
+	}
+
+	if guest != nil {
+		c.Merge(guest, now)
+		if err := s.repo.Save(ctx, c); err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to merge cart")
+		}
+		if err := s.repo.Delete(ctx, guest.ID); err != nil {
+			log.Printf("Failed to delete merged guest cart %s: %v", guest.ID, err)
+		}
+	}
+
+	return c, nil
+}
+
+func (s *Service) create(ctx context.Context, userID *uuid.UUID, now time.Time) (*cart.Cart, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to generate cart token")
+	}
+
+	c := cart.NewCart(userID, token, now)
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create cart")
+	}
+	return c, nil
+}
+
+// setItem validates the product and sets the line, snapshotting the current price for new lines
+func (s *Service) setItem(ctx context.Context, c *cart.Cart, productID uuid.UUID, quantity int) error {
+	if quantity <= 0 {
+		return appErrors.New(appErrors.ErrValidation, "quantity must be greater than zero")
+	}
+	if quantity > cart.MaxItemQuantity {
+		return appErrors.New(appErrors.ErrValidation, fmt.Sprintf("quantity cannot exceed %d", cart.MaxItemQuantity))
+	}
+
+	p, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, product.ErrProductNotFound) {
+			return appErrors.New(appErrors.ErrNotFound, "product not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load product")
+	}
+
+	if !p.IsAvailable() {
+		return appErrors.New(appErrors.ErrConflict, "product is not available")
+	}
+	if c.UserID != nil && p.SellerID == *c.UserID {
+		return appErrors.New(appErrors.ErrValidation, "cannot buy your own product")
+	}
+	if quantity > p.StockQuantity {
+		return appErrors.New(appErrors.ErrConflict, "not enough stock").
+			WithDetails("available", p.StockQuantity)
+	}
+
+	price := p.BasePrice
+	sales, err := s.flashSales.ActiveForProducts(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return err
+	}
+	if sale, ok := sales[productID]; ok {
+		if sale.PerUserLimit != nil && quantity > *sale.PerUserLimit {
+			return appErrors.New(appErrors.ErrValidation, fmt.Sprintf("flash sale allows at most %d per customer", *sale.PerUserLimit))
+		}
+		price = sale.SalePrice
+	}
+
+	return c.SetItem(productID, quantity, price, time.Now())
+}
+
+func (s *Service) save(ctx context.Context, c *cart.Cart) (*View, error) {
+	if err := s.repo.Save(ctx, c); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to save cart")
+	}
+	return s.view(ctx, c)
+}
+
+func (s *Service) view(ctx context.Context, c *cart.Cart) (*View, error) {
+	v := &View{
+		Cart:     c,
+		Lines:    make([]Line, 0, len(c.Items)),
+		Subtotal: c.Subtotal(),
+	}
+
+	productIDs := make([]uuid.UUID, len(c.Items))
+	for i, item := range c.Items {
+		productIDs[i] = item.ProductID
+	}
+	sales, err := s.flashSales.ActiveForProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range c.Items {
+		line := Line{Item: item}
+		p, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil && !errors.Is(err, product.ErrProductNotFound) {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load product")
+		}
+		if p != nil {
+			line.Product = p
+			line.Price = p.BasePrice
+			if sale, ok := sales[item.ProductID]; ok {
+				line.FlashSale = sale
+				line.Price = sale.SalePrice
+			}
+			line.Available = p.IsAvailable() && p.StockQuantity >= item.Quantity
+			line.PriceChanged = line.Price != item.UnitPrice
+		}
+		v.Lines = append(v.Lines, line)
+	}
+
+	v.Total = v.Subtotal
+	if c.CouponCode != "" {
+		d, err := s.promotions.Evaluate(ctx, c.CouponCode, c.UserID, couponLines(v.Lines))
+		var appErr *appErrors.AppError
+		switch {
+		case errors.As(err, &appErr) && appErr.Code != appErrors.ErrInternal:
+			v.CouponError = appErr.Message
+		case err != nil:
+			return nil, err
+		default:
+			v.Discount = d
+			v.Total = payment.FromMinorUnits(payment.ToMinorUnits(v.Subtotal) - payment.ToMinorUnits(d.Amount))
+		}
+	}
+
+	return v, nil
+}
+
+// couponLines returns the cart lines a coupon can be priced against
+func couponLines(lines []Line) []promotion.Line {
+	result := make([]promotion.Line, 0, len(lines))
+	for _, line := range lines {
+		if line.Product == nil {
+			continue
+		}
+		result = append(result, promotion.Line{
+			ProductID:  line.Item.ProductID,
+			CategoryID: line.Product.CategoryID,
+			SellerID:   line.Product.SellerID,
+			Amount:     line.Item.UnitPrice * float64(line.Item.Quantity),
+		})
+	}
+	return result
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
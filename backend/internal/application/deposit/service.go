@@ -0,0 +1,241 @@
+package deposit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/auction"
+	"github.com/blytz/live/backend/internal/domain/deposit"
+	"github.com/blytz/live/backend/internal/domain/payment"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Service provides bidder deposit use cases: pre-authorizing holds on
+// bidders' cards and checking an auction's bidder requirement
+type Service struct {
+	repo        deposit.Repository
+	auctionRepo auction.Repository
+	methodRepo  payment.MethodRepository
+	gateway     payment.Gateway
+}
+
+// NewService creates a new deposit service
+func NewService(repo deposit.Repository, auctionRepo auction.Repository, methodRepo payment.MethodRepository, gateway payment.Gateway) *Service {
+	return &Service{
+		repo:        repo,
+		auctionRepo: auctionRepo,
+		methodRepo:  methodRepo,
+		gateway:     gateway,
+	}
+}
+
+// PlaceHold pre-authorizes the auction's deposit on one of the bidder's
+// saved methods, the default one when methodID is nil. A bidder with an
+// active hold on the auction gets that hold back.
+func (s *Service) PlaceHold(ctx context.Context, auctionID, userID uuid.UUID, methodID *uuid.UUID) (*deposit.Hold, error) {
+	a, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if a.BidderRequirement != auction.RequireDepositHold {
+		return nil, appErrors.New(appErrors.ErrValidation, "auction does not require a deposit")
+	}
+	if a.SellerID == userID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "sellers cannot bid on their own auction")
+	}
+	if a.Status != auction.StatusScheduled && a.Status != auction.StatusLive {
+		return nil, appErrors.New(appErrors.ErrAuctionEnded, "auction is no longer accepting bidders")
+	}
+
+	existing, err := s.repo.GetActive(ctx, auctionID, userID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, deposit.ErrHoldNotFound) {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to look up deposit")
+	}
+
+	m, err := s.chargeableMethod(ctx, userID, methodID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	h := &deposit.Hold{
+		ID:        uuid.New(),
+		AuctionID: auctionID,
+		UserID:    userID,
+		Amount:    a.DepositAmount,
+		Currency:  payment.DefaultCurrency,
+		Provider:  s.gateway.Name(),
+		MethodID:  &m.ID,
+		Status:    deposit.StatusHeld,
+		CreatedAt: now,
+	}
+	result, err := s.gateway.Authorize(ctx, payment.AuthorizeRequest{
+		Amount:         h.Amount,
+		Currency:       h.Currency,
+		MethodRef:      m.MethodRef,
+		CustomerRef:    m.CustomerRef,
+		IdempotencyKey: h.ID.String(),
+		Description:    fmt.Sprintf("Bidder deposit for auction %s", auctionID),
+		Metadata: map[string]string{
+			"auction_id": auctionID.String(),
+			"hold_id":    h.ID.String(),
+		},
+	})
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+	h.GatewayReference = result.Reference
+
+	if err := s.repo.Create(ctx, h); err != nil {
+		// Don't leave funds reserved for a hold we have no record of
+		s.void(ctx, h)
+		if errors.Is(err, deposit.ErrHoldExists) {
+			// A concurrent request won; hand back its hold
+			return s.repo.GetActive(ctx, auctionID, userID)
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to save deposit")
+	}
+	return h, nil
+}
+
+// GetHold retrieves a bidder's active hold on an auction
+func (s *Service) GetHold(ctx context.Context, auctionID, userID uuid.UUID) (*deposit.Hold, error) {
+	h, err := s.repo.GetActive(ctx, auctionID, userID)
+	if err != nil {
+		if errors.Is(err, deposit.ErrHoldNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "no active deposit on this auction")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get deposit")
+	}
+	return h, nil
+}
+
+// CheckBidder rejects bidders who do not meet the auction's bidder requirement
+func (s *Service) CheckBidder(ctx context.Context, a *auction.Auction, userID uuid.UUID) error {
+	switch a.BidderRequirement {
+	case auction.RequirePaymentMethod:
+		methods, err := s.methodRepo.ListByUser(ctx, userID)
+		if err != nil {
+			return appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payment methods")
+		}
+		now := time.Now()
+		for _, m := range methods {
+			if m.Provider == s.gateway.Name() && !m.IsExpired(now) {
+				return nil
+			}
+		}
+		return appErrors.New(appErrors.ErrForbidden, "a valid saved payment method is required to bid on this auction").
+			WithDetails("requirement", string(a.BidderRequirement))
+
+	case auction.RequireDepositHold:
+		h, err := s.repo.GetActive(ctx, a.ID, userID)
+		if err != nil && !errors.Is(err, deposit.ErrHoldNotFound) {
+			return appErrors.Wrap(err, appErrors.ErrInternal, "failed to look up deposit")
+		}
+		if h == nil || h.Amount < a.DepositAmount {
+			return appErrors.New(appErrors.ErrForbidden, "a deposit hold is required to bid on this auction").
+				WithDetails("requirement", string(a.BidderRequirement)).
+				WithDetails("deposit_amount", a.DepositAmount)
+		}
+	}
+	return nil
+}
+
+// ReleaseAuction releases every active hold on an ended auction except the
+// one belonging to keep, the winner, whose hold lasts until they pay
+func (s *Service) ReleaseAuction(ctx context.Context, auctionID uuid.UUID, keep *uuid.UUID) error {
+	holds, err := s.repo.ListActiveByAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	for _, h := range holds {
+		if keep != nil && h.UserID == *keep {
+			continue
+		}
+		if err := s.release(ctx, h); err != nil {
+			log.Printf("Failed to release deposit %s on auction %s: %v", h.ID, auctionID, err)
+		}
+	}
+	return nil
+}
+
+// ReleaseHold releases a bidder's active hold on an auction, if any
+func (s *Service) ReleaseHold(ctx context.Context, auctionID, userID uuid.UUID) error {
+	h, err := s.repo.GetActive(ctx, auctionID, userID)
+	if err != nil {
+		if errors.Is(err, deposit.ErrHoldNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.release(ctx, h)
+}
+
+func (s *Service) release(ctx context.Context, h *deposit.Hold) error {
+	if err := s.gateway.Void(ctx, h.GatewayReference); err != nil && !errors.Is(err, payment.ErrPaymentNotFound) {
+		return err
+	}
+	h.Release(time.Now())
+	return s.repo.Update(ctx, h)
+}
+
+// void releases an authorization that was never recorded as a hold
+func (s *Service) void(ctx context.Context, h *deposit.Hold) {
+	if err := s.gateway.Void(ctx, h.GatewayReference); err != nil {
+		log.Printf("Failed to void unrecorded deposit authorization %s: %v", h.GatewayReference, err)
+	}
+}
+
+func (s *Service) chargeableMethod(ctx context.Context, userID uuid.UUID, methodID *uuid.UUID) (*payment.Method, error) {
+	var m *payment.Method
+	if methodID != nil {
+		found, err := s.methodRepo.GetByID(ctx, *methodID)
+		if err != nil {
+			if errors.Is(err, payment.ErrMethodNotFound) {
+				return nil, appErrors.New(appErrors.ErrNotFound, "payment method not found")
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get payment method")
+		}
+		if found.UserID != userID {
+			return nil, appErrors.New(appErrors.ErrNotFound, "payment method not found")
+		}
+		m = found
+	} else {
+		methods, err := s.methodRepo.ListByUser(ctx, userID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payment methods")
+		}
+		if len(methods) == 0 || !methods[0].IsDefault {
+			return nil, appErrors.New(appErrors.ErrValidation, "no payment method given and no default saved")
+		}
+		m = methods[0]
+	}
+
+	if m.Provider != s.gateway.Name() {
+		return nil, appErrors.New(appErrors.ErrValidation, "payment method belongs to another provider")
+	}
+	if m.IsExpired(time.Now()) {
+		return nil, appErrors.Wrap(payment.ErrMethodExpired, appErrors.ErrValidation, "card has expired")
+	}
+	return m, nil
+}
+
+func gatewayError(err error) error {
+	switch {
+	case errors.Is(err, payment.ErrInsufficientFunds):
+		return appErrors.Wrap(err, appErrors.ErrInsufficientFunds, "insufficient funds for deposit")
+	case errors.Is(err, payment.ErrPaymentDeclined):
+		return appErrors.Wrap(err, appErrors.ErrPaymentFailed, "deposit declined")
+	case errors.Is(err, payment.ErrGatewayUnavailable):
+		return appErrors.Wrap(err, appErrors.ErrTimeout, "payment provider unavailable")
+	default:
+		return appErrors.Wrap(err, appErrors.ErrPaymentFailed, "deposit failed")
+	}
+}
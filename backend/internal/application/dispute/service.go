@@ -0,0 +1,489 @@
+package dispute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	paymentApp "github.com/blytz/live/backend/internal/application/payment"
+	"github.com/blytz/live/backend/internal/application/upload"
+	"github.com/blytz/live/backend/internal/domain/dispute"
+	"github.com/blytz/live/backend/internal/domain/order"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// escalationBatchSize caps how many overdue disputes one sweep escalates
+const escalationBatchSize = 100
+
+// Service provides dispute use cases between buyers, sellers and admins
+type Service struct {
+	repo      dispute.Repository
+	orderRepo order.Repository
+	payments  *paymentApp.Service
+	uploads   *upload.Service
+}
+
+// NewService creates a new dispute service
+func NewService(repo dispute.Repository, orderRepo order.Repository, payments *paymentApp.Service, uploads *upload.Service) *Service {
+	return &Service{
+		repo:      repo,
+		orderRepo: orderRepo,
+		payments:  payments,
+		uploads:   uploads,
+	}
+}
+
+// Actor identifies who acts on a dispute. Admins may act on any dispute,
+// buyers and sellers on their own.
+type Actor struct {
+	UserID  uuid.UUID
+	IsAdmin bool
+}
+
+// Detail is a dispute with its evidence
+type Detail struct {
+	Dispute  *dispute.Dispute
+	Evidence []*dispute.Evidence
+}
+
+// OpenRequest represents a request to open a dispute
+type OpenRequest struct {
+	OrderID     uuid.UUID
+	BuyerID     uuid.UUID
+	Reason      dispute.Reason
+	Description string
+	Amount      float64 // refund asked for; zero asks for everything refundable
+}
+
+// Open opens a dispute against a shipped or delivered order
+func (s *Service) Open(ctx context.Context, req OpenRequest) (*dispute.Dispute, error) {
+	if !req.Reason.IsValid() {
+		return nil, appErrors.New(appErrors.ErrValidation, "invalid dispute reason")
+	}
+	description := strings.TrimSpace(req.Description)
+	if description == "" {
+		return nil, appErrors.New(appErrors.ErrValidation, "description is required")
+	}
+
+	o, err := s.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	if o.BuyerID != req.BuyerID {
+		if o.SellerID == req.BuyerID {
+			return nil, appErrors.New(appErrors.ErrForbidden, "only the buyer can open a dispute")
+		}
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+
+	now := time.Now()
+	switch {
+	case o.PaymentID == nil:
+		return nil, appErrors.New(appErrors.ErrConflict, "order has not been paid")
+	case o.Status == order.StatusDelivered:
+		if o.DeliveredAt != nil && now.Sub(*o.DeliveredAt) > dispute.FilingWindow {
+			return nil, appErrors.New(appErrors.ErrConflict, "dispute window has closed")
+		}
+	case o.Status != order.StatusShipped:
+		return nil, appErrors.New(appErrors.ErrConflict, "only shipped or delivered orders can be disputed")
+	}
+	if req.Amount < 0 || req.Amount > o.TotalAmount {
+		return nil, appErrors.New(appErrors.ErrValidation, "amount must be between 0 and the order total").
+			WithDetails("order_total", o.TotalAmount)
+	}
+
+	d := dispute.NewDispute(o.ID, o.BuyerID, o.SellerID, req.Reason, description, req.Amount, now)
+	if err := s.repo.Create(ctx, d); err != nil {
+		if errors.Is(err, dispute.ErrDisputeExists) {
+			return nil, appErrors.New(appErrors.ErrConflict, "order already has an open dispute")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to open dispute")
+	}
+	return d, nil
+}
+
+// GetDispute returns a dispute and its evidence to its parties or an admin
+func (s *Service) GetDispute(ctx context.Context, id uuid.UUID, actor Actor) (*Detail, error) {
+	d, err := s.get(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence, err := s.repo.ListEvidence(ctx, d.ID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list evidence")
+	}
+	return &Detail{Dispute: d, Evidence: evidence}, nil
+}
+
+// ListOrderDisputes returns an order's disputes to its buyer or seller
+func (s *Service) ListOrderDisputes(ctx context.Context, orderID, userID uuid.UUID) ([]*dispute.Dispute, error) {
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	if !o.IsParticipant(userID) {
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+
+	disputes, err := s.repo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list disputes")
+	}
+	return disputes, nil
+}
+
+// ListResult represents a page of disputes
+type ListResult struct {
+	Disputes   []*dispute.Dispute
+	TotalCount int64
+	Page       int
+	PageSize   int
+}
+
+// ListDisputes lists disputes matching the filter
+func (s *Service) ListDisputes(ctx context.Context, filter dispute.Filter) (*ListResult, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	disputes, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list disputes")
+	}
+	return &ListResult{
+		Disputes:   disputes,
+		TotalCount: total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+	}, nil
+}
+
+// Respond records the seller's response, optionally offering the buyer a refund
+func (s *Service) Respond(ctx context.Context, id, sellerID uuid.UUID, response string, offeredAmount float64) (*dispute.Dispute, error) {
+	d, err := s.get(ctx, id, Actor{UserID: sellerID})
+	if err != nil {
+		return nil, err
+	}
+	if d.SellerID != sellerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "only the seller can respond to this dispute")
+	}
+
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return nil, appErrors.New(appErrors.ErrValidation, "response is required")
+	}
+	if err := s.checkAmount(ctx, d, offeredAmount); err != nil {
+		return nil, err
+	}
+
+	from := d.Status
+	if err := d.Respond(response, offeredAmount, time.Now()); err != nil {
+		return nil, transitionError(d)
+	}
+	if err := s.transition(ctx, d, from); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Escalate hands a dispute to admins at the request of its buyer or seller
+func (s *Service) Escalate(ctx context.Context, id, userID uuid.UUID) (*dispute.Dispute, error) {
+	d, err := s.get(ctx, id, Actor{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	from := d.Status
+	if err := d.Escalate(time.Now()); err != nil {
+		return nil, transitionError(d)
+	}
+	if err := s.transition(ctx, d, from); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// AcceptResponse settles a dispute on the seller's terms at the buyer's
+// request, refunding the amount the seller offered, if any
+func (s *Service) AcceptResponse(ctx context.Context, id, buyerID uuid.UUID) (*dispute.Dispute, error) {
+	d, err := s.get(ctx, id, Actor{UserID: buyerID})
+	if err != nil {
+		return nil, err
+	}
+	if d.BuyerID != buyerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "only the buyer can accept the seller's response")
+	}
+	if d.Status != dispute.StatusSellerResponded {
+		return nil, appErrors.New(appErrors.ErrConflict, "seller has not responded to this dispute")
+	}
+
+	if d.OfferedAmount == 0 {
+		return s.resolve(ctx, d, dispute.Resolution{
+			Outcome:    dispute.OutcomeSellerFavor,
+			ResolvedBy: buyerID,
+			Note:       "buyer accepted the seller's response",
+		})
+	}
+
+	// The refund is the seller's, as offered
+	refundActor := Actor{UserID: d.SellerID}
+	return s.refund(ctx, d, refundActor, buyerID, d.OfferedAmount, "buyer accepted the seller's offer")
+}
+
+// ResolveRequest represents a request to settle a dispute
+type ResolveRequest struct {
+	Outcome dispute.Outcome
+	Amount  float64 // refund amount; zero refunds what the buyer asked for
+	Note    string
+}
+
+// Resolve settles a dispute. Admins may decide either way; the seller may
+// only refund the buyer and the buyer may only withdraw the dispute.
+func (s *Service) Resolve(ctx context.Context, id uuid.UUID, actor Actor, req ResolveRequest) (*dispute.Dispute, error) {
+	d, err := s.get(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !d.IsOpen() {
+		return nil, appErrors.New(appErrors.ErrConflict, "dispute is already resolved")
+	}
+
+	switch req.Outcome {
+	case dispute.OutcomeRefund:
+		if !actor.IsAdmin && d.SellerID != actor.UserID {
+			return nil, appErrors.New(appErrors.ErrForbidden, "only the seller or an admin can refund a dispute")
+		}
+		amount := req.Amount
+		if amount == 0 {
+			amount = d.RequestedAmount
+		}
+		if err := s.checkAmount(ctx, d, amount); err != nil {
+			return nil, err
+		}
+		return s.refund(ctx, d, actor, actor.UserID, amount, req.Note)
+	case dispute.OutcomeSellerFavor:
+		if !actor.IsAdmin && d.BuyerID != actor.UserID {
+			return nil, appErrors.New(appErrors.ErrForbidden, "only the buyer or an admin can close a dispute without a refund")
+		}
+		if req.Amount != 0 {
+			return nil, appErrors.New(appErrors.ErrValidation, "amount only applies to refunds")
+		}
+		return s.resolve(ctx, d, dispute.Resolution{
+			Outcome:    dispute.OutcomeSellerFavor,
+			ResolvedBy: actor.UserID,
+			Note:       req.Note,
+		})
+	default:
+		return nil, appErrors.New(appErrors.ErrValidation, "outcome must be refund or seller_favor")
+	}
+}
+
+// AddEvidenceRequest represents evidence added to a dispute. File is optional.
+type AddEvidenceRequest struct {
+	DisputeID uuid.UUID
+	Actor     Actor
+	Note      string
+	File      io.Reader
+	Filename  string
+	Size      int64
+}
+
+// AddEvidence adds a note and optionally an uploaded file to an unresolved dispute
+func (s *Service) AddEvidence(ctx context.Context, req AddEvidenceRequest) (*dispute.Evidence, error) {
+	d, err := s.get(ctx, req.DisputeID, req.Actor)
+	if err != nil {
+		return nil, err
+	}
+	if !d.IsOpen() {
+		return nil, appErrors.New(appErrors.ErrConflict, "dispute is already resolved")
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if note == "" && req.File == nil {
+		return nil, appErrors.New(appErrors.ErrValidation, "evidence needs a note or a file")
+	}
+
+	e := &dispute.Evidence{
+		ID:        uuid.New(),
+		DisputeID: d.ID,
+		AuthorID:  req.Actor.UserID,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+	if req.File != nil {
+		result, err := s.uploads.UploadDisputeEvidence(ctx, req.File, req.Filename, req.Size)
+		if err != nil {
+			return nil, appErrors.New(appErrors.ErrValidation, err.Error())
+		}
+		e.FileURL = result.URL
+		e.FileKey = result.Key
+		e.ContentType = result.ContentType
+	}
+
+	if err := s.repo.AddEvidence(ctx, e); err != nil {
+		if e.FileKey != "" {
+			if err := s.uploads.DeleteFile(ctx, e.FileKey); err != nil {
+				log.Printf("Failed to delete orphaned evidence %s: %v", e.FileKey, err)
+			}
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to add evidence")
+	}
+	return e, nil
+}
+
+// EscalateOverdue escalates disputes whose party has not acted by the
+// deadline and returns how many were escalated
+func (s *Service) EscalateOverdue(ctx context.Context) (int, error) {
+	now := time.Now()
+	disputes, err := s.repo.ListOverdue(ctx, now, escalationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, d := range disputes {
+		// The party may have acted since the dispute was listed
+		from := d.Status
+		if err := d.Escalate(now); err != nil {
+			continue
+		}
+		if err := s.repo.TransitionStatus(ctx, d, from); err != nil {
+			if !errors.Is(err, dispute.ErrStatusConflict) {
+				log.Printf("Failed to escalate dispute %s: %v", d.ID, err)
+			}
+			continue
+		}
+		escalated++
+	}
+	return escalated, nil
+}
+
+// RunEscalation escalates overdue disputes periodically until ctx is cancelled
+func (s *Service) RunEscalation(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			escalated, err := s.EscalateOverdue(ctx)
+			if err != nil {
+				log.Printf("Failed to escalate overdue disputes: %v", err)
+				continue
+			}
+			if escalated > 0 {
+				log.Printf("Escalated %d overdue disputes", escalated)
+			}
+		}
+	}
+}
+
+// get loads a dispute visible to the actor
+func (s *Service) get(ctx context.Context, id uuid.UUID, actor Actor) (*dispute.Dispute, error) {
+	d, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, dispute.ErrDisputeNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "dispute not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get dispute")
+	}
+	if !actor.IsAdmin && !d.IsParticipant(actor.UserID) {
+		return nil, appErrors.New(appErrors.ErrNotFound, "dispute not found")
+	}
+	return d, nil
+}
+
+// checkAmount validates a refund amount against the disputed order's total.
+// Zero stands for everything still refundable.
+func (s *Service) checkAmount(ctx context.Context, d *dispute.Dispute, amount float64) error {
+	if amount < 0 {
+		return appErrors.New(appErrors.ErrValidation, "amount cannot be negative")
+	}
+	o, err := s.orderRepo.GetByID(ctx, d.OrderID)
+	if err != nil {
+		return orderError(err)
+	}
+	if amount > o.TotalAmount {
+		return appErrors.New(appErrors.ErrValidation, "amount exceeds the order total").
+			WithDetails("order_total", o.TotalAmount)
+	}
+	return nil
+}
+
+// refund refunds the buyer through the order's payment and resolves the
+// dispute in their favor. The refund is keyed by the dispute so a retried
+// resolution cannot refund twice.
+func (s *Service) refund(ctx context.Context, d *dispute.Dispute, refundActor Actor, resolvedBy uuid.UUID, amount float64, note string) (*dispute.Dispute, error) {
+	reason := fmt.Sprintf("dispute %s: %s", d.ID, d.Reason)
+	refund, err := s.payments.RefundOrder(ctx, paymentApp.RefundOrderRequest{
+		OrderID:        d.OrderID,
+		ActorID:        refundActor.UserID,
+		IsAdmin:        refundActor.IsAdmin,
+		Amount:         amount,
+		Reason:         reason,
+		IdempotencyKey: "dispute-" + d.ID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refundID := refund.ID
+	return s.resolve(ctx, d, dispute.Resolution{
+		Outcome:      dispute.OutcomeRefund,
+		RefundAmount: refund.Amount,
+		RefundID:     &refundID,
+		ResolvedBy:   resolvedBy,
+		Note:         note,
+	})
+}
+
+func (s *Service) resolve(ctx context.Context, d *dispute.Dispute, r dispute.Resolution) (*dispute.Dispute, error) {
+	r.Note = strings.TrimSpace(r.Note)
+	r.ResolvedAt = time.Now()
+	from := d.Status
+	if err := d.Resolve(r); err != nil {
+		return nil, transitionError(d)
+	}
+	if err := s.transition(ctx, d, from); err != nil {
+		if r.RefundID != nil {
+			log.Printf("Refund %s issued but dispute %s not marked resolved: %v", *r.RefundID, d.ID, err)
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// transition saves a dispute moved out of status from, failing with a
+// conflict if another request moved it first
+func (s *Service) transition(ctx context.Context, d *dispute.Dispute, from dispute.Status) error {
+	if err := s.repo.TransitionStatus(ctx, d, from); err != nil {
+		if errors.Is(err, dispute.ErrStatusConflict) {
+			return appErrors.Wrap(err, appErrors.ErrConflict, "dispute was updated concurrently")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to update dispute")
+	}
+	return nil
+}
+
+// Helper functions
+func orderError(err error) error {
+	if errors.Is(err, order.ErrOrderNotFound) {
+		return appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+	return appErrors.Wrap(err, appErrors.ErrInternal, "failed to get order")
+}
+
+func transitionError(d *dispute.Dispute) error {
+	return appErrors.New(appErrors.ErrConflict, fmt.Sprintf("dispute is %s", d.Status))
+}
@@ -0,0 +1,278 @@
+package flashsale
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/flashsale"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/product"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Service provides flash sale use cases
+type Service struct {
+	repo        flashsale.Repository
+	counter     flashsale.Counter
+	productRepo product.Repository
+}
+
+// NewService creates a new flash sale service
+func NewService(repo flashsale.Repository, counter flashsale.Counter, productRepo product.Repository) *Service {
+	return &Service{
+		repo:        repo,
+		counter:     counter,
+		productRepo: productRepo,
+	}
+}
+
+// FeedItem is a running sale together with its product
+type FeedItem struct {
+	Sale    *flashsale.Sale
+	Product *product.Product
+}
+
+// CreateSale schedules a flash sale for one of the seller's products
+func (s *Service) CreateSale(ctx context.Context, sellerID uuid.UUID, sale *flashsale.Sale) (*flashsale.Sale, error) {
+	p, err := s.sellerProduct(ctx, sellerID, sale.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if err := sale.Validate(p.BasePrice); err != nil {
+		return nil, invalidSaleError(err)
+	}
+
+	now := time.Now()
+	if !sale.EndsAt.After(now) {
+		return nil, appErrors.New(appErrors.ErrValidation, "flash sale must end in the future")
+	}
+
+	sale.ID = uuid.New()
+	sale.SellerID = sellerID
+	sale.SoldCount = 0
+	sale.CreatedAt = now
+	sale.UpdatedAt = now
+
+	if err := s.repo.Create(ctx, sale); err != nil {
+		return nil, saveError(err, "failed to create flash sale")
+	}
+	return sale, nil
+}
+
+// UpdateSale changes a sale's price, quantities and window before it starts
+func (s *Service) UpdateSale(ctx context.Context, sellerID, saleID uuid.UUID, update *flashsale.Sale) (*flashsale.Sale, error) {
+	sale, err := s.sellerSale(ctx, sellerID, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if sale.HasStarted(now) {
+		return nil, appErrors.Wrap(flashsale.ErrSaleStarted, appErrors.ErrConflict, "flash sale has already started")
+	}
+
+	p, err := s.sellerProduct(ctx, sellerID, sale.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	sale.SalePrice = update.SalePrice
+	sale.Quantity = update.Quantity
+	sale.PerUserLimit = update.PerUserLimit
+	sale.StartsAt = update.StartsAt
+	sale.EndsAt = update.EndsAt
+	sale.UpdatedAt = now
+	if err := sale.Validate(p.BasePrice); err != nil {
+		return nil, invalidSaleError(err)
+	}
+
+	if err := s.repo.Update(ctx, sale); err != nil {
+		return nil, saveError(err, "failed to update flash sale")
+	}
+	return sale, nil
+}
+
+// DeleteSale removes a sale before it starts
+func (s *Service) DeleteSale(ctx context.Context, sellerID, saleID uuid.UUID) error {
+	sale, err := s.sellerSale(ctx, sellerID, saleID)
+	if err != nil {
+		return err
+	}
+	if sale.HasStarted(time.Now()) {
+		return appErrors.Wrap(flashsale.ErrSaleStarted, appErrors.ErrConflict, "flash sale has already started")
+	}
+
+	if err := s.repo.Delete(ctx, saleID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to delete flash sale")
+	}
+	return nil
+}
+
+// ListSellerSales lists the seller's flash sales
+func (s *Service) ListSellerSales(ctx context.Context, sellerID uuid.UUID) ([]*flashsale.Sale, error) {
+	sales, err := s.repo.ListBySeller(ctx, sellerID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list flash sales")
+	}
+	return sales, nil
+}
+
+// ActiveFeed lists the sales running now, ending soonest first. Sales whose
+// product is no longer available are left out.
+func (s *Service) ActiveFeed(ctx context.Context) ([]*FeedItem, error) {
+	sales, err := s.repo.ListActive(ctx, time.Now())
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list flash sales")
+	}
+
+	feed := make([]*FeedItem, 0, len(sales))
+	for _, sale := range sales {
+		p, err := s.productRepo.GetByID(ctx, sale.ProductID)
+		if err != nil {
+			if errors.Is(err, product.ErrProductNotFound) {
+				continue
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load product")
+		}
+		if !p.IsAvailable() {
+			continue
+		}
+		feed = append(feed, &FeedItem{Sale: sale, Product: p})
+	}
+	return feed, nil
+}
+
+// ActiveForProducts returns the sale running now for each product that has one
+func (s *Service) ActiveForProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*flashsale.Sale, error) {
+	sales, err := s.repo.ActiveForProducts(ctx, productIDs, time.Now())
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load flash sales")
+	}
+	return sales, nil
+}
+
+// Claim takes the sale units an order's items buy at flash sale prices. Units
+// are first taken from the counter, so buyers beyond the sale's quantity are
+// turned away without touching the database.
+func (s *Service) Claim(ctx context.Context, userID uuid.UUID, o *order.Order, sales map[uuid.UUID]*flashsale.Sale) error {
+	var claims []*flashsale.Claim
+	release := func() {
+		for _, claim := range claims {
+			if err := s.counter.Release(ctx, claim.SaleID, claim.UserID, claim.Quantity); err != nil {
+				log.Printf("Failed to release flash sale %s units: %v", claim.SaleID, err)
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, item := range o.Items {
+		sale, ok := sales[item.ProductID]
+		if !ok {
+			continue
+		}
+
+		if err := s.reserve(ctx, sale, userID, item.Quantity); err != nil {
+			release()
+			return claimError(err)
+		}
+		claims = append(claims, &flashsale.Claim{
+			ID:        uuid.New(),
+			SaleID:    sale.ID,
+			UserID:    userID,
+			OrderID:   o.ID,
+			Quantity:  item.Quantity,
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.repo.Claim(ctx, claims); err != nil {
+		release()
+		return claimError(err)
+	}
+	return nil
+}
+
+// ReleaseOrder gives back the sale units of a cancelled order
+func (s *Service) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	claims, err := s.repo.ReleaseOrder(ctx, orderID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to release flash sale units")
+	}
+
+	for _, claim := range claims {
+		if err := s.counter.Release(ctx, claim.SaleID, claim.UserID, claim.Quantity); err != nil {
+			log.Printf("Failed to release flash sale %s units: %v", claim.SaleID, err)
+		}
+	}
+	return nil
+}
+
+// reserve takes units from the counter, rebuilding the per-buyer counts from
+// the recorded claims if they were lost, so an evicted count does not reset
+// buyers' limits
+func (s *Service) reserve(ctx context.Context, sale *flashsale.Sale, userID uuid.UUID, quantity int) error {
+	err := s.counter.Reserve(ctx, sale, userID, quantity)
+	if !errors.Is(err, flashsale.ErrBuyerCountsLost) {
+		return err
+	}
+
+	claimed, err := s.repo.ClaimedByBuyer(ctx, sale.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.counter.RestoreBuyers(ctx, sale, claimed); err != nil {
+		return err
+	}
+	log.Printf("Restored buyer counts of flash sale %s", sale.ID)
+	return s.counter.Reserve(ctx, sale, userID, quantity)
+}
+
+func (s *Service) sellerProduct(ctx context.Context, sellerID, productID uuid.UUID) (*product.Product, error) {
+	p, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, product.ErrProductNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "product not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load product")
+	}
+	if p.SellerID != sellerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "not your product")
+	}
+	return p, nil
+}
+
+func (s *Service) sellerSale(ctx context.Context, sellerID, saleID uuid.UUID) (*flashsale.Sale, error) {
+	sale, err := s.repo.GetByID(ctx, saleID)
+	if err != nil {
+		if errors.Is(err, flashsale.ErrSaleNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "flash sale not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get flash sale")
+	}
+	if sale.SellerID != sellerID {
+		return nil, appErrors.New(appErrors.ErrNotFound, "flash sale not found")
+	}
+	return sale, nil
+}
+
+func invalidSaleError(err error) error {
+	return appErrors.Wrap(err, appErrors.ErrValidation, "flash sale needs a price below the product's price, a positive quantity and per-user limit, and an end after its start")
+}
+
+func saveError(err error, message string) error {
+	if errors.Is(err, flashsale.ErrSaleOverlaps) {
+		return appErrors.Wrap(err, appErrors.ErrConflict, err.Error())
+	}
+	return appErrors.Wrap(err, appErrors.ErrInternal, message)
+}
+
+// claimError explains why flash sale units could not be taken
+func claimError(err error) error {
+	if errors.Is(err, flashsale.ErrSoldOut) || errors.Is(err, flashsale.ErrUserLimitReached) {
+		return appErrors.Wrap(err, appErrors.ErrConflict, err.Error())
+	}
+	return appErrors.Wrap(err, appErrors.ErrInternal, "failed to claim flash sale units")
+}
@@ -0,0 +1,284 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	flashsaleApp "github.com/blytz/live/backend/internal/application/flashsale"
+	"github.com/blytz/live/backend/internal/domain/inventory"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/payment"
+	"github.com/blytz/live/backend/internal/domain/promotion"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// expiryBatchSize limits how many expired reservations are released per sweep
+const expiryBatchSize = 100
+
+// Service manages stock reservations between cart, checkout and payment
+type Service struct {
+	repo       inventory.Repository
+	orderRepo  order.Repository
+	couponRepo promotion.Repository
+	payments   payment.Repository
+	flashSales *flashsaleApp.Service
+}
+
+// NewService creates a new inventory service
+func NewService(repo inventory.Repository, orderRepo order.Repository, couponRepo promotion.Repository, payments payment.Repository, flashSales *flashsaleApp.Service) *Service {
+	return &Service{
+		repo:       repo,
+		orderRepo:  orderRepo,
+		couponRepo: couponRepo,
+		payments:   payments,
+		flashSales: flashSales,
+	}
+}
+
+// ReserveOrder holds stock for every item of an unsaved order until its payment deadline.
+// Either all items are reserved or none are.
+func (s *Service) ReserveOrder(ctx context.Context, o *order.Order) error {
+	now := time.Now()
+	ttl := inventory.CheckoutHoldTTL
+	if o.PaymentDueAt != nil {
+		ttl = o.PaymentDueAt.Sub(now)
+	}
+
+	var reserved []*inventory.Reservation
+	for _, item := range o.Items {
+		r, err := inventory.NewReservation(item.ProductID, o.BuyerID, item.Quantity, ttl, now)
+		if err != nil {
+			s.releaseAll(ctx, reserved, "checkout failed")
+			return appErrors.Wrap(err, appErrors.ErrValidation, "invalid item quantity")
+		}
+		orderID := o.ID
+		r.OrderID = &orderID
+
+		if err := s.repo.Reserve(ctx, r); err != nil {
+			s.releaseAll(ctx, reserved, "checkout failed")
+			return reserveError(err, item.ProductID)
+		}
+		reserved = append(reserved, r)
+	}
+
+	return nil
+}
+
+// EnsureOrderReserved re-reserves stock for a pending order whose hold was released,
+// e.g. after a failed payment
+func (s *Service) EnsureOrderReserved(ctx context.Context, o *order.Order) error {
+	active, err := s.repo.ListActiveByOrder(ctx, o.ID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load reservations")
+	}
+	if len(active) > 0 {
+		return nil
+	}
+	return s.ReserveOrder(ctx, o)
+}
+
+// CommitOrder marks an order's held stock as sold
+func (s *Service) CommitOrder(ctx context.Context, orderID uuid.UUID) error {
+	reservations, err := s.repo.ListActiveByOrder(ctx, orderID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load reservations")
+	}
+
+	for _, r := range reservations {
+		if err := s.repo.Commit(ctx, r.ID); err != nil {
+			return appErrors.Wrap(err, appErrors.ErrInternal, "failed to commit reservation")
+		}
+	}
+	return nil
+}
+
+// ReleaseOrder returns an order's held stock
+func (s *Service) ReleaseOrder(ctx context.Context, orderID uuid.UUID, reason string) error {
+	reservations, err := s.repo.ListActiveByOrder(ctx, orderID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load reservations")
+	}
+	return s.releaseAll(ctx, reservations, reason)
+}
+
+// Restock returns sold units to available stock, e.g. after a refunded return
+func (s *Service) Restock(ctx context.Context, productID uuid.UUID, quantity int) error {
+	if quantity <= 0 {
+		return appErrors.New(appErrors.ErrValidation, "restock quantity must be greater than zero")
+	}
+	if err := s.repo.Restock(ctx, productID, quantity); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to restock product")
+	}
+	return nil
+}
+
+// ReserveAuction holds one unit of the product for the lifetime of an auction
+func (s *Service) ReserveAuction(ctx context.Context, productID, sellerID, auctionID uuid.UUID) error {
+	r, err := inventory.NewReservation(productID, sellerID, 1, 0, time.Now())
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrValidation, "invalid reservation")
+	}
+	r.AuctionID = &auctionID
+
+	if err := s.repo.Reserve(ctx, r); err != nil {
+		return reserveError(err, productID)
+	}
+	return nil
+}
+
+// ReleaseAuction returns the unit held by an auction that ended without a sale
+func (s *Service) ReleaseAuction(ctx context.Context, auctionID uuid.UUID, reason string) error {
+	r, err := s.repo.GetActiveByAuction(ctx, auctionID)
+	if err != nil {
+		if errors.Is(err, inventory.ErrReservationNotFound) {
+			return nil
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load reservation")
+	}
+
+	if err := s.repo.Release(ctx, r.ID, reason); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to release reservation")
+	}
+	return nil
+}
+
+// TransferAuctionToOrder moves the unit held by an auction to the winner's order.
+// The hold does not expire; the order's payment deadline governs it.
+func (s *Service) TransferAuctionToOrder(ctx context.Context, auctionID, orderID uuid.UUID) error {
+	r, err := s.repo.GetActiveByAuction(ctx, auctionID)
+	if err != nil {
+		if errors.Is(err, inventory.ErrReservationNotFound) {
+			return nil
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load reservation")
+	}
+
+	if err := s.repo.Assign(ctx, r.ID, orderID, nil); err != nil && !errors.Is(err, inventory.ErrReservationNotFound) {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to transfer reservation")
+	}
+	return nil
+}
+
+// ReleaseExpired releases expired holds and cancels the pending orders they belonged to
+func (s *Service) ReleaseExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	expired, err := s.repo.ListExpired(ctx, now, expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, r := range expired {
+		if r.OrderID != nil && s.isBeingPaid(ctx, *r.OrderID, now) {
+			// Keep the stock while the buyer's payment goes through
+			extended := now.Add(inventory.CheckoutHoldTTL)
+			if err := s.repo.Assign(ctx, r.ID, *r.OrderID, &extended); err != nil {
+				log.Printf("Failed to extend reservation %s of order %s being paid: %v", r.ID, *r.OrderID, err)
+			}
+			continue
+		}
+
+		if err := s.repo.Release(ctx, r.ID, "expired"); err != nil {
+			log.Printf("Failed to release reservation %s: %v", r.ID, err)
+			continue
+		}
+		released++
+
+		if r.OrderID != nil {
+			s.cancelExpiredOrder(ctx, *r.OrderID, now)
+		}
+	}
+	return released, nil
+}
+
+// RunExpiry releases expired reservations periodically until ctx is cancelled
+func (s *Service) RunExpiry(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			released, err := s.ReleaseExpired(ctx)
+			if err != nil {
+				log.Printf("Failed to release expired reservations: %v", err)
+				continue
+			}
+			if released > 0 {
+				log.Printf("Released %d expired stock reservations", released)
+			}
+		}
+	}
+}
+
+func (s *Service) cancelExpiredOrder(ctx context.Context, orderID uuid.UUID, now time.Time) {
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil || o.Status != order.StatusPending || s.isBeingPaid(ctx, orderID, now) {
+		return
+	}
+
+	if err := o.Cancel("payment window expired", now); err != nil {
+		return
+	}
+	if err := s.orderRepo.TransitionStatus(ctx, o, order.StatusPending); err != nil {
+		if !errors.Is(err, order.ErrStatusConflict) {
+			log.Printf("Failed to cancel expired order %s: %v", orderID, err)
+		}
+		return
+	}
+
+	// Release any other holds of the same order now that it is cancelled
+	s.ReleaseOrder(ctx, orderID, "expired")
+
+	if o.CouponCode != "" {
+		if err := s.couponRepo.ReleaseOrder(ctx, orderID); err != nil {
+			log.Printf("Failed to release coupon of expired order %s: %v", orderID, err)
+		}
+	}
+	if err := s.flashSales.ReleaseOrder(ctx, orderID); err != nil {
+		log.Printf("Failed to release flash sale units of expired order %s: %v", orderID, err)
+	}
+}
+
+// isBeingPaid reports whether an order has a payment that has taken or may
+// still take the buyer's money. Lookup failures count as being paid so that
+// the order is left for the next sweep.
+func (s *Service) isBeingPaid(ctx context.Context, orderID uuid.UUID, now time.Time) bool {
+	payments, err := s.payments.ListByOrder(ctx, orderID)
+	if err != nil {
+		log.Printf("Failed to list payments of order %s: %v", orderID, err)
+		return true
+	}
+	for _, p := range payments {
+		if p.IsLive(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) releaseAll(ctx context.Context, reservations []*inventory.Reservation, reason string) error {
+	var firstErr error
+	for _, r := range reservations {
+		if err := s.repo.Release(ctx, r.ID, reason); err != nil {
+			log.Printf("Failed to release reservation %s: %v", r.ID, err)
+			if firstErr == nil {
+				firstErr = appErrors.Wrap(err, appErrors.ErrInternal, "failed to release reservation")
+			}
+		}
+	}
+	return firstErr
+}
+
+func reserveError(err error, productID uuid.UUID) error {
+	if errors.Is(err, inventory.ErrInsufficientStock) {
+		return appErrors.New(appErrors.ErrConflict, "not enough stock").
+			WithDetails("product_id", productID.String())
+	}
+	return appErrors.Wrap(err, appErrors.ErrInternal, "failed to reserve stock")
+}
@@ -0,0 +1,215 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/invoice"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/blytz/live/backend/internal/infrastructure/storage/r2"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// exportWindowLimit caps the period one accounting export may cover
+const exportWindowLimit = 366 * 24 * time.Hour
+
+// Service issues, renders and exports invoices for paid orders
+type Service struct {
+	repo        invoice.Repository
+	orderRepo   order.Repository
+	userRepo    user.Repository
+	productRepo product.Repository
+	renderer    invoice.Renderer
+	storage     *r2.Client
+}
+
+// NewService creates a new invoice service
+func NewService(repo invoice.Repository, orderRepo order.Repository, userRepo user.Repository, productRepo product.Repository, renderer invoice.Renderer, storage *r2.Client) *Service {
+	return &Service{
+		repo:        repo,
+		orderRepo:   orderRepo,
+		userRepo:    userRepo,
+		productRepo: productRepo,
+		renderer:    renderer,
+		storage:     storage,
+	}
+}
+
+// Document is a rendered invoice
+type Document struct {
+	Invoice     *invoice.Invoice
+	ContentType string
+	Data        []byte
+}
+
+// IssueForOrder issues the invoice of a paid order and stores its rendered
+// document. An order that already has an invoice keeps it.
+func (s *Service) IssueForOrder(ctx context.Context, o *order.Order) (*invoice.Invoice, error) {
+	if !invoice.IsInvoiceable(o) {
+		return nil, appErrors.Wrap(invoice.ErrNotInvoiceable, appErrors.ErrConflict, "order has not been paid")
+	}
+
+	seller, err := s.party(ctx, o.SellerID)
+	if err != nil {
+		return nil, err
+	}
+	buyer, err := s.party(ctx, o.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := invoice.NewInvoice(o, seller, buyer, s.describe(ctx), time.Now())
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrConflict, "order has not been paid")
+	}
+
+	if err := s.repo.Create(ctx, inv); err != nil {
+		if errors.Is(err, invoice.ErrInvoiceExists) {
+			return s.getByOrder(ctx, o.ID)
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create invoice")
+	}
+
+	if _, err := s.store(ctx, inv); err != nil {
+		log.Printf("Failed to store invoice %s: %v", inv.Number, err)
+	}
+	return inv, nil
+}
+
+// GetInvoice returns an order's invoice to its buyer or seller, issuing it
+// first for orders paid before invoicing was in place
+func (s *Service) GetInvoice(ctx context.Context, orderID, userID uuid.UUID) (*invoice.Invoice, error) {
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, order.ErrOrderNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get order")
+	}
+	if !o.IsParticipant(userID) {
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+
+	inv, err := s.repo.GetByOrderID(ctx, orderID)
+	if err == nil {
+		return inv, nil
+	}
+	if !errors.Is(err, invoice.ErrInvoiceNotFound) {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get invoice")
+	}
+	return s.IssueForOrder(ctx, o)
+}
+
+// GetDocument returns an order's rendered invoice to its buyer or seller.
+// Documents missing from storage are rendered again.
+func (s *Service) GetDocument(ctx context.Context, orderID, userID uuid.UUID) (*Document, error) {
+	inv, err := s.GetInvoice(ctx, orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.DocumentKey != "" {
+		data, err := s.fetch(ctx, inv.DocumentKey)
+		if err == nil {
+			return &Document{Invoice: inv, ContentType: s.renderer.ContentType(), Data: data}, nil
+		}
+		log.Printf("Failed to fetch invoice %s, rendering again: %v", inv.Number, err)
+	}
+
+	data, err := s.store(ctx, inv)
+	if err != nil {
+		log.Printf("Failed to store invoice %s: %v", inv.Number, err)
+		if data == nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to render invoice")
+		}
+	}
+	return &Document{Invoice: inv, ContentType: s.renderer.ContentType(), Data: data}, nil
+}
+
+// ExportSellerInvoices lists a seller's invoices issued in [from, to) for
+// accounting tools
+func (s *Service) ExportSellerInvoices(ctx context.Context, sellerID uuid.UUID, from, to time.Time) ([]*invoice.Invoice, error) {
+	if !to.After(from) {
+		return nil, appErrors.New(appErrors.ErrValidation, "export period must end after it starts")
+	}
+	if to.Sub(from) > exportWindowLimit {
+		return nil, appErrors.New(appErrors.ErrValidation, "export period cannot exceed one year")
+	}
+
+	invoices, err := s.repo.ListBySeller(ctx, sellerID, from, to)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list invoices")
+	}
+	return invoices, nil
+}
+
+func (s *Service) getByOrder(ctx context.Context, orderID uuid.UUID) (*invoice.Invoice, error) {
+	inv, err := s.repo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get invoice")
+	}
+	return inv, nil
+}
+
+// store renders an invoice and uploads it, recording its storage key. The
+// rendered bytes are returned even if the upload fails.
+func (s *Service) store(ctx context.Context, inv *invoice.Invoice) ([]byte, error) {
+	data, err := s.renderer.Render(inv)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.storage.UploadBytes(ctx, data, inv.Number+".pdf", r2.UploadOptions{
+		ContentType: s.renderer.ContentType(),
+		Folder:      "invoices/" + inv.Seller.ID.String(),
+	})
+	if err != nil {
+		return data, err
+	}
+	if err := s.repo.SetDocument(ctx, inv.ID, result.Key); err != nil {
+		return data, err
+	}
+	inv.DocumentKey = result.Key
+	return data, nil
+}
+
+func (s *Service) fetch(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.storage.GetObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// party names a user on an invoice
+func (s *Service) party(ctx context.Context, userID uuid.UUID) (invoice.Party, error) {
+	u, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return invoice.Party{}, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load user")
+	}
+
+	name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+	if name == "" {
+		name = u.Email
+	}
+	return invoice.Party{ID: u.ID, Name: name, Email: u.Email}, nil
+}
+
+// describe returns a lookup of product names for invoice lines
+func (s *Service) describe(ctx context.Context) func(productID uuid.UUID) string {
+	return func(productID uuid.UUID) string {
+		p, err := s.productRepo.GetByID(ctx, productID)
+		if err != nil {
+			return "Item " + productID.String()[:8]
+		}
+		return p.Name
+	}
+}
@@ -0,0 +1,382 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/ledger"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/payment"
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/blytz/live/backend/internal/domain/user"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// payoutBatchSize limits how many sellers are paid per run
+const payoutBatchSize = 100
+
+// Config holds marketplace fee and payout settings
+type Config struct {
+	DefaultFeeBps int   // fee applied when no fee rule matches
+	MinimumPayout int64 // smallest balance paid out, in minor units
+}
+
+// Service records money movements between buyers, the platform and sellers
+type Service struct {
+	repo        ledger.Repository
+	productRepo product.Repository
+	userRepo    user.Repository
+	provider    ledger.PayoutProvider
+	config      Config
+}
+
+// NewService creates a new ledger service
+func NewService(repo ledger.Repository, productRepo product.Repository, userRepo user.Repository, provider ledger.PayoutProvider, config Config) *Service {
+	return &Service{
+		repo:        repo,
+		productRepo: productRepo,
+		userRepo:    userRepo,
+		provider:    provider,
+		config:      config,
+	}
+}
+
+// Balance summarizes a seller's accounts in minor units
+type Balance struct {
+	Pending   int64
+	Available int64
+	PaidOut   int64
+}
+
+// RecordOrderPayment books a paid order: the platform keeps its fee and the
+// rest is held for the seller until the order is delivered
+func (s *Service) RecordOrderPayment(ctx context.Context, o *order.Order) error {
+	fee, err := s.orderFee(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	total := payment.ToMinorUnits(o.TotalAmount)
+	t := ledger.NewTransaction(ledger.KindOrderPayment, o.ID, "Payment for order "+o.ID.String(), time.Now())
+	t.OrderID = &o.ID
+	t.Add(ledger.PlatformAccount(ledger.AccountBuyerPayments), -total)
+	t.Add(ledger.PlatformAccount(ledger.AccountPlatformFees), fee)
+	t.Add(ledger.SellerAccount(ledger.AccountSellerPending, o.SellerID), total-fee)
+
+	return s.post(ctx, t)
+}
+
+// ReleaseOrderFunds makes the seller's held share of a delivered order available for payout
+func (s *Service) ReleaseOrderFunds(ctx context.Context, o *order.Order) error {
+	pending, err := s.repo.OrderBalance(ctx, o.ID, ledger.SellerAccount(ledger.AccountSellerPending, o.SellerID))
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load order balance")
+	}
+	if pending <= 0 {
+		return nil
+	}
+
+	t := ledger.NewTransaction(ledger.KindOrderRelease, o.ID, "Funds released for order "+o.ID.String(), time.Now())
+	t.OrderID = &o.ID
+	t.Add(ledger.SellerAccount(ledger.AccountSellerPending, o.SellerID), -pending)
+	t.Add(ledger.SellerAccount(ledger.AccountSellerAvailable, o.SellerID), pending)
+
+	return s.post(ctx, t)
+}
+
+// RecordRefund books a refund. The platform returns its fee in proportion to the
+// refunded amount; the seller's share comes out of held funds first.
+func (s *Service) RecordRefund(ctx context.Context, o *order.Order, refund *payment.Refund) error {
+	sellerPending := ledger.SellerAccount(ledger.AccountSellerPending, o.SellerID)
+
+	fee, err := s.repo.OrderBalance(ctx, o.ID, ledger.PlatformAccount(ledger.AccountPlatformFees))
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load order balance")
+	}
+	pending, err := s.repo.OrderBalance(ctx, o.ID, sellerPending)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to load order balance")
+	}
+
+	payTx, err := s.repo.GetTransaction(ctx, ledger.KindOrderPayment, o.ID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "order payment not found in ledger")
+	}
+	var total, originalFee int64
+	for _, e := range payTx.Entries {
+		switch e.Account.Type {
+		case ledger.AccountBuyerPayments:
+			total = -e.Amount
+		case ledger.AccountPlatformFees:
+			originalFee = e.Amount
+		}
+	}
+
+	amount := payment.ToMinorUnits(refund.Amount)
+	feeRefund := int64(0)
+	if total > 0 {
+		feeRefund = amount * originalFee / total
+	}
+	if feeRefund > fee {
+		feeRefund = fee
+	}
+	sellerShare := amount - feeRefund
+	fromPending := sellerShare
+	if fromPending > pending {
+		fromPending = pending
+	}
+	if fromPending < 0 {
+		fromPending = 0
+	}
+
+	t := ledger.NewTransaction(ledger.KindRefund, refund.ID, "Refund for order "+o.ID.String(), time.Now())
+	t.OrderID = &o.ID
+	t.Add(ledger.PlatformAccount(ledger.AccountBuyerPayments), amount)
+	t.Add(ledger.PlatformAccount(ledger.AccountPlatformFees), -feeRefund)
+	t.Add(sellerPending, -fromPending)
+	t.Add(ledger.SellerAccount(ledger.AccountSellerAvailable, o.SellerID), -(sellerShare - fromPending))
+
+	return s.post(ctx, t)
+}
+
+// GetSellerBalance returns a seller's pending, available and paid out totals
+func (s *Service) GetSellerBalance(ctx context.Context, sellerID uuid.UUID) (*Balance, error) {
+	b := &Balance{}
+	for accountType, dst := range map[ledger.AccountType]*int64{
+		ledger.AccountSellerPending:   &b.Pending,
+		ledger.AccountSellerAvailable: &b.Available,
+		ledger.AccountPayouts:         &b.PaidOut,
+	} {
+		account, err := s.repo.GetAccount(ctx, ledger.SellerAccount(accountType, sellerID))
+		if err != nil {
+			if errors.Is(err, ledger.ErrAccountNotFound) {
+				continue
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load balance")
+		}
+		*dst = account.Balance
+	}
+	return b, nil
+}
+
+// ListSellerTransactions lists the ledger entries of a seller's accounts
+func (s *Service) ListSellerTransactions(ctx context.Context, sellerID uuid.UUID, page, pageSize int) ([]*ledger.StatementLine, int64, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	lines, total, err := s.repo.ListStatement(ctx, sellerID, page, pageSize)
+	if err != nil {
+		return nil, 0, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list transactions")
+	}
+	return lines, total, nil
+}
+
+// ListSellerPayouts lists a seller's payouts
+func (s *Service) ListSellerPayouts(ctx context.Context, sellerID uuid.UUID, page, pageSize int) ([]*ledger.Payout, int64, error) {
+	page, pageSize = normalizePage(page, pageSize)
+	payouts, total, err := s.repo.ListPayouts(ctx, sellerID, page, pageSize)
+	if err != nil {
+		return nil, 0, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payouts")
+	}
+	return payouts, total, nil
+}
+
+// ProcessPayouts pays out every seller whose available balance reaches the minimum
+func (s *Service) ProcessPayouts(ctx context.Context) (int, error) {
+	accounts, err := s.repo.ListPayableAccounts(ctx, s.config.MinimumPayout, payoutBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	paid := 0
+	for _, account := range accounts {
+		if account.OwnerID == nil {
+			continue
+		}
+		if err := s.payout(ctx, *account.OwnerID, account.Balance, account.Currency); err != nil {
+			log.Printf("Failed to pay out seller %s: %v", *account.OwnerID, err)
+			continue
+		}
+		paid++
+	}
+	return paid, nil
+}
+
+// RunPayouts processes payouts periodically until ctx is cancelled
+func (s *Service) RunPayouts(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			paid, err := s.ProcessPayouts(ctx)
+			if err != nil {
+				log.Printf("Failed to process payouts: %v", err)
+				continue
+			}
+			if paid > 0 {
+				log.Printf("Paid out %d sellers", paid)
+			}
+		}
+	}
+}
+
+// ListFeeRules lists the configured fee rules
+func (s *Service) ListFeeRules(ctx context.Context) ([]*ledger.FeeRule, error) {
+	rules, err := s.repo.ListFeeRules(ctx)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list fee rules")
+	}
+	return rules, nil
+}
+
+// CreateFeeRule adds a fee rule for a category and/or seller tier
+func (s *Service) CreateFeeRule(ctx context.Context, rule *ledger.FeeRule) (*ledger.FeeRule, error) {
+	if rule.PercentBps < 0 || rule.PercentBps > 10000 {
+		return nil, appErrors.New(appErrors.ErrValidation, "percent_bps must be between 0 and 10000")
+	}
+	if rule.FixedAmount < 0 {
+		return nil, appErrors.New(appErrors.ErrValidation, "fixed amount cannot be negative")
+	}
+
+	rules, err := s.ListFeeRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range rules {
+		if sameScope(existing, rule) {
+			return nil, appErrors.New(appErrors.ErrConflict, "a fee rule already exists for this category and tier")
+		}
+	}
+
+	rule.ID = uuid.New()
+	rule.CreatedAt = time.Now()
+	if err := s.repo.CreateFeeRule(ctx, rule); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create fee rule")
+	}
+	return rule, nil
+}
+
+// DeleteFeeRule removes a fee rule
+func (s *Service) DeleteFeeRule(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.DeleteFeeRule(ctx, id); err != nil {
+		if errors.Is(err, ledger.ErrFeeRuleNotFound) {
+			return appErrors.New(appErrors.ErrNotFound, "fee rule not found")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to delete fee rule")
+	}
+	return nil
+}
+
+// payout moves a seller's available balance out and sends it through the provider.
+// A failed transfer is reversed so the balance is retried on the next run.
+func (s *Service) payout(ctx context.Context, sellerID uuid.UUID, amount int64, currency string) error {
+	now := time.Now()
+	p := ledger.NewPayout(sellerID, amount, currency, s.provider.Name(), now)
+
+	t := ledger.NewTransaction(ledger.KindPayout, p.ID, "Payout "+p.ID.String(), now)
+	t.Add(ledger.SellerAccount(ledger.AccountSellerAvailable, sellerID), -amount)
+	t.Add(ledger.SellerAccount(ledger.AccountPayouts, sellerID), amount)
+
+	if err := s.repo.CreatePayout(ctx, p, t); err != nil {
+		if errors.Is(err, ledger.ErrInsufficientBalance) {
+			// Another instance paid this balance out first
+			return nil
+		}
+		return err
+	}
+
+	reference, err := s.provider.Send(ctx, ledger.PayoutRequest{
+		PayoutID:       p.ID,
+		SellerID:       sellerID,
+		Amount:         amount,
+		Currency:       currency,
+		IdempotencyKey: p.ID.String(),
+	})
+	if err != nil {
+		p.MarkFailed(err.Error(), time.Now())
+		if updateErr := s.repo.UpdatePayout(ctx, p); updateErr != nil {
+			log.Printf("Failed to mark payout %s failed: %v", p.ID, updateErr)
+		}
+
+		reversal := ledger.NewTransaction(ledger.KindPayoutReversal, p.ID, "Reversal of failed payout "+p.ID.String(), time.Now())
+		reversal.Add(ledger.SellerAccount(ledger.AccountPayouts, sellerID), -amount)
+		reversal.Add(ledger.SellerAccount(ledger.AccountSellerAvailable, sellerID), amount)
+		if postErr := s.repo.Post(ctx, reversal); postErr != nil {
+			log.Printf("Failed to reverse payout %s: %v", p.ID, postErr)
+		}
+		return fmt.Errorf("payout provider: %w", err)
+	}
+
+	p.MarkPaid(reference, time.Now())
+	return s.repo.UpdatePayout(ctx, p)
+}
+
+// orderFee computes the platform fee of an order from its items' fee rules
+func (s *Service) orderFee(ctx context.Context, o *order.Order) (int64, error) {
+	rules, err := s.repo.ListFeeRules(ctx)
+	if err != nil {
+		return 0, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load fee rules")
+	}
+
+	tier := user.DefaultSellerTier
+	if seller, err := s.userRepo.GetByID(ctx, o.SellerID); err == nil && seller.SellerTier != "" {
+		tier = seller.SellerTier
+	}
+
+	var fee int64
+	for _, item := range o.Items {
+		var categoryID *uuid.UUID
+		if p, err := s.productRepo.GetByID(ctx, item.ProductID); err == nil {
+			categoryID = p.CategoryID
+		}
+
+		rule := ledger.SelectFeeRule(rules, categoryID, tier)
+		if rule == nil {
+			rule = &ledger.FeeRule{PercentBps: s.config.DefaultFeeBps}
+		}
+		fee += rule.Fee(payment.ToMinorUnits(item.Total))
+	}
+
+	total := payment.ToMinorUnits(o.TotalAmount)
+	if fee > total {
+		fee = total
+	}
+	return fee, nil
+}
+
+// post posts a transaction, treating an already posted one as success
+func (s *Service) post(ctx context.Context, t *ledger.Transaction) error {
+	if err := s.repo.Post(ctx, t); err != nil {
+		if errors.Is(err, ledger.ErrDuplicateTransaction) {
+			return nil
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to post ledger transaction")
+	}
+	return nil
+}
+
+func sameScope(a, b *ledger.FeeRule) bool {
+	if (a.CategoryID == nil) != (b.CategoryID == nil) {
+		return false
+	}
+	if a.CategoryID != nil && *a.CategoryID != *b.CategoryID {
+		return false
+	}
+	return a.SellerTier == b.SellerTier
+}
+
+func normalizePage(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
@@ -0,0 +1,428 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	depositApp "github.com/blytz/live/backend/internal/application/deposit"
+	flashsaleApp "github.com/blytz/live/backend/internal/application/flashsale"
+	inventoryApp "github.com/blytz/live/backend/internal/application/inventory"
+	ledgerApp "github.com/blytz/live/backend/internal/application/ledger"
+	promotionApp "github.com/blytz/live/backend/internal/application/promotion"
+	shippingApp "github.com/blytz/live/backend/internal/application/shipping"
+	"github.com/blytz/live/backend/internal/domain/auction"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/blytz/live/backend/internal/domain/shipping"
+	"github.com/blytz/live/backend/internal/domain/user"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// overdueBatchSize limits how many unpaid auction orders are cancelled per sweep
+const overdueBatchSize = 100
+
+// Service provides order use cases
+type Service struct {
+	repo        order.Repository
+	auctionRepo auction.Repository
+	productRepo product.Repository
+	inventory   *inventoryApp.Service
+	ledger      *ledgerApp.Service
+	shipping    *shippingApp.Service
+	taxes       order.TaxCalculator
+	promotions  *promotionApp.Service
+	flashSales  *flashsaleApp.Service
+	strikes     user.StrikeRepository
+	deposits    *depositApp.Service
+}
+
+// NewService creates a new order service
+func NewService(repo order.Repository, auctionRepo auction.Repository, productRepo product.Repository, inventory *inventoryApp.Service, ledger *ledgerApp.Service, shipping *shippingApp.Service, taxes order.TaxCalculator, promotions *promotionApp.Service, flashSales *flashsaleApp.Service, strikes user.StrikeRepository, deposits *depositApp.Service) *Service {
+	return &Service{
+		repo:        repo,
+		auctionRepo: auctionRepo,
+		productRepo: productRepo,
+		inventory:   inventory,
+		ledger:      ledger,
+		shipping:    shipping,
+		taxes:       taxes,
+		promotions:  promotions,
+		flashSales:  flashSales,
+		strikes:     strikes,
+		deposits:    deposits,
+	}
+}
+
+// ListResult represents a page of orders
+type ListResult struct {
+	Orders     []*order.Order
+	TotalCount int64
+	Page       int
+	PageSize   int
+}
+
+// CreateFromAuction creates the pending order for an auction winner.
+// It is idempotent: calling it again for the same auction returns the existing order.
+func (s *Service) CreateFromAuction(ctx context.Context, auctionID, winnerID uuid.UUID) (*order.Order, error) {
+	if existing, err := s.repo.GetByAuctionID(ctx, auctionID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, order.ErrOrderNotFound) {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to look up auction order")
+	}
+
+	a, err := s.auctionRepo.GetByID(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if a.WinnerID == nil || *a.WinnerID != winnerID {
+		return nil, appErrors.New(appErrors.ErrConflict, "user did not win this auction")
+	}
+
+	bids, err := s.auctionRepo.GetBidsByAuction(ctx, auctionID, 1, 0)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to load winning bid")
+	}
+	if len(bids) == 0 || bids[0].UserID != winnerID {
+		return nil, appErrors.New(appErrors.ErrConflict, "winning bid not found")
+	}
+
+	o := order.NewAuctionOrder(auctionID, winnerID, a.SellerID, a.ProductID, bids[0].Amount, time.Now())
+	if err := s.repo.Create(ctx, o); err != nil {
+		// A concurrent retry may have created it first
+		if existing, getErr := s.repo.GetByAuctionID(ctx, auctionID); getErr == nil {
+			return existing, nil
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create order")
+	}
+
+	// The unit held for the auction now belongs to the winner's order
+	if err := s.inventory.TransferAuctionToOrder(ctx, auctionID, o.ID); err != nil {
+		log.Printf("Failed to transfer stock hold of auction %s: %v", auctionID, err)
+	}
+
+	return o, nil
+}
+
+// GetOrder gets an order visible to the buyer or seller
+func (s *Service) GetOrder(ctx context.Context, orderID, userID uuid.UUID) (*order.Order, error) {
+	o, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !o.IsParticipant(userID) {
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+	return o, nil
+}
+
+// ListBuyerOrders lists a buyer's orders
+func (s *Service) ListBuyerOrders(ctx context.Context, buyerID uuid.UUID, status *order.Status, page, pageSize int) (*ListResult, error) {
+	return s.list(ctx, order.Filter{BuyerID: &buyerID, Status: status, Page: page, PageSize: pageSize})
+}
+
+// ListSellerOrders lists orders placed with a seller
+func (s *Service) ListSellerOrders(ctx context.Context, sellerID uuid.UUID, status *order.Status, page, pageSize int) (*ListResult, error) {
+	return s.list(ctx, order.Filter{SellerID: &sellerID, Status: status, Page: page, PageSize: pageSize})
+}
+
+// CancelOrder cancels an unpaid order on behalf of the buyer or seller
+func (s *Service) CancelOrder(ctx context.Context, orderID, userID uuid.UUID, reason string) (*order.Order, error) {
+	o, err := s.GetOrder(ctx, orderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := o.Status
+	if err := o.Cancel(reason, time.Now()); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrOrderNotCancellable, "order can no longer be cancelled")
+	}
+	if err := s.transition(ctx, o, from); err != nil {
+		return nil, err
+	}
+
+	if err := s.inventory.ReleaseOrder(ctx, o.ID, "order cancelled"); err != nil {
+		log.Printf("Failed to release stock of cancelled order %s: %v", o.ID, err)
+	}
+	if o.CouponCode != "" {
+		if err := s.promotions.ReleaseOrder(ctx, o.ID); err != nil {
+			log.Printf("Failed to release coupon of cancelled order %s: %v", o.ID, err)
+		}
+	}
+	if err := s.flashSales.ReleaseOrder(ctx, o.ID); err != nil {
+		log.Printf("Failed to release flash sale units of cancelled order %s: %v", o.ID, err)
+	}
+
+	// Backing out of a won auction counts the same as never paying for it
+	if o.AuctionID != nil && o.BuyerID == userID {
+		s.recordNonPayment(ctx, o, time.Now())
+	}
+	s.releaseDeposit(ctx, o)
+
+	return o, nil
+}
+
+// MarkPaid records a successful payment for an order
+func (s *Service) MarkPaid(ctx context.Context, orderID, paymentID uuid.UUID) (*order.Order, error) {
+	o, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := o.Status
+	if err := o.MarkPaid(paymentID, time.Now()); err != nil {
+		if errors.Is(err, order.ErrPaymentOverdue) {
+			return nil, appErrors.Wrap(err, appErrors.ErrPaymentFailed, "payment deadline has passed")
+		}
+		return nil, transitionError(err)
+	}
+
+	return o, s.transition(ctx, o, from)
+}
+
+// SetShippingAddress sets where an unpaid order ships, repricing its
+// shipping and tax. Auction orders are created without an address and must
+// have one set before they are paid.
+func (s *Service) SetShippingAddress(ctx context.Context, orderID, buyerID uuid.UUID, dest shippingApp.Destination) (*order.Order, error) {
+	o, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.BuyerID != buyerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "only the buyer can change the shipping address")
+	}
+	if o.Status != order.StatusPending {
+		return nil, appErrors.Wrap(order.ErrShippingLocked, appErrors.ErrConflict, "shipping can no longer be changed")
+	}
+
+	addr, err := s.shipping.ResolveDestination(ctx, buyerID, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	parcels := make([]shipping.Parcel, 0, len(o.Items))
+	categories := make(map[uuid.UUID]*uuid.UUID)
+	for _, item := range o.Items {
+		p, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get product")
+		}
+		parcels = append(parcels, shippingApp.ParcelOf(p, item.Quantity))
+		categories[item.ProductID] = p.CategoryID
+	}
+
+	quote, err := s.shipping.Quote(ctx, o.SellerID, parcels, o.Subtotal, addr)
+	if err != nil {
+		return nil, err
+	}
+	o.SetShipping(addr, quote.Cost)
+
+	taxQuote, err := s.taxes.Calculate(ctx, order.TaxRequest{
+		Destination: addr,
+		Lines:       o.TaxLines(func(productID uuid.UUID) *uuid.UUID { return categories[productID] }),
+	})
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to calculate tax")
+	}
+	o.ApplyTax(taxQuote)
+	o.UpdatedAt = time.Now()
+
+	if err := s.repo.Reprice(ctx, o); err != nil {
+		if errors.Is(err, order.ErrStatusConflict) {
+			return nil, appErrors.Wrap(err, appErrors.ErrConflict, "order was updated concurrently")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update order")
+	}
+	return o, nil
+}
+
+// MarkShipped marks an order as shipped by its seller with the carrier's tracking number
+func (s *Service) MarkShipped(ctx context.Context, orderID, sellerID uuid.UUID, carrier, trackingNumber string) (*order.Order, error) {
+	c, err := shipping.LookupCarrier(carrier)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation, "unknown carrier")
+	}
+
+	o, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.SellerID != sellerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "only the seller can ship this order")
+	}
+
+	from := o.Status
+	if err := o.Ship(c.Code, trackingNumber, time.Now()); err != nil {
+		return nil, transitionError(err)
+	}
+
+	return o, s.transition(ctx, o, from)
+}
+
+// MarkDelivered confirms delivery on behalf of the buyer
+func (s *Service) MarkDelivered(ctx context.Context, orderID, buyerID uuid.UUID) (*order.Order, error) {
+	o, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.BuyerID != buyerID {
+		return nil, appErrors.New(appErrors.ErrForbidden, "only the buyer can confirm delivery")
+	}
+
+	from := o.Status
+	if err := o.Deliver(time.Now()); err != nil {
+		return nil, transitionError(err)
+	}
+	if err := s.transition(ctx, o, from); err != nil {
+		return nil, err
+	}
+
+	// Delivery ends the holding period for the seller's share
+	if err := s.ledger.ReleaseOrderFunds(ctx, o); err != nil {
+		log.Printf("Failed to release funds of order %s: %v", o.ID, err)
+	}
+
+	return o, nil
+}
+
+// CancelUnpaidAuctionOrders cancels auction orders whose winner did not pay
+// within the deadline and its grace period, returns the item to stock and
+// records a strike on the buyer. It returns how many orders were cancelled.
+func (s *Service) CancelUnpaidAuctionOrders(ctx context.Context) (int, error) {
+	now := time.Now()
+	overdue, err := s.repo.ListOverdueAuctionOrders(ctx, now.Add(-order.AuctionPaymentGracePeriod), overdueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, o := range overdue {
+		if !o.IsPaymentOverdue(now) {
+			continue
+		}
+		if err := o.Cancel("winner did not pay", now); err != nil {
+			continue
+		}
+		// The winner may have paid since the order was listed
+		if err := s.repo.TransitionStatus(ctx, o, order.StatusPending); err != nil {
+			if !errors.Is(err, order.ErrStatusConflict) {
+				log.Printf("Failed to cancel unpaid auction order %s: %v", o.ID, err)
+			}
+			continue
+		}
+		cancelled++
+
+		if err := s.inventory.ReleaseOrder(ctx, o.ID, "winner did not pay"); err != nil {
+			log.Printf("Failed to release stock of unpaid auction order %s: %v", o.ID, err)
+		}
+		s.recordNonPayment(ctx, o, now)
+		s.releaseDeposit(ctx, o)
+	}
+	return cancelled, nil
+}
+
+// RunPaymentDeadlines cancels unpaid auction orders periodically until ctx is cancelled
+func (s *Service) RunPaymentDeadlines(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			cancelled, err := s.CancelUnpaidAuctionOrders(ctx)
+			if err != nil {
+				log.Printf("Failed to cancel unpaid auction orders: %v", err)
+				continue
+			}
+			if cancelled > 0 {
+				log.Printf("Cancelled %d unpaid auction orders", cancelled)
+			}
+		}
+	}
+}
+
+// recordNonPayment records a strike on the buyer of an auction order that
+// was cancelled without being paid
+func (s *Service) recordNonPayment(ctx context.Context, o *order.Order, now time.Time) {
+	orderID := o.ID
+	strike := &user.Strike{
+		ID:        uuid.New(),
+		UserID:    o.BuyerID,
+		Reason:    user.StrikeNonPayment,
+		OrderID:   &orderID,
+		AuctionID: o.AuctionID,
+		CreatedAt: now,
+	}
+	if err := s.strikes.Create(ctx, strike); err != nil {
+		log.Printf("Failed to record non-payment strike for user %s: %v", o.BuyerID, err)
+	}
+}
+
+// releaseDeposit releases the winner's deposit hold on the auction an order
+// was created from
+func (s *Service) releaseDeposit(ctx context.Context, o *order.Order) {
+	if o.AuctionID == nil {
+		return
+	}
+	if err := s.deposits.ReleaseHold(ctx, *o.AuctionID, o.BuyerID); err != nil {
+		log.Printf("Failed to release deposit of auction order %s: %v", o.ID, err)
+	}
+}
+
+func (s *Service) getOrder(ctx context.Context, orderID uuid.UUID) (*order.Order, error) {
+	o, err := s.repo.GetByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, order.ErrOrderNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get order")
+	}
+	return o, nil
+}
+
+// transition saves an order moved out of status from, failing with a
+// conflict if another request moved it first
+func (s *Service) transition(ctx context.Context, o *order.Order, from order.Status) error {
+	if err := s.repo.TransitionStatus(ctx, o, from); err != nil {
+		if errors.Is(err, order.ErrStatusConflict) {
+			return appErrors.Wrap(err, appErrors.ErrConflict, "order was updated concurrently")
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to update order")
+	}
+	return nil
+}
+
+func (s *Service) list(ctx context.Context, filter order.Filter) (*ListResult, error) {
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+
+	orders, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list orders")
+	}
+
+	return &ListResult{
+		Orders:     orders,
+		TotalCount: total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+	}, nil
+}
+
+func transitionError(err error) error {
+	if errors.Is(err, order.ErrInvalidTransition) {
+		return appErrors.Wrap(err, appErrors.ErrConflict, "order is not in a valid state for this action")
+	}
+	return err
+}
@@ -0,0 +1,812 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	depositApp "github.com/blytz/live/backend/internal/application/deposit"
+	inventoryApp "github.com/blytz/live/backend/internal/application/inventory"
+	invoiceApp "github.com/blytz/live/backend/internal/application/invoice"
+	ledgerApp "github.com/blytz/live/backend/internal/application/ledger"
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/payment"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// errOrderNotPayable marks a payment returned because its order stopped
+// awaiting payment while it was being taken
+var errOrderNotPayable = errors.New("order is no longer awaiting payment")
+
+// Service provides payment use cases
+type Service struct {
+	repo       payment.Repository
+	methodRepo payment.MethodRepository
+	orderRepo  order.Repository
+	gateway    payment.Gateway
+	inventory  *inventoryApp.Service
+	ledger     *ledgerApp.Service
+	invoices   *invoiceApp.Service
+	events     order.EventPublisher
+	deposits   *depositApp.Service
+}
+
+// NewService creates a new payment service
+func NewService(repo payment.Repository, methodRepo payment.MethodRepository, orderRepo order.Repository, gateway payment.Gateway, inventory *inventoryApp.Service, ledger *ledgerApp.Service, invoices *invoiceApp.Service, events order.EventPublisher, deposits *depositApp.Service) *Service {
+	return &Service{
+		repo:       repo,
+		methodRepo: methodRepo,
+		orderRepo:  orderRepo,
+		gateway:    gateway,
+		inventory:  inventory,
+		ledger:     ledger,
+		invoices:   invoices,
+		events:     events,
+		deposits:   deposits,
+	}
+}
+
+// PayOrderRequest represents a request to pay for an order. Either a
+// one-off MethodRef or a saved MethodID may be given; with neither, the
+// buyer's default saved method is charged.
+type PayOrderRequest struct {
+	OrderID        uuid.UUID
+	BuyerID        uuid.UUID
+	MethodRef      string
+	MethodID       *uuid.UUID
+	IdempotencyKey string
+}
+
+// PayOrder authorizes and immediately captures the order total, marking the order as paid
+func (s *Service) PayOrder(ctx context.Context, req PayOrderRequest) (*payment.Payment, error) {
+	p, err := s.AuthorizeOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status == payment.StatusCaptured {
+		return p, nil
+	}
+	return s.CapturePayment(ctx, p.ID)
+}
+
+// AuthorizeOrder places a hold for the order total on the buyer's payment method
+func (s *Service) AuthorizeOrder(ctx context.Context, req PayOrderRequest) (*payment.Payment, error) {
+	if req.IdempotencyKey != "" {
+		existing, err := s.repo.GetByTransactionID(ctx, req.IdempotencyKey)
+		if err == nil {
+			if existing.OrderID != req.OrderID || existing.UserID != req.BuyerID {
+				return nil, appErrors.New(appErrors.ErrConflict, "idempotency key already used")
+			}
+			return existing, nil
+		}
+		if !errors.Is(err, payment.ErrPaymentNotFound) {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to look up payment")
+		}
+	} else {
+		req.IdempotencyKey = uuid.New().String()
+	}
+
+	o, err := s.payableOrder(ctx, req.OrderID, req.BuyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checkout orders whose hold was released by an earlier failed attempt need stock again
+	if o.AuctionID == nil {
+		if err := s.inventory.EnsureOrderReserved(ctx, o); err != nil {
+			return nil, err
+		}
+	}
+
+	methodRef, customerRef, err := s.chargeableMethod(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p := payment.NewPayment(o.ID, req.BuyerID, o.TotalAmount, payment.DefaultCurrency, s.gateway.Name(), "card", req.IdempotencyKey)
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to record payment")
+	}
+
+	result, err := s.gateway.Authorize(ctx, payment.AuthorizeRequest{
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		MethodRef:      methodRef,
+		CustomerRef:    customerRef,
+		IdempotencyKey: p.TransactionID,
+		Description:    "Order " + o.ID.String(),
+		Metadata: map[string]string{
+			"order_id":   o.ID.String(),
+			"payment_id": p.ID.String(),
+		},
+	})
+	if err != nil {
+		p.MarkFailed(err.Error())
+		s.save(ctx, p)
+		s.releaseStock(ctx, o)
+		return nil, gatewayError(err)
+	}
+
+	if err := p.MarkAuthorized(result.Reference); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to authorize payment")
+	}
+	if err := s.save(ctx, p); err != nil {
+		return nil, err
+	}
+
+	// The order may have been cancelled while the provider was authorizing
+	if err := s.ensurePayable(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// CapturePayment captures an authorized payment and marks its order as paid.
+// If the capture fails the authorization is released.
+func (s *Service) CapturePayment(ctx context.Context, paymentID uuid.UUID) (*payment.Payment, error) {
+	p, err := s.getPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status == payment.StatusCaptured {
+		return p, nil
+	}
+	if p.Status != payment.StatusAuthorized {
+		return nil, appErrors.New(appErrors.ErrConflict, "payment is not authorized")
+	}
+	if err := s.ensurePayable(ctx, p); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.gateway.Capture(ctx, p.GatewayReference, p.Amount); err != nil {
+		if voidErr := s.gateway.Void(ctx, p.GatewayReference); voidErr != nil {
+			log.Printf("Failed to void payment %s after capture failure: %v", p.ID, voidErr)
+		}
+		p.MarkFailed(err.Error())
+		s.save(ctx, p)
+		if o, getErr := s.orderRepo.GetByID(ctx, p.OrderID); getErr == nil {
+			s.releaseStock(ctx, o)
+		}
+		return nil, gatewayError(err)
+	}
+
+	if err := p.MarkCaptured(); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to capture payment")
+	}
+	if err := s.save(ctx, p); err != nil {
+		return nil, err
+	}
+
+	if err := s.markOrderPaid(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// VoidPayment releases an authorized payment
+func (s *Service) VoidPayment(ctx context.Context, paymentID uuid.UUID) (*payment.Payment, error) {
+	p, err := s.getPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status == payment.StatusVoided {
+		return p, nil
+	}
+
+	if err := p.MarkVoided(); err != nil {
+		return nil, appErrors.New(appErrors.ErrConflict, "payment is not authorized")
+	}
+	if err := s.gateway.Void(ctx, p.GatewayReference); err != nil {
+		return nil, gatewayError(err)
+	}
+
+	return p, s.save(ctx, p)
+}
+
+// ListOrderPayments lists the payments of an order visible to its buyer or seller
+func (s *Service) ListOrderPayments(ctx context.Context, orderID, userID uuid.UUID) ([]*payment.Payment, error) {
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	if !o.IsParticipant(userID) {
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+
+	payments, err := s.repo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payments")
+	}
+	return payments, nil
+}
+
+// RefundOrderRequest represents a request to refund an order
+type RefundOrderRequest struct {
+	OrderID        uuid.UUID
+	ActorID        uuid.UUID
+	IsAdmin        bool
+	Amount         float64 // zero refunds everything not yet refunded
+	Reason         string
+	Restock        []payment.RestockItem
+	IdempotencyKey string
+}
+
+// RefundOrder returns all or part of an order's captured payment to the buyer.
+// Sellers may refund their own orders; admins may refund any order.
+func (s *Service) RefundOrder(ctx context.Context, req RefundOrderRequest) (*payment.Refund, error) {
+	if req.Reason == "" {
+		return nil, appErrors.New(appErrors.ErrValidation, "refund reason is required")
+	}
+
+	if req.IdempotencyKey != "" {
+		existing, err := s.repo.GetRefundByIdempotencyKey(ctx, req.IdempotencyKey)
+		if err == nil {
+			if existing.OrderID != req.OrderID {
+				return nil, appErrors.New(appErrors.ErrConflict, "idempotency key already used")
+			}
+			if existing.Status == payment.RefundPending {
+				return nil, appErrors.New(appErrors.ErrConflict, "refund is still being processed")
+			}
+			return existing, nil
+		}
+		if !errors.Is(err, payment.ErrRefundNotFound) {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to look up refund")
+		}
+	} else {
+		req.IdempotencyKey = uuid.New().String()
+	}
+
+	o, err := s.orderRepo.GetByID(ctx, req.OrderID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	if !req.IsAdmin && o.SellerID != req.ActorID {
+		if o.BuyerID == req.ActorID {
+			return nil, appErrors.New(appErrors.ErrForbidden, "only the seller can refund this order")
+		}
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+	if o.PaymentID == nil {
+		return nil, appErrors.New(appErrors.ErrConflict, "order has no captured payment")
+	}
+
+	p, err := s.getPayment(ctx, *o.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = p.Refundable()
+	}
+	if err := p.CanRefund(amount); err != nil {
+		if errors.Is(err, payment.ErrNotRefundable) {
+			return nil, appErrors.New(appErrors.ErrConflict, "payment has nothing left to refund")
+		}
+		return nil, appErrors.New(appErrors.ErrValidation, "refund exceeds captured amount").
+			WithDetails("refundable", p.Refundable())
+	}
+
+	if err := s.validateRestock(ctx, o, req.Restock); err != nil {
+		return nil, err
+	}
+
+	// Reserve the amount before asking the provider, so concurrent refunds
+	// cannot together return more than was captured
+	refund := payment.NewRefund(p, amount, req.Reason, req.ActorID, req.IdempotencyKey, time.Now())
+	refund.Restocked = req.Restock
+	p, err = s.repo.ReserveRefund(ctx, refund)
+	if err != nil {
+		if errors.Is(err, payment.ErrRefundExceedsCaptured) {
+			// A concurrent refund got there first
+			return nil, appErrors.New(appErrors.ErrConflict, "refund exceeds captured amount")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to record refund")
+	}
+
+	if err := s.sendRefund(ctx, p, refund); err != nil {
+		return nil, err
+	}
+
+	if err := s.ledger.RecordRefund(ctx, o, refund); err != nil {
+		log.Printf("Failed to book refund %s in ledger: %v", refund.ID, err)
+	}
+
+	for _, item := range refund.Restocked {
+		if err := s.inventory.Restock(ctx, item.ProductID, item.Quantity); err != nil {
+			log.Printf("Failed to restock product %s after refund %s: %v", item.ProductID, refund.ID, err)
+		}
+	}
+
+	if p.IsFullyRefunded() && o.CanTransition(order.StatusRefunded) {
+		from := o.Status
+		if err := o.MarkRefunded(time.Now()); err == nil {
+			if err := s.orderRepo.TransitionStatus(ctx, o, from); err != nil {
+				log.Printf("Failed to mark order %s refunded: %v", o.ID, err)
+			}
+		}
+	}
+
+	if err := s.events.PublishOrderRefunded(ctx, o, amount, req.Reason); err != nil {
+		log.Printf("Failed to publish refund of order %s: %v", o.ID, err)
+	}
+
+	return refund, nil
+}
+
+// ListOrderRefunds lists the refunds of an order visible to its buyer or seller
+func (s *Service) ListOrderRefunds(ctx context.Context, orderID, userID uuid.UUID) ([]*payment.Refund, error) {
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	if !o.IsParticipant(userID) {
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+
+	refunds, err := s.repo.ListRefundsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list refunds")
+	}
+	return refunds, nil
+}
+
+// HandleWebhook verifies and applies an asynchronous provider notification.
+// Unknown payments and event types are acknowledged and ignored.
+func (s *Service) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.gateway.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrUnknownPaymentEvent) {
+			return nil
+		}
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return appErrors.New(appErrors.ErrUnauthorized, "invalid webhook signature")
+		}
+		return appErrors.Wrap(err, appErrors.ErrValidation, "invalid webhook")
+	}
+
+	p, err := s.repo.GetByReference(ctx, event.Reference)
+	if err != nil {
+		if errors.Is(err, payment.ErrPaymentNotFound) {
+			return nil
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to look up payment")
+	}
+
+	switch event.Type {
+	case payment.WebhookAuthorized:
+		if p.Status != payment.StatusPending {
+			return nil
+		}
+		p.MarkAuthorized(event.Reference)
+		if err := s.save(ctx, p); err != nil {
+			return err
+		}
+		return ignoreUnpayable(s.ensurePayable(ctx, p))
+	case payment.WebhookCaptured:
+		if p.Status == payment.StatusCaptured {
+			return nil
+		}
+		if err := p.MarkCaptured(); err != nil {
+			return nil
+		}
+		if err := s.save(ctx, p); err != nil {
+			return err
+		}
+		return ignoreUnpayable(s.markOrderPaid(ctx, p))
+	case payment.WebhookFailed:
+		if p.Status != payment.StatusPending && p.Status != payment.StatusAuthorized {
+			return nil
+		}
+		p.MarkFailed(event.FailureReason)
+	case payment.WebhookVoided:
+		if p.MarkVoided() != nil {
+			return nil
+		}
+	case payment.WebhookRefunded:
+		if event.Amount <= p.RefundedAmount {
+			return nil
+		}
+		now := time.Now()
+		p.RefundedAmount = event.Amount
+		p.RefundedAt = &now
+		if p.RefundedAmount >= p.Amount {
+			p.Status = payment.StatusRefunded
+		} else {
+			p.Status = payment.StatusPartiallyRefunded
+		}
+	}
+
+	return s.save(ctx, p)
+}
+
+// AddPaymentMethod saves a client-side card token for reuse. The user's first
+// method becomes the default.
+func (s *Service) AddPaymentMethod(ctx context.Context, userID uuid.UUID, token string, makeDefault bool) (*payment.Method, error) {
+	if token == "" {
+		return nil, appErrors.New(appErrors.ErrValidation, "token is required")
+	}
+
+	existing, err := s.methodRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payment methods")
+	}
+
+	customerRef := ""
+	for _, m := range existing {
+		if m.Provider == s.gateway.Name() && m.CustomerRef != "" {
+			customerRef = m.CustomerRef
+			break
+		}
+	}
+
+	saved, err := s.gateway.SaveMethod(ctx, payment.SaveMethodRequest{
+		Token:       token,
+		CustomerRef: customerRef,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, gatewayError(err)
+	}
+
+	m := payment.NewMethod(userID, s.gateway.Name(), saved, time.Now())
+	if m.IsExpired(time.Now()) {
+		return nil, appErrors.New(appErrors.ErrValidation, "card has expired")
+	}
+	m.IsDefault = makeDefault || len(existing) == 0
+
+	if err := s.methodRepo.Create(ctx, m); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to save payment method")
+	}
+	return m, nil
+}
+
+// ListPaymentMethods lists a user's saved methods, default first
+func (s *Service) ListPaymentMethods(ctx context.Context, userID uuid.UUID) ([]*payment.Method, error) {
+	methods, err := s.methodRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payment methods")
+	}
+	return methods, nil
+}
+
+// SetDefaultPaymentMethod makes a saved method the user's default
+func (s *Service) SetDefaultPaymentMethod(ctx context.Context, userID, methodID uuid.UUID) (*payment.Method, error) {
+	m, err := s.userMethod(ctx, userID, methodID)
+	if err != nil {
+		return nil, err
+	}
+	if m.IsExpired(time.Now()) {
+		return nil, appErrors.New(appErrors.ErrValidation, "card has expired")
+	}
+
+	if err := s.methodRepo.SetDefault(ctx, userID, methodID); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to set default payment method")
+	}
+	m.IsDefault = true
+	return m, nil
+}
+
+// RemovePaymentMethod detaches a saved method at the provider and deletes it
+func (s *Service) RemovePaymentMethod(ctx context.Context, userID, methodID uuid.UUID) error {
+	m, err := s.userMethod(ctx, userID, methodID)
+	if err != nil {
+		return err
+	}
+
+	if m.Provider == s.gateway.Name() {
+		if err := s.gateway.RemoveMethod(ctx, m.MethodRef); err != nil {
+			log.Printf("Failed to detach payment method %s at provider: %v", m.ID, err)
+		}
+	}
+
+	if err := s.methodRepo.Delete(ctx, methodID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to remove payment method")
+	}
+	return nil
+}
+
+// chargeableMethod resolves the provider method and customer to charge for a payment
+func (s *Service) chargeableMethod(ctx context.Context, req PayOrderRequest) (string, string, error) {
+	if req.MethodRef != "" {
+		return req.MethodRef, "", nil
+	}
+
+	var m *payment.Method
+	if req.MethodID != nil {
+		found, err := s.userMethod(ctx, req.BuyerID, *req.MethodID)
+		if err != nil {
+			return "", "", err
+		}
+		m = found
+	} else {
+		methods, err := s.methodRepo.ListByUser(ctx, req.BuyerID)
+		if err != nil {
+			return "", "", appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payment methods")
+		}
+		if len(methods) == 0 || !methods[0].IsDefault {
+			return "", "", appErrors.New(appErrors.ErrValidation, "no payment method given and no default saved")
+		}
+		m = methods[0]
+	}
+
+	if m.Provider != s.gateway.Name() {
+		return "", "", appErrors.New(appErrors.ErrValidation, "payment method belongs to another provider")
+	}
+	if m.IsExpired(time.Now()) {
+		return "", "", appErrors.Wrap(payment.ErrMethodExpired, appErrors.ErrValidation, "card has expired")
+	}
+	return m.MethodRef, m.CustomerRef, nil
+}
+
+func (s *Service) userMethod(ctx context.Context, userID, methodID uuid.UUID) (*payment.Method, error) {
+	m, err := s.methodRepo.GetByID(ctx, methodID)
+	if err != nil {
+		if errors.Is(err, payment.ErrMethodNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "payment method not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get payment method")
+	}
+	if m.UserID != userID {
+		return nil, appErrors.New(appErrors.ErrNotFound, "payment method not found")
+	}
+	return m, nil
+}
+
+// payableOrder loads an order the buyer can still pay for
+func (s *Service) payableOrder(ctx context.Context, orderID, buyerID uuid.UUID) (*order.Order, error) {
+	o, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, orderError(err)
+	}
+	if o.BuyerID != buyerID {
+		return nil, appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+	if o.Status != order.StatusPending {
+		return nil, appErrors.New(appErrors.ErrConflict, "order is not awaiting payment")
+	}
+	if o.IsPaymentOverdue(time.Now()) {
+		return nil, appErrors.New(appErrors.ErrPaymentFailed, "payment deadline has passed")
+	}
+	if o.TotalAmount <= 0 {
+		return nil, appErrors.New(appErrors.ErrValidation, "order has nothing to pay")
+	}
+
+	payments, err := s.repo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list payments")
+	}
+	for _, p := range payments {
+		if p.IsSettled() {
+			return nil, appErrors.New(appErrors.ErrConflict, "order already has an active payment")
+		}
+	}
+
+	return o, nil
+}
+
+// markOrderPaid moves the order of a captured payment to paid. If the order
+// stopped awaiting payment in the meantime the capture is refunded.
+func (s *Service) markOrderPaid(ctx context.Context, p *payment.Payment) error {
+	o, err := s.orderRepo.GetByID(ctx, p.OrderID)
+	if err != nil {
+		return orderError(err)
+	}
+	if o.PaymentID != nil && *o.PaymentID == p.ID {
+		return nil
+	}
+	if o.Status != order.StatusPending {
+		return s.returnPayment(ctx, p, o)
+	}
+
+	// The money has been taken, so the deadline no longer applies
+	o.PaymentDueAt = nil
+	if err := o.MarkPaid(p.ID, time.Now()); err != nil {
+		return s.returnPayment(ctx, p, o)
+	}
+	if err := s.orderRepo.TransitionStatus(ctx, o, order.StatusPending); err != nil {
+		if errors.Is(err, order.ErrStatusConflict) {
+			// Another request moved the order first, perhaps marking it
+			// paid with this same payment
+			if o, err = s.orderRepo.GetByID(ctx, p.OrderID); err != nil {
+				return orderError(err)
+			}
+			if o.PaymentID != nil && *o.PaymentID == p.ID {
+				return nil
+			}
+			return s.returnPayment(ctx, p, o)
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to update order")
+	}
+
+	if err := s.inventory.CommitOrder(ctx, o.ID); err != nil {
+		log.Printf("Failed to commit stock of paid order %s: %v", o.ID, err)
+	}
+	if err := s.ledger.RecordOrderPayment(ctx, o); err != nil {
+		log.Printf("Failed to book payment of order %s in ledger: %v", o.ID, err)
+	}
+	if _, err := s.invoices.IssueForOrder(ctx, o); err != nil {
+		log.Printf("Failed to issue invoice for order %s: %v", o.ID, err)
+	}
+	if o.AuctionID != nil {
+		if err := s.deposits.ReleaseHold(ctx, *o.AuctionID, o.BuyerID); err != nil {
+			log.Printf("Failed to release deposit of auction order %s: %v", o.ID, err)
+		}
+	}
+	return nil
+}
+
+// ensurePayable checks that the order of an authorized payment still awaits
+// payment, voiding the authorization if it does not
+func (s *Service) ensurePayable(ctx context.Context, p *payment.Payment) error {
+	o, err := s.orderRepo.GetByID(ctx, p.OrderID)
+	if err != nil {
+		return orderError(err)
+	}
+	if o.Status == order.StatusPending && !o.IsPaymentOverdue(time.Now()) {
+		return nil
+	}
+	return s.returnPayment(ctx, p, o)
+}
+
+// returnPayment gives back a payment taken for an order that is no longer
+// awaiting it, such as one cancelled while the buyer was paying. An
+// authorization is voided and a capture refunded in full. The returned error
+// tells the payer why the payment did not go through.
+func (s *Service) returnPayment(ctx context.Context, p *payment.Payment, o *order.Order) error {
+	unpayable := appErrors.Wrap(errOrderNotPayable, appErrors.ErrConflict, "order is no longer awaiting payment; the payment was returned")
+
+	switch p.Status {
+	case payment.StatusAuthorized:
+		log.Printf("Voiding payment %s: order %s is %s", p.ID, o.ID, o.Status)
+		if err := s.gateway.Void(ctx, p.GatewayReference); err != nil {
+			log.Printf("Failed to void payment %s of order %s: %v", p.ID, o.ID, err)
+			return gatewayError(err)
+		}
+		p.MarkVoided()
+		if err := s.save(ctx, p); err != nil {
+			return err
+		}
+	case payment.StatusCaptured:
+		log.Printf("Refunding payment %s: order %s is %s", p.ID, o.ID, o.Status)
+		// A fixed key keeps retried webhooks from refunding twice
+		key := "order-not-payable:" + p.ID.String()
+		const reason = "order was no longer awaiting payment"
+		refund := payment.NewRefund(p, p.Amount, reason, uuid.Nil, key, time.Now())
+		if _, err := s.repo.ReserveRefund(ctx, refund); err != nil {
+			if errors.Is(err, payment.ErrRefundExceedsCaptured) {
+				// A retried webhook already refunded it
+				return unpayable
+			}
+			log.Printf("Failed to record refund of payment %s of order %s: %v", p.ID, o.ID, err)
+			return appErrors.Wrap(err, appErrors.ErrInternal, "failed to record refund")
+		}
+		if err := s.sendRefund(ctx, p, refund); err != nil {
+			log.Printf("Failed to refund payment %s of order %s: %v", p.ID, o.ID, err)
+			return err
+		}
+	}
+	return unpayable
+}
+
+// sendRefund asks the provider to make a reserved refund. The reservation is
+// released if the provider refuses, so the amount can be refunded again.
+func (s *Service) sendRefund(ctx context.Context, p *payment.Payment, refund *payment.Refund) error {
+	result, err := s.gateway.Refund(ctx, payment.RefundRequest{
+		Reference:      p.GatewayReference,
+		Amount:         refund.Amount,
+		Currency:       p.Currency,
+		Reason:         refund.Reason,
+		IdempotencyKey: refund.IdempotencyKey,
+	})
+	if err != nil {
+		if releaseErr := s.repo.ReleaseRefund(ctx, refund); releaseErr != nil {
+			log.Printf("Failed to release refund %s of payment %s: %v", refund.ID, p.ID, releaseErr)
+		}
+		return gatewayError(err)
+	}
+
+	refund.MarkSucceeded(result.Reference)
+	if err := s.repo.CompleteRefund(ctx, refund); err != nil {
+		// The amount stays reserved, so the payment totals are already right
+		log.Printf("Failed to mark refund %s of payment %s succeeded: %v", refund.ID, p.ID, err)
+	}
+	return nil
+}
+
+// ignoreUnpayable acknowledges a webhook whose payment was returned because
+// its order no longer awaited payment
+func ignoreUnpayable(err error) error {
+	if errors.Is(err, errOrderNotPayable) {
+		return nil
+	}
+	return err
+}
+
+// releaseStock returns the stock held by a checkout order after a failed payment.
+// Auction orders keep their unit until the payment deadline.
+func (s *Service) releaseStock(ctx context.Context, o *order.Order) {
+	if o.AuctionID != nil {
+		return
+	}
+	if err := s.inventory.ReleaseOrder(ctx, o.ID, "payment failed"); err != nil {
+		log.Printf("Failed to release stock of order %s: %v", o.ID, err)
+	}
+}
+
+// validateRestock checks that restocked quantities do not exceed what the order
+// bought minus what earlier refunds already returned to stock
+func (s *Service) validateRestock(ctx context.Context, o *order.Order, items []payment.RestockItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	remaining := make(map[uuid.UUID]int, len(o.Items))
+	for _, item := range o.Items {
+		remaining[item.ProductID] += item.Quantity
+	}
+
+	previous, err := s.repo.ListRefundsByOrder(ctx, o.ID)
+	if err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to list refunds")
+	}
+	for _, r := range previous {
+		for _, item := range r.Restocked {
+			remaining[item.ProductID] -= item.Quantity
+		}
+	}
+
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return appErrors.New(appErrors.ErrValidation, "restock quantity must be greater than zero")
+		}
+		if item.Quantity > remaining[item.ProductID] {
+			return appErrors.New(appErrors.ErrValidation, "cannot restock more than was bought").
+				WithDetails("product_id", item.ProductID.String())
+		}
+		remaining[item.ProductID] -= item.Quantity
+	}
+	return nil
+}
+
+func (s *Service) getPayment(ctx context.Context, id uuid.UUID) (*payment.Payment, error) {
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, payment.ErrPaymentNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "payment not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get payment")
+	}
+	return p, nil
+}
+
+func (s *Service) save(ctx context.Context, p *payment.Payment) error {
+	if err := s.repo.Update(ctx, p); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to update payment")
+	}
+	return nil
+}
+
+func gatewayError(err error) error {
+	switch {
+	case errors.Is(err, payment.ErrInsufficientFunds):
+		return appErrors.Wrap(err, appErrors.ErrInsufficientFunds, "insufficient funds")
+	case errors.Is(err, payment.ErrPaymentDeclined):
+		return appErrors.Wrap(err, appErrors.ErrPaymentFailed, "payment declined")
+	case errors.Is(err, payment.ErrGatewayUnavailable):
+		return appErrors.Wrap(err, appErrors.ErrTimeout, "payment provider unavailable")
+	default:
+		return appErrors.Wrap(err, appErrors.ErrPaymentFailed, "payment failed")
+	}
+}
+
+func orderError(err error) error {
+	if errors.Is(err, order.ErrOrderNotFound) {
+		return appErrors.New(appErrors.ErrNotFound, "order not found")
+	}
+	return appErrors.Wrap(err, appErrors.ErrInternal, "failed to get order")
+}
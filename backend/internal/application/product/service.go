@@ -341,7 +341,7 @@ func (s *Service) UpdateStock(ctx context.Context, productID uuid.UUID, sellerID
 // GetSellerProducts retrieves all products for a seller
 func (s *Service) GetSellerProducts(ctx context.Context, sellerID uuid.UUID, status *product.Status, page, pageSize int) (*ListResult, error) {
 	return s.ListProducts(ctx, ListProductsDTO{
-		SellerID: sellerID,
+		SellerID: &sellerID,
 		Status:   status,
 		Page:     page,
 		PageSize: pageSize,
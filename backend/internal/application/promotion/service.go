@@ -0,0 +1,235 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/promotion"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Service provides coupon management and redemption use cases
+type Service struct {
+	repo promotion.Repository
+}
+
+// NewService creates a new promotion service
+func NewService(repo promotion.Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Actor identifies who manages a coupon. Admins manage platform coupons,
+// sellers their own.
+type Actor struct {
+	UserID  uuid.UUID
+	IsAdmin bool
+}
+
+// ListResult represents a page of coupons
+type ListResult struct {
+	Coupons    []*promotion.Coupon
+	TotalCount int64
+	Page       int
+	PageSize   int
+}
+
+// CreateCoupon creates a platform coupon for admins or a seller coupon for sellers
+func (s *Service) CreateCoupon(ctx context.Context, actor Actor, c *promotion.Coupon) (*promotion.Coupon, error) {
+	if err := c.Validate(); err != nil {
+		return nil, invalidCouponError(err)
+	}
+
+	now := time.Now()
+	c.ID = uuid.New()
+	c.SellerID = nil
+	if !actor.IsAdmin {
+		sellerID := actor.UserID
+		c.SellerID = &sellerID
+	}
+	c.UsedCount = 0
+	c.CreatedBy = actor.UserID
+	c.CreatedAt = now
+	c.UpdatedAt = now
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		if errors.Is(err, promotion.ErrDuplicateCode) {
+			return nil, appErrors.New(appErrors.ErrConflict, "coupon code already exists")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create coupon")
+	}
+	return c, nil
+}
+
+// UpdateCoupon replaces a coupon's settings. The code and owner cannot change.
+func (s *Service) UpdateCoupon(ctx context.Context, actor Actor, couponID uuid.UUID, update *promotion.Coupon) (*promotion.Coupon, error) {
+	c, err := s.managedCoupon(ctx, actor, couponID)
+	if err != nil {
+		return nil, err
+	}
+
+	update.Code = c.Code
+	if err := update.Validate(); err != nil {
+		return nil, invalidCouponError(err)
+	}
+
+	c.Description = update.Description
+	c.Type = update.Type
+	c.PercentBps = update.PercentBps
+	c.Amount = update.Amount
+	c.MaxDiscount = update.MaxDiscount
+	c.MinOrderAmount = update.MinOrderAmount
+	c.ProductIDs = update.ProductIDs
+	c.CategoryIDs = update.CategoryIDs
+	c.UsageLimit = update.UsageLimit
+	c.PerUserLimit = update.PerUserLimit
+	c.StartsAt = update.StartsAt
+	c.ExpiresAt = update.ExpiresAt
+	c.Active = update.Active
+	c.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update coupon")
+	}
+	return c, nil
+}
+
+// DeleteCoupon deletes a coupon
+func (s *Service) DeleteCoupon(ctx context.Context, actor Actor, couponID uuid.UUID) error {
+	if _, err := s.managedCoupon(ctx, actor, couponID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, couponID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to delete coupon")
+	}
+	return nil
+}
+
+// ListCoupons lists the coupons an actor manages: platform coupons for
+// admins, their own for sellers
+func (s *Service) ListCoupons(ctx context.Context, actor Actor, page, pageSize int) (*ListResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	filter := promotion.Filter{Page: page, PageSize: pageSize}
+	if actor.IsAdmin {
+		filter.PlatformOnly = true
+	} else {
+		filter.SellerID = &actor.UserID
+	}
+
+	coupons, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list coupons")
+	}
+
+	return &ListResult{
+		Coupons:    coupons,
+		TotalCount: total,
+		Page:       page,
+		PageSize:   pageSize,
+	}, nil
+}
+
+// Evaluate prices a coupon code against lines. With a user, the coupon's
+// per-user limit is checked too.
+func (s *Service) Evaluate(ctx context.Context, code string, userID *uuid.UUID, lines []promotion.Line) (*promotion.Discount, error) {
+	c, err := s.repo.GetByCode(ctx, promotion.NormalizeCode(code))
+	if err != nil {
+		if errors.Is(err, promotion.ErrCouponNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "coupon not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get coupon")
+	}
+
+	d, err := c.Evaluate(lines, time.Now())
+	if err != nil {
+		return nil, redeemError(err)
+	}
+
+	if userID != nil && c.PerUserLimit != nil {
+		uses, err := s.repo.CountUserUses(ctx, c.ID, *userID)
+		if err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to count coupon uses")
+		}
+		if uses >= *c.PerUserLimit {
+			return nil, redeemError(promotion.ErrUserLimitReached)
+		}
+	}
+	return d, nil
+}
+
+// Redeem records the use of a discount by the orders of one checkout
+func (s *Service) Redeem(ctx context.Context, d *promotion.Discount, userID uuid.UUID, orders []*order.Order) error {
+	reference := uuid.New()
+	now := time.Now()
+
+	redemptions := make([]*promotion.Redemption, 0, len(orders))
+	for _, o := range orders {
+		if o.DiscountAmount <= 0 {
+			continue
+		}
+		redemptions = append(redemptions, &promotion.Redemption{
+			ID:        uuid.New(),
+			CouponID:  d.Coupon.ID,
+			UserID:    userID,
+			OrderID:   o.ID,
+			Reference: reference,
+			Amount:    o.DiscountAmount,
+			CreatedAt: now,
+		})
+	}
+
+	if err := s.repo.Redeem(ctx, d.Coupon, redemptions); err != nil {
+		if errors.Is(err, promotion.ErrUsageLimitReached) || errors.Is(err, promotion.ErrUserLimitReached) {
+			return redeemError(err)
+		}
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to redeem coupon")
+	}
+	return nil
+}
+
+// ReleaseOrder gives back the coupon use of a cancelled order
+func (s *Service) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	if err := s.repo.ReleaseOrder(ctx, orderID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to release coupon")
+	}
+	return nil
+}
+
+func (s *Service) managedCoupon(ctx context.Context, actor Actor, couponID uuid.UUID) (*promotion.Coupon, error) {
+	c, err := s.repo.GetByID(ctx, couponID)
+	if err != nil {
+		if errors.Is(err, promotion.ErrCouponNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "coupon not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get coupon")
+	}
+
+	if actor.IsAdmin {
+		if c.SellerID != nil {
+			return nil, appErrors.New(appErrors.ErrNotFound, "coupon not found")
+		}
+		return c, nil
+	}
+	if c.SellerID == nil || *c.SellerID != actor.UserID {
+		return nil, appErrors.New(appErrors.ErrNotFound, "coupon not found")
+	}
+	return c, nil
+}
+
+func invalidCouponError(err error) error {
+	return appErrors.Wrap(err, appErrors.ErrValidation, "coupon needs a 3-32 character code, a type of percentage (1-10000 bps) or fixed (positive amount), positive limits and an expiry after its start")
+}
+
+// redeemError explains why a coupon cannot be used
+func redeemError(err error) error {
+	return appErrors.Wrap(err, appErrors.ErrValidation, err.Error())
+}
@@ -0,0 +1,317 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/blytz/live/backend/internal/domain/shipping"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Service provides address book, shipping profile and shipping quote use cases
+type Service struct {
+	profileRepo shipping.ProfileRepository
+	addressRepo shipping.AddressRepository
+	productRepo product.Repository
+}
+
+// NewService creates a new shipping service
+func NewService(profileRepo shipping.ProfileRepository, addressRepo shipping.AddressRepository, productRepo product.Repository) *Service {
+	return &Service{
+		profileRepo: profileRepo,
+		addressRepo: addressRepo,
+		productRepo: productRepo,
+	}
+}
+
+// Destination selects where an order ships: a saved address, an address given
+// inline, or (with neither) the user's default address
+type Destination struct {
+	AddressID *uuid.UUID
+	Address   *order.Address
+}
+
+// QuoteItem is a product and quantity to quote shipping for
+type QuoteItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+// ListAddresses lists a user's address book, default first
+func (s *Service) ListAddresses(ctx context.Context, userID uuid.UUID) ([]*shipping.SavedAddress, error) {
+	addresses, err := s.addressRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list addresses")
+	}
+	return addresses, nil
+}
+
+// AddAddress adds an address to a user's address book. The first address
+// becomes the default.
+func (s *Service) AddAddress(ctx context.Context, userID uuid.UUID, label string, addr order.Address, makeDefault bool) (*shipping.SavedAddress, error) {
+	a, err := shipping.NewSavedAddress(userID, label, addr, time.Now())
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation, "name, line1, city, postal_code and a two-letter country are required")
+	}
+
+	existing, err := s.addressRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list addresses")
+	}
+	a.IsDefault = makeDefault || len(existing) == 0
+
+	if err := s.addressRepo.Create(ctx, a); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to save address")
+	}
+	return a, nil
+}
+
+// UpdateAddress replaces the label and fields of a saved address
+func (s *Service) UpdateAddress(ctx context.Context, userID, addressID uuid.UUID, label string, addr order.Address) (*shipping.SavedAddress, error) {
+	a, err := s.userAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if err := shipping.ValidateAddress(&addr); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation, "name, line1, city, postal_code and a two-letter country are required")
+	}
+
+	a.Label = label
+	a.Address = addr
+	a.UpdatedAt = time.Now()
+	if err := s.addressRepo.Update(ctx, a); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update address")
+	}
+	return a, nil
+}
+
+// SetDefaultAddress makes a saved address the user's default
+func (s *Service) SetDefaultAddress(ctx context.Context, userID, addressID uuid.UUID) (*shipping.SavedAddress, error) {
+	a, err := s.userAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.addressRepo.SetDefault(ctx, userID, addressID); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to set default address")
+	}
+	a.IsDefault = true
+	return a, nil
+}
+
+// DeleteAddress removes an address from a user's address book
+func (s *Service) DeleteAddress(ctx context.Context, userID, addressID uuid.UUID) error {
+	if _, err := s.userAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+
+	if err := s.addressRepo.Delete(ctx, addressID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to delete address")
+	}
+	return nil
+}
+
+// ResolveDestination returns the address a user's order ships to
+func (s *Service) ResolveDestination(ctx context.Context, userID uuid.UUID, dest Destination) (*order.Address, error) {
+	switch {
+	case dest.AddressID != nil:
+		a, err := s.userAddress(ctx, userID, *dest.AddressID)
+		if err != nil {
+			return nil, err
+		}
+		addr := a.Address
+		return &addr, nil
+
+	case dest.Address != nil:
+		addr := *dest.Address
+		if err := shipping.ValidateAddress(&addr); err != nil {
+			return nil, appErrors.Wrap(err, appErrors.ErrValidation, "name, line1, city, postal_code and a two-letter country are required")
+		}
+		return &addr, nil
+	}
+
+	addresses, err := s.addressRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list addresses")
+	}
+	if len(addresses) == 0 || !addresses[0].IsDefault {
+		return nil, appErrors.New(appErrors.ErrValidation, "a shipping address is required")
+	}
+	addr := addresses[0].Address
+	return &addr, nil
+}
+
+// ListProfiles lists a seller's shipping profiles
+func (s *Service) ListProfiles(ctx context.Context, sellerID uuid.UUID) ([]*shipping.Profile, error) {
+	profiles, err := s.profileRepo.ListBySeller(ctx, sellerID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list shipping profiles")
+	}
+	return profiles, nil
+}
+
+// CreateProfile creates a shipping profile for a seller
+func (s *Service) CreateProfile(ctx context.Context, sellerID uuid.UUID, p *shipping.Profile) (*shipping.Profile, error) {
+	if err := p.Validate(); err != nil {
+		return nil, profileError(err)
+	}
+
+	now := time.Now()
+	p.ID = uuid.New()
+	p.SellerID = sellerID
+	p.CreatedAt = now
+	p.UpdatedAt = now
+	if err := s.profileRepo.Create(ctx, p); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to create shipping profile")
+	}
+	return p, nil
+}
+
+// UpdateProfile replaces the settings of a seller's shipping profile
+func (s *Service) UpdateProfile(ctx context.Context, sellerID, profileID uuid.UUID, update *shipping.Profile) (*shipping.Profile, error) {
+	p, err := s.sellerProfile(ctx, sellerID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	if err := update.Validate(); err != nil {
+		return nil, profileError(err)
+	}
+
+	p.Name = update.Name
+	p.Countries = update.Countries
+	p.RateType = update.RateType
+	p.FlatRate = update.FlatRate
+	p.Tiers = update.Tiers
+	p.FreeOverAmount = update.FreeOverAmount
+	p.UpdatedAt = time.Now()
+	if err := s.profileRepo.Update(ctx, p); err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to update shipping profile")
+	}
+	return p, nil
+}
+
+// DeleteProfile deletes a seller's shipping profile
+func (s *Service) DeleteProfile(ctx context.Context, sellerID, profileID uuid.UUID) error {
+	if _, err := s.sellerProfile(ctx, sellerID, profileID); err != nil {
+		return err
+	}
+
+	if err := s.profileRepo.Delete(ctx, profileID); err != nil {
+		return appErrors.Wrap(err, appErrors.ErrInternal, "failed to delete shipping profile")
+	}
+	return nil
+}
+
+// Quote prices shipping a seller's parcels to a destination. Sellers without
+// shipping profiles ship for free.
+func (s *Service) Quote(ctx context.Context, sellerID uuid.UUID, parcels []shipping.Parcel, subtotal float64, dest *order.Address) (*shipping.Quote, error) {
+	profiles, err := s.profileRepo.ListBySeller(ctx, sellerID)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to list shipping profiles")
+	}
+
+	q := &shipping.Quote{
+		SellerID:            sellerID,
+		BillableWeightGrams: shipping.BillableWeight(parcels),
+	}
+	if len(profiles) == 0 {
+		return q, nil
+	}
+
+	p := shipping.SelectProfile(profiles, dest.Country)
+	if p == nil {
+		return nil, appErrors.Wrap(shipping.ErrNoRoute, appErrors.ErrValidation, "seller does not ship to "+dest.Country)
+	}
+	cost, err := p.Price(q.BillableWeightGrams, subtotal)
+	if err != nil {
+		return nil, appErrors.Wrap(err, appErrors.ErrValidation, "parcel is too heavy for the seller's shipping rates")
+	}
+
+	q.ProfileID = &p.ID
+	q.ProfileName = p.Name
+	q.Cost = cost
+	return q, nil
+}
+
+// QuoteItems prices shipping products to a user's destination, one quote per seller
+func (s *Service) QuoteItems(ctx context.Context, userID uuid.UUID, items []QuoteItem, dest Destination) ([]*shipping.Quote, error) {
+	addr, err := s.ResolveDestination(ctx, userID, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	var sellers []uuid.UUID
+	parcels := make(map[uuid.UUID][]shipping.Parcel)
+	subtotals := make(map[uuid.UUID]float64)
+	for _, item := range items {
+		p, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			if errors.Is(err, product.ErrProductNotFound) {
+				return nil, appErrors.New(appErrors.ErrNotFound, "product not found").
+					WithDetails("product_id", item.ProductID.String())
+			}
+			return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get product")
+		}
+
+		if _, ok := parcels[p.SellerID]; !ok {
+			sellers = append(sellers, p.SellerID)
+		}
+		parcels[p.SellerID] = append(parcels[p.SellerID], ParcelOf(p, item.Quantity))
+		subtotals[p.SellerID] += p.BasePrice * float64(item.Quantity)
+	}
+
+	quotes := make([]*shipping.Quote, 0, len(sellers))
+	for _, sellerID := range sellers {
+		q, err := s.Quote(ctx, sellerID, parcels[sellerID], subtotals[sellerID], addr)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, q)
+	}
+	return quotes, nil
+}
+
+// ParcelOf describes a quantity of a product as a parcel
+func ParcelOf(p *product.Product, quantity int) shipping.Parcel {
+	return shipping.Parcel{
+		Quantity:     quantity,
+		WeightGrams:  p.WeightGrams,
+		DimensionsCm: p.DimensionsCm,
+	}
+}
+
+func (s *Service) userAddress(ctx context.Context, userID, addressID uuid.UUID) (*shipping.SavedAddress, error) {
+	a, err := s.addressRepo.GetByID(ctx, addressID)
+	if err != nil {
+		if errors.Is(err, shipping.ErrAddressNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "address not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get address")
+	}
+	if a.UserID != userID {
+		return nil, appErrors.New(appErrors.ErrNotFound, "address not found")
+	}
+	return a, nil
+}
+
+func (s *Service) sellerProfile(ctx context.Context, sellerID, profileID uuid.UUID) (*shipping.Profile, error) {
+	p, err := s.profileRepo.GetByID(ctx, profileID)
+	if err != nil {
+		if errors.Is(err, shipping.ErrProfileNotFound) {
+			return nil, appErrors.New(appErrors.ErrNotFound, "shipping profile not found")
+		}
+		return nil, appErrors.Wrap(err, appErrors.ErrInternal, "failed to get shipping profile")
+	}
+	if p.SellerID != sellerID {
+		return nil, appErrors.New(appErrors.ErrNotFound, "shipping profile not found")
+	}
+	return p, nil
+}
+
+func profileError(err error) error {
+	return appErrors.Wrap(err, appErrors.ErrValidation, "profile needs a rate type of flat or weight_tiered, non-negative rates, unique tier weights and two-letter country codes")
+}
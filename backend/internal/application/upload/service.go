@@ -152,6 +152,42 @@ func (s *Service) UploadStreamThumbnail(ctx context.Context, file io.Reader, fil
 	}, nil
 }
 
+// UploadDisputeEvidence uploads a photo or PDF attached to a dispute
+func (s *Service) UploadDisputeEvidence(ctx context.Context, file io.Reader, filename string, size int64) (*UploadResult, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	allowedExts := map[string]bool{
+		".jpg":  true,
+		".jpeg": true,
+		".png":  true,
+		".webp": true,
+		".pdf":  true,
+	}
+	if !allowedExts[ext] {
+		return nil, fmt.Errorf("invalid file type: %s (allowed: jpg, png, webp, pdf)", ext)
+	}
+
+	// Max 10MB
+	if size > 10*1024*1024 {
+		return nil, fmt.Errorf("file too large: max 10MB")
+	}
+
+	result, err := s.r2Client.Upload(ctx, file, filename, r2.UploadOptions{
+		Folder:       "disputes",
+		AllowedTypes: []string{"image/", "application/pdf"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		URL:         result.URL,
+		Key:         result.Key,
+		ContentType: result.ContentType,
+		Size:        size,
+	}, nil
+}
+
 // UploadFromMultipart uploads a file from multipart form
 func (s *Service) UploadFromMultipart(ctx context.Context, fileHeader *multipart.FileHeader, folder string) (*UploadResult, error) {
 	file, err := fileHeader.Open()
@@ -8,6 +8,10 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrAlreadyRelisted is returned when relisting an auction that has already
+// been relisted
+var ErrAlreadyRelisted = errors.New("auction has already been relisted")
+
 type Status string
 
 const (
@@ -17,28 +21,52 @@ const (
 	StatusCancelled Status = "cancelled"
 )
 
+// BidderRequirement is what an auction asks of bidders before it accepts
+// their bids, giving sellers of expensive lots assurance bidders can pay
+type BidderRequirement string
+
+const (
+	RequireNothing       BidderRequirement = ""
+	RequirePaymentMethod BidderRequirement = "payment_method" // a saved, unexpired card
+	RequireDepositHold   BidderRequirement = "deposit_hold"   // a pre-authorized hold of DepositAmount
+)
+
+// IsValid reports whether r is a known requirement
+func (r BidderRequirement) IsValid() bool {
+	switch r {
+	case RequireNothing, RequirePaymentMethod, RequireDepositHold:
+		return true
+	}
+	return false
+}
+
 type Auction struct {
-	ID           uuid.UUID
-	ProductID    uuid.UUID
-	SellerID     uuid.UUID
-	Title        string
-	Description  string
-	StartTime    time.Time
-	EndTime      time.Time
-	Status       Status
-	StartPrice   float64
-	ReservePrice *float64
-	BuyNowPrice  *float64
-	CurrentBid   *Bid
-	BidCount     int
-	WinnerID     *uuid.UUID
-	LiveKitRoom  string
-	StreamKey    string
-	AutoExtend   bool
-	ExtendTime   time.Duration
-	IsFeatured   bool
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID                uuid.UUID
+	ProductID         uuid.UUID
+	SellerID          uuid.UUID
+	Title             string
+	Description       string
+	StartTime         time.Time
+	EndTime           time.Time
+	Status            Status
+	StartPrice        float64
+	ReservePrice      *float64
+	BuyNowPrice       *float64
+	BidderRequirement BidderRequirement
+	DepositAmount     float64 // hold placed on bidders' cards when RequireDepositHold
+	CurrentBid        *Bid
+	BidCount          int
+	WinnerID          *uuid.UUID
+	LiveKitRoom       string
+	StreamKey         string
+	AutoExtend        bool
+	ExtendTime        time.Duration
+	IsFeatured        bool
+	ViewerCount       int
+	RelistedFrom      *uuid.UUID // the auction this one relists
+	RelistedAs        *uuid.UUID // the auction that relisted this one
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
 
 func (a *Auction) CanPlaceBid(amount float64, now time.Time) error {
@@ -141,6 +169,9 @@ func (ab *AutoBid) ShouldBid(currentBid float64) (bool, float64) {
 }
 
 type Repository interface {
+	// Create stores a new auction. When it relists another auction, the
+	// source is marked relisted in the same transaction, failing with
+	// ErrAlreadyRelisted if another relist got there first.
 	Create(ctx context.Context, auction *Auction) error
 	Update(ctx context.Context, auction *Auction) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -161,11 +192,26 @@ type Cache interface {
 	GetAuctionState(ctx context.Context, auctionID uuid.UUID) (*AuctionState, error)
 	SetAuctionState(ctx context.Context, auctionID uuid.UUID, state *AuctionState, ttl time.Duration) error
 	DeleteAuctionState(ctx context.Context, auctionID uuid.UUID) error
-	IncrementViewerCount(ctx context.Context, auctionID uuid.UUID) (int, error)
-	DecrementViewerCount(ctx context.Context, auctionID uuid.UUID) (int, error)
+	SetPresence(ctx context.Context, auctionID uuid.UUID, instanceID string, presence *InstancePresence, ttl time.Duration) error
+	RemovePresence(ctx context.Context, auctionID uuid.UUID, instanceID string) error
+	GetPresence(ctx context.Context, auctionID uuid.UUID) (*Presence, error)
 	GetViewerCount(ctx context.Context, auctionID uuid.UUID) (int, error)
 }
 
+// InstancePresence is the set of viewers connected to a single server instance
+type InstancePresence struct {
+	UserIDs   []string `json:"user_ids"`
+	Anonymous int      `json:"anonymous"`
+}
+
+// Presence is the cluster-wide audience of an auction room. Authenticated
+// counts unique users regardless of how many tabs or instances they use.
+type Presence struct {
+	Viewers       int `json:"viewers"`
+	Authenticated int `json:"authenticated"`
+	Anonymous     int `json:"anonymous"`
+}
+
 type AuctionState struct {
 	AuctionID   uuid.UUID `json:"auction_id"`
 	CurrentBid  *Bid      `json:"current_bid,omitempty"`
@@ -181,4 +227,4 @@ type EventBus interface {
 	PublishAuctionStarted(ctx context.Context, auctionID uuid.UUID) error
 	PublishAuctionEnded(ctx context.Context, auctionID uuid.UUID, winnerID *uuid.UUID) error
 	PublishAuctionExtended(ctx context.Context, auctionID uuid.UUID, newEndTime time.Time) error
-}
\ No newline at end of file
+}
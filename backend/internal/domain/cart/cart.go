@@ -0,0 +1,202 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrCartNotFound    = errors.New("cart not found")
+	ErrItemNotFound    = errors.New("item not in cart")
+	ErrInvalidQuantity = errors.New("quantity must be greater than zero")
+	ErrCartExpired     = errors.New("cart has expired")
+)
+
+const (
+	// GuestCartTTL is how long an untouched guest cart is kept
+	GuestCartTTL = 7 * 24 * time.Hour
+	// UserCartTTL is how long an untouched user cart is kept
+	UserCartTTL = 30 * 24 * time.Hour
+	// MaxItemQuantity caps the quantity of a single line
+	MaxItemQuantity = 99
+)
+
+// Cart represents a shopping cart owned by a user or a guest token
+type Cart struct {
+	ID         uuid.UUID
+	UserID     *uuid.UUID
+	Token      string
+	CouponCode string // applied at checkout if still valid
+	Items      []Item
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Item represents a product line in a cart.
+// UnitPrice is the price snapshot taken when the product was added.
+type Item struct {
+	ID        uuid.UUID
+	CartID    uuid.UUID
+	ProductID uuid.UUID
+	Quantity  int
+	UnitPrice float64
+	AddedAt   time.Time
+}
+
+// NewCart creates an empty cart
+func NewCart(userID *uuid.UUID, token string, now time.Time) *Cart {
+	c := &Cart{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Token:     token,
+		Items:     []Item{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	c.Touch(now)
+	return c
+}
+
+// IsGuest reports whether the cart belongs to an anonymous visitor
+func (c *Cart) IsGuest() bool {
+	return c.UserID == nil
+}
+
+// IsExpired reports whether the cart has expired
+func (c *Cart) IsExpired(now time.Time) bool {
+	return now.After(c.ExpiresAt)
+}
+
+// Touch extends the cart's expiry after activity
+func (c *Cart) Touch(now time.Time) {
+	ttl := UserCartTTL
+	if c.IsGuest() {
+		ttl = GuestCartTTL
+	}
+	c.ExpiresAt = now.Add(ttl)
+	c.UpdatedAt = now
+}
+
+// SetCoupon applies a coupon code to the cart, or removes it when code is empty
+func (c *Cart) SetCoupon(code string, now time.Time) {
+	c.CouponCode = code
+	c.Touch(now)
+}
+
+// Find returns the line for a product
+func (c *Cart) Find(productID uuid.UUID) (*Item, bool) {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			return &c.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// SetItem sets the quantity of a product line, adding it with the given price snapshot if absent
+func (c *Cart) SetItem(productID uuid.UUID, quantity int, unitPrice float64, now time.Time) error {
+	if quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	if quantity > MaxItemQuantity {
+		quantity = MaxItemQuantity
+	}
+
+	if item, ok := c.Find(productID); ok {
+		item.Quantity = quantity
+	} else {
+		c.Items = append(c.Items, Item{
+			ID:        uuid.New(),
+			CartID:    c.ID,
+			ProductID: productID,
+			Quantity:  quantity,
+			UnitPrice: unitPrice,
+			AddedAt:   now,
+		})
+	}
+	c.Touch(now)
+	return nil
+}
+
+// RemoveItem removes a product line
+func (c *Cart) RemoveItem(productID uuid.UUID, now time.Time) error {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			c.Touch(now)
+			return nil
+		}
+	}
+	return ErrItemNotFound
+}
+
+// Clear removes all lines
+func (c *Cart) Clear(now time.Time) {
+	c.Items = []Item{}
+	c.Touch(now)
+}
+
+// Merge moves the lines of another cart into this one. Quantities of
+// products present in both carts are added up; existing price snapshots win.
+func (c *Cart) Merge(other *Cart, now time.Time) {
+	for _, item := range other.Items {
+		if existing, ok := c.Find(item.ProductID); ok {
+			existing.Quantity += item.Quantity
+			if existing.Quantity > MaxItemQuantity {
+				existing.Quantity = MaxItemQuantity
+			}
+			continue
+		}
+		item.ID = uuid.New()
+		item.CartID = c.ID
+		c.Items = append(c.Items, item)
+	}
+	if c.CouponCode == "" {
+		c.CouponCode = other.CouponCode
+	}
+	c.Touch(now)
+}
+
+// Subtotal returns the total of all lines at their snapshot prices
+func (c *Cart) Subtotal() float64 {
+	var total float64
+	for _, item := range c.Items {
+		total += item.UnitPrice * float64(item.Quantity)
+	}
+	return total
+}
+
+// ItemCount returns the total quantity of all lines
+func (c *Cart) ItemCount() int {
+	var count int
+	for _, item := range c.Items {
+		count += item.Quantity
+	}
+	return count
+}
+
+// Repository defines the interface for cart data access
+type Repository interface {
+	// Create creates a cart with its items
+	Create(ctx context.Context, cart *Cart) error
+
+	// Save updates a cart and replaces its items
+	Save(ctx context.Context, cart *Cart) error
+
+	// GetByToken retrieves a cart by its token
+	GetByToken(ctx context.Context, token string) (*Cart, error)
+
+	// GetByUserID retrieves a user's cart
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*Cart, error)
+
+	// Delete deletes a cart and its items
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// DeleteExpired deletes carts that expired before the given time
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,68 @@
+package deposit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrHoldNotFound = errors.New("deposit hold not found")
+	ErrHoldExists   = errors.New("bidder already has an active hold on this auction")
+)
+
+// Status represents the status of a deposit hold
+type Status string
+
+const (
+	StatusHeld     Status = "held"
+	StatusReleased Status = "released"
+)
+
+// Hold is an amount pre-authorized on a bidder's payment method so the
+// seller of a high-value auction knows the bidder can pay. The money is
+// never captured; the authorization is voided once the hold is released.
+type Hold struct {
+	ID               uuid.UUID
+	AuctionID        uuid.UUID
+	UserID           uuid.UUID
+	Amount           float64
+	Currency         string
+	Provider         string
+	MethodID         *uuid.UUID
+	GatewayReference string
+	Status           Status
+	CreatedAt        time.Time
+	ReleasedAt       *time.Time
+}
+
+// IsActive reports whether the hold still reserves funds
+func (h *Hold) IsActive() bool {
+	return h.Status == StatusHeld
+}
+
+// Release marks the hold as released
+func (h *Hold) Release(now time.Time) {
+	h.Status = StatusReleased
+	h.ReleasedAt = &now
+}
+
+// Repository defines the interface for deposit hold data access.
+// Implementations must keep at most one active hold per bidder and auction.
+type Repository interface {
+	// Create saves a hold, returning ErrHoldExists if the bidder already
+	// has an active hold on the auction
+	Create(ctx context.Context, hold *Hold) error
+
+	// Update saves changes to a hold
+	Update(ctx context.Context, hold *Hold) error
+
+	// GetActive retrieves a bidder's active hold on an auction
+	GetActive(ctx context.Context, auctionID, userID uuid.UUID) (*Hold, error)
+
+	// ListActiveByAuction retrieves all active holds on an auction
+	ListActiveByAuction(ctx context.Context, auctionID uuid.UUID) ([]*Hold, error)
+}
@@ -0,0 +1,246 @@
+package dispute
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrDisputeNotFound   = errors.New("dispute not found")
+	ErrDisputeExists     = errors.New("order already has an open dispute")
+	ErrInvalidTransition = errors.New("invalid dispute status transition")
+	ErrNotDisputable     = errors.New("order cannot be disputed")
+	ErrDisputeClosed     = errors.New("dispute is resolved")
+	ErrStatusConflict    = errors.New("dispute status was changed concurrently")
+)
+
+const (
+	// FilingWindow is how long after delivery a buyer may open a dispute
+	FilingWindow = 30 * 24 * time.Hour
+	// ResponseWindow is how long the seller has to respond before the
+	// dispute escalates to admins
+	ResponseWindow = 3 * 24 * time.Hour
+	// DecisionWindow is how long the buyer has to accept the seller's
+	// response before the dispute escalates to admins
+	DecisionWindow = 5 * 24 * time.Hour
+)
+
+// Status represents the lifecycle status of a dispute
+type Status string
+
+const (
+	StatusOpened          Status = "opened"
+	StatusSellerResponded Status = "seller_responded"
+	StatusEscalated       Status = "escalated"
+	StatusResolved        Status = "resolved"
+)
+
+// transitions lists the statuses each status may move to
+var transitions = map[Status][]Status{
+	StatusOpened:          {StatusSellerResponded, StatusEscalated, StatusResolved},
+	StatusSellerResponded: {StatusEscalated, StatusResolved},
+	StatusEscalated:       {StatusResolved},
+}
+
+// Reason is the buyer's complaint
+type Reason string
+
+const (
+	ReasonNotAsDescribed Reason = "not_as_described"
+	ReasonDamaged        Reason = "damaged"
+	ReasonNotReceived    Reason = "not_received"
+	ReasonOther          Reason = "other"
+)
+
+// IsValid reports whether r is a known reason
+func (r Reason) IsValid() bool {
+	switch r {
+	case ReasonNotAsDescribed, ReasonDamaged, ReasonNotReceived, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// Outcome is how a dispute was settled
+type Outcome string
+
+const (
+	OutcomeRefund      Outcome = "refund"       // the buyer was refunded
+	OutcomeSellerFavor Outcome = "seller_favor" // closed without a refund
+)
+
+// Dispute is a buyer's claim against an order, worked out with the seller
+// and, failing that, decided by an admin
+type Dispute struct {
+	ID              uuid.UUID
+	OrderID         uuid.UUID
+	BuyerID         uuid.UUID
+	SellerID        uuid.UUID
+	Reason          Reason
+	Description     string
+	RequestedAmount float64 // zero asks for everything refundable
+	Status          Status
+
+	// Seller's response, possibly offering a (partial) refund
+	SellerResponse string
+	OfferedAmount  float64
+	RespondedAt    *time.Time
+
+	// DeadlineAt is when the dispute escalates if the party whose turn it
+	// is has not acted; nil once escalated or resolved
+	DeadlineAt  *time.Time
+	EscalatedAt *time.Time
+
+	Resolution *Resolution
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Resolution records how a dispute was settled
+type Resolution struct {
+	Outcome      Outcome
+	RefundAmount float64
+	RefundID     *uuid.UUID
+	ResolvedBy   uuid.UUID
+	Note         string
+	ResolvedAt   time.Time
+}
+
+// Evidence is a note, optionally with an attached file, added to a dispute
+// by one of its parties or an admin
+type Evidence struct {
+	ID          uuid.UUID
+	DisputeID   uuid.UUID
+	AuthorID    uuid.UUID
+	Note        string
+	FileURL     string
+	FileKey     string
+	ContentType string
+	CreatedAt   time.Time
+}
+
+// NewDispute opens a dispute, giving the seller ResponseWindow to respond
+func NewDispute(orderID, buyerID, sellerID uuid.UUID, reason Reason, description string, amount float64, now time.Time) *Dispute {
+	deadline := now.Add(ResponseWindow)
+	return &Dispute{
+		ID:              uuid.New(),
+		OrderID:         orderID,
+		BuyerID:         buyerID,
+		SellerID:        sellerID,
+		Reason:          reason,
+		Description:     description,
+		RequestedAmount: amount,
+		Status:          StatusOpened,
+		DeadlineAt:      &deadline,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// CanTransition reports whether the dispute may move to the given status
+func (d *Dispute) CanTransition(to Status) bool {
+	for _, s := range transitions[d.Status] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsParticipant reports whether the user is the dispute's buyer or seller
+func (d *Dispute) IsParticipant(userID uuid.UUID) bool {
+	return d.BuyerID == userID || d.SellerID == userID
+}
+
+// IsOpen reports whether the dispute is still being worked out
+func (d *Dispute) IsOpen() bool {
+	return d.Status != StatusResolved
+}
+
+// IsOverdue reports whether the dispute's deadline has passed
+func (d *Dispute) IsOverdue(now time.Time) bool {
+	return d.DeadlineAt != nil && now.After(*d.DeadlineAt)
+}
+
+// Respond records the seller's response, optionally offering a refund, and
+// gives the buyer DecisionWindow to accept it
+func (d *Dispute) Respond(response string, offeredAmount float64, now time.Time) error {
+	if d.Status != StatusOpened {
+		return ErrInvalidTransition
+	}
+	deadline := now.Add(DecisionWindow)
+	d.Status = StatusSellerResponded
+	d.SellerResponse = response
+	d.OfferedAmount = offeredAmount
+	d.RespondedAt = &now
+	d.DeadlineAt = &deadline
+	d.UpdatedAt = now
+	return nil
+}
+
+// Escalate hands the dispute to admins
+func (d *Dispute) Escalate(now time.Time) error {
+	if !d.CanTransition(StatusEscalated) {
+		return ErrInvalidTransition
+	}
+	d.Status = StatusEscalated
+	d.EscalatedAt = &now
+	d.DeadlineAt = nil
+	d.UpdatedAt = now
+	return nil
+}
+
+// Resolve settles the dispute
+func (d *Dispute) Resolve(r Resolution) error {
+	if !d.CanTransition(StatusResolved) {
+		return ErrInvalidTransition
+	}
+	d.Status = StatusResolved
+	d.Resolution = &r
+	d.DeadlineAt = nil
+	d.UpdatedAt = r.ResolvedAt
+	return nil
+}
+
+// Filter represents dispute list filters
+type Filter struct {
+	BuyerID  *uuid.UUID
+	SellerID *uuid.UUID
+	Status   *Status
+	Page     int
+	PageSize int
+}
+
+// Repository defines the interface for dispute data access
+type Repository interface {
+	// Create creates a dispute, failing with ErrDisputeExists if the order
+	// already has an unresolved one
+	Create(ctx context.Context, d *Dispute) error
+
+	// TransitionStatus updates a dispute's status, response, deadlines and
+	// resolution only if the stored dispute is still in status from. It
+	// returns ErrStatusConflict when another writer moved the dispute first.
+	TransitionStatus(ctx context.Context, d *Dispute, from Status) error
+
+	// GetByID retrieves a dispute by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Dispute, error)
+
+	// ListByOrder retrieves an order's disputes, newest first
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*Dispute, error)
+
+	// List retrieves disputes matching the filter, newest first
+	List(ctx context.Context, filter Filter) ([]*Dispute, int64, error)
+
+	// ListOverdue retrieves unresolved disputes whose deadline passed before now
+	ListOverdue(ctx context.Context, now time.Time, limit int) ([]*Dispute, error)
+
+	// AddEvidence adds evidence to a dispute
+	AddEvidence(ctx context.Context, e *Evidence) error
+
+	// ListEvidence retrieves a dispute's evidence, oldest first
+	ListEvidence(ctx context.Context, disputeID uuid.UUID) ([]*Evidence, error)
+}
@@ -0,0 +1,138 @@
+package flashsale
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrSaleNotFound     = errors.New("flash sale not found")
+	ErrInvalidSale      = errors.New("invalid flash sale")
+	ErrSaleOverlaps     = errors.New("product already has a flash sale in this window")
+	ErrSaleStarted      = errors.New("flash sale has already started")
+	ErrSoldOut          = errors.New("flash sale is sold out")
+	ErrUserLimitReached = errors.New("flash sale purchase limit reached")
+	ErrBuyerCountsLost  = errors.New("flash sale buyer counts were lost")
+)
+
+// Sale temporarily overrides a product's price for a time window, for a
+// limited quantity and optionally a limited quantity per buyer
+type Sale struct {
+	ID           uuid.UUID
+	ProductID    uuid.UUID
+	SellerID     uuid.UUID
+	SalePrice    float64
+	Quantity     int  // units available at the sale price
+	PerUserLimit *int // units one buyer may purchase
+	SoldCount    int
+	StartsAt     time.Time
+	EndsAt       time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Validate checks the sale against the product's regular price
+func (s *Sale) Validate(basePrice float64) error {
+	if s.SalePrice <= 0 || s.SalePrice >= basePrice {
+		return ErrInvalidSale
+	}
+	if s.Quantity <= 0 {
+		return ErrInvalidSale
+	}
+	if s.PerUserLimit != nil && *s.PerUserLimit <= 0 {
+		return ErrInvalidSale
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return ErrInvalidSale
+	}
+	return nil
+}
+
+// HasStarted reports whether the sale window has opened
+func (s *Sale) HasStarted(now time.Time) bool {
+	return !now.Before(s.StartsAt)
+}
+
+// IsActive reports whether the sale price applies right now
+func (s *Sale) IsActive(now time.Time) bool {
+	return s.HasStarted(now) && now.Before(s.EndsAt) && s.Remaining() > 0
+}
+
+// Remaining returns the units still available at the sale price
+func (s *Sale) Remaining() int {
+	if s.SoldCount >= s.Quantity {
+		return 0
+	}
+	return s.Quantity - s.SoldCount
+}
+
+// Claim records units of a sale bought by one order
+type Claim struct {
+	ID        uuid.UUID
+	SaleID    uuid.UUID
+	UserID    uuid.UUID
+	OrderID   uuid.UUID
+	Quantity  int
+	CreatedAt time.Time
+}
+
+// Repository defines the interface for flash sale data access
+type Repository interface {
+	// Create creates a sale, failing with ErrSaleOverlaps if the product
+	// already has a sale in an overlapping window
+	Create(ctx context.Context, sale *Sale) error
+
+	// Update updates a sale's price, quantities and window
+	Update(ctx context.Context, sale *Sale) error
+
+	// GetByID retrieves a sale by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Sale, error)
+
+	// Delete deletes a sale
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ListBySeller retrieves a seller's sales, newest first
+	ListBySeller(ctx context.Context, sellerID uuid.UUID) ([]*Sale, error)
+
+	// ListActive retrieves sales running at now that are not sold out,
+	// ending soonest first
+	ListActive(ctx context.Context, now time.Time) ([]*Sale, error)
+
+	// ActiveForProducts returns the sale running at now for each of the
+	// products that has one
+	ActiveForProducts(ctx context.Context, productIDs []uuid.UUID, now time.Time) (map[uuid.UUID]*Sale, error)
+
+	// Claim atomically records claims and adds them to the sales' sold
+	// counts, failing with ErrSoldOut or ErrUserLimitReached instead of
+	// overselling
+	Claim(ctx context.Context, claims []*Claim) error
+
+	// ReleaseOrder removes an order's claims and gives their units back,
+	// returning the claims released
+	ReleaseOrder(ctx context.Context, orderID uuid.UUID) ([]*Claim, error)
+
+	// ClaimedByBuyer sums the units of a sale each buyer has claimed
+	ClaimedByBuyer(ctx context.Context, saleID uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+// Counter hands out a sale's units under contention. It absorbs the rush of
+// buyers when a sale opens so that only purchases that can succeed reach the
+// repository, which remains the source of truth.
+type Counter interface {
+	// Reserve takes quantity units of the sale for a user, failing with
+	// ErrSoldOut or ErrUserLimitReached. It fails with ErrBuyerCountsLost,
+	// taking nothing, when the sale has a per-user limit and the counts of
+	// what each buyer took were lost; RestoreBuyers must then be called.
+	Reserve(ctx context.Context, sale *Sale, userID uuid.UUID, quantity int) error
+
+	// RestoreBuyers rebuilds lost per-buyer counts of a sale from the units
+	// each buyer has claimed
+	RestoreBuyers(ctx context.Context, sale *Sale, claimed map[uuid.UUID]int) error
+
+	// Release gives back units taken by Reserve
+	Release(ctx context.Context, saleID, userID uuid.UUID, quantity int) error
+}
@@ -0,0 +1,104 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrInvalidQuantity     = errors.New("reservation quantity must be greater than zero")
+)
+
+// CheckoutHoldTTL is how long stock is held for an unpaid checkout order
+const CheckoutHoldTTL = 15 * time.Minute
+
+// Status represents the status of a stock reservation
+type Status string
+
+const (
+	// StatusActive means the units are held and deducted from available stock
+	StatusActive Status = "active"
+	// StatusCommitted means the units were sold
+	StatusCommitted Status = "committed"
+	// StatusReleased means the units were returned to available stock
+	StatusReleased Status = "released"
+)
+
+// Reservation holds units of a product for an order or auction.
+// Held units are deducted from Product.StockQuantity when the reservation is made
+// and returned if it is released.
+type Reservation struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	UserID    uuid.UUID
+	OrderID   *uuid.UUID
+	AuctionID *uuid.UUID
+	Quantity  int
+	Status    Status
+	Reason    string
+	ExpiresAt *time.Time // nil holds until explicitly released
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewReservation creates an active reservation
+func NewReservation(productID, userID uuid.UUID, quantity int, ttl time.Duration, now time.Time) (*Reservation, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	r := &Reservation{
+		ID:        uuid.New(),
+		ProductID: productID,
+		UserID:    userID,
+		Quantity:  quantity,
+		Status:    StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		r.ExpiresAt = &expiresAt
+	}
+	return r, nil
+}
+
+// IsExpired reports whether an active reservation has passed its expiry
+func (r *Reservation) IsExpired(now time.Time) bool {
+	return r.Status == StatusActive && r.ExpiresAt != nil && now.After(*r.ExpiresAt)
+}
+
+// Repository defines the interface for stock reservation data access.
+// Implementations must update stock and reservation state atomically.
+type Repository interface {
+	// Reserve deducts the quantity from available stock and stores the reservation,
+	// failing with ErrInsufficientStock if not enough units are available
+	Reserve(ctx context.Context, r *Reservation) error
+
+	// Commit marks an active reservation as sold
+	Commit(ctx context.Context, id uuid.UUID) error
+
+	// Release returns an active reservation's units to available stock
+	Release(ctx context.Context, id uuid.UUID, reason string) error
+
+	// Assign moves an active reservation to an order with a new expiry
+	Assign(ctx context.Context, id uuid.UUID, orderID uuid.UUID, expiresAt *time.Time) error
+
+	// ListActiveByOrder retrieves the active reservations of an order
+	ListActiveByOrder(ctx context.Context, orderID uuid.UUID) ([]*Reservation, error)
+
+	// GetActiveByAuction retrieves the active reservation of an auction
+	GetActiveByAuction(ctx context.Context, auctionID uuid.UUID) (*Reservation, error)
+
+	// ListExpired retrieves active reservations that expired before the given time
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*Reservation, error)
+
+	// Restock returns sold units of a product to available stock
+	Restock(ctx context.Context, productID uuid.UUID, quantity int) error
+}
@@ -0,0 +1,144 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrInvoiceNotFound = errors.New("invoice not found")
+	ErrInvoiceExists   = errors.New("order already has an invoice")
+	ErrNotInvoiceable  = errors.New("order has not been paid")
+)
+
+// Party is the seller or buyer named on an invoice
+type Party struct {
+	ID      uuid.UUID      `json:"id"`
+	Name    string         `json:"name"`
+	Email   string         `json:"email,omitempty"`
+	Address *order.Address `json:"address,omitempty"`
+}
+
+// Line is an invoiced order item
+type Line struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	Description     string    `json:"description"`
+	Quantity        int       `json:"quantity"`
+	UnitPrice       float64   `json:"unit_price"`
+	Discount        float64   `json:"discount"`
+	TaxJurisdiction string    `json:"tax_jurisdiction,omitempty"`
+	TaxRateBps      int       `json:"tax_rate_bps"`
+	TaxAmount       float64   `json:"tax_amount"`
+	Total           float64   `json:"total"` // after discount, before tax
+}
+
+// Invoice is a seller's numbered invoice for a paid order. Its contents are
+// fixed when issued; later order changes such as refunds do not alter it.
+type Invoice struct {
+	ID           uuid.UUID
+	Number       string // seller-scoped, e.g. "INV-1A2B3C4D-000042"
+	Sequence     int    // position in the seller's numbering
+	OrderID      uuid.UUID
+	Seller       Party
+	Buyer        Party
+	Lines        []Line
+	Subtotal     float64
+	Discount     float64
+	ShippingCost float64
+	TaxAmount    float64
+	TaxInclusive bool
+	Total        float64
+	CouponCode   string
+	PaidAt       time.Time
+	IssuedAt     time.Time
+	DocumentKey  string // storage key of the rendered PDF, empty until rendered
+}
+
+// IsInvoiceable reports whether an order has been paid for and can be invoiced
+func IsInvoiceable(o *order.Order) bool {
+	return o.PaidAt != nil
+}
+
+// NewInvoice drafts the invoice for a paid order. The number is assigned
+// when the repository stores it. describe returns a product's description.
+func NewInvoice(o *order.Order, seller, buyer Party, describe func(productID uuid.UUID) string, now time.Time) (*Invoice, error) {
+	if !IsInvoiceable(o) {
+		return nil, ErrNotInvoiceable
+	}
+
+	inv := &Invoice{
+		ID:           uuid.New(),
+		OrderID:      o.ID,
+		Seller:       seller,
+		Buyer:        buyer,
+		Lines:        make([]Line, len(o.Items)),
+		Subtotal:     o.Subtotal,
+		Discount:     o.DiscountAmount,
+		ShippingCost: o.ShippingCost,
+		TaxAmount:    o.TaxAmount,
+		TaxInclusive: o.TaxInclusive,
+		Total:        o.TotalAmount,
+		CouponCode:   o.CouponCode,
+		PaidAt:       *o.PaidAt,
+		IssuedAt:     now,
+	}
+	if inv.Buyer.Address == nil {
+		inv.Buyer.Address = o.BillingAddress
+	}
+	if inv.Buyer.Address == nil {
+		inv.Buyer.Address = o.ShippingAddress
+	}
+
+	for i, item := range o.Items {
+		inv.Lines[i] = Line{
+			ProductID:       item.ProductID,
+			Description:     describe(item.ProductID),
+			Quantity:        item.Quantity,
+			UnitPrice:       item.UnitPrice,
+			Discount:        item.DiscountAmount,
+			TaxJurisdiction: item.TaxJurisdiction,
+			TaxRateBps:      item.TaxRateBps,
+			TaxAmount:       item.TaxAmount,
+			Total:           item.Total - item.DiscountAmount,
+		}
+	}
+	return inv, nil
+}
+
+// AssignNumber sets the invoice's place in the seller's numbering
+func (inv *Invoice) AssignNumber(sequence int) {
+	inv.Sequence = sequence
+	inv.Number = fmt.Sprintf("INV-%s-%06d", strings.ToUpper(inv.Seller.ID.String()[:8]), sequence)
+}
+
+// Renderer renders an invoice as a printable document
+type Renderer interface {
+	// Render returns the document bytes
+	Render(inv *Invoice) ([]byte, error)
+
+	// ContentType returns the MIME type of rendered documents
+	ContentType() string
+}
+
+// Repository defines the interface for invoice data access
+type Repository interface {
+	// Create stores an invoice under the seller's next number, failing with
+	// ErrInvoiceExists if the order is already invoiced
+	Create(ctx context.Context, inv *Invoice) error
+
+	// GetByOrderID retrieves an order's invoice
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) (*Invoice, error)
+
+	// ListBySeller retrieves a seller's invoices issued in [from, to), in number order
+	ListBySeller(ctx context.Context, sellerID uuid.UUID, from, to time.Time) ([]*Invoice, error)
+
+	// SetDocument records where an invoice's rendered document is stored
+	SetDocument(ctx context.Context, id uuid.UUID, key string) error
+}
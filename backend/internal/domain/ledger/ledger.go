@@ -0,0 +1,317 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrAccountNotFound      = errors.New("ledger account not found")
+	ErrTransactionNotFound  = errors.New("ledger transaction not found")
+	ErrUnbalanced           = errors.New("ledger transaction does not balance")
+	ErrDuplicateTransaction = errors.New("ledger transaction already posted")
+	ErrInsufficientBalance  = errors.New("insufficient account balance")
+	ErrPayoutNotFound       = errors.New("payout not found")
+	ErrFeeRuleNotFound      = errors.New("fee rule not found")
+)
+
+// AccountType represents the purpose of a ledger account
+type AccountType string
+
+const (
+	// AccountBuyerPayments is the platform-wide source of money paid in by buyers
+	AccountBuyerPayments AccountType = "buyer_payments"
+	// AccountPlatformFees accumulates the marketplace's fees
+	AccountPlatformFees AccountType = "platform_fees"
+	// AccountSellerPending holds a seller's share of orders not yet delivered
+	AccountSellerPending AccountType = "seller_pending"
+	// AccountSellerAvailable holds a seller's share that can be paid out
+	AccountSellerAvailable AccountType = "seller_available"
+	// AccountPayouts accumulates what has been sent to a seller
+	AccountPayouts AccountType = "payouts"
+)
+
+// AccountKey identifies an account; platform accounts have no owner
+type AccountKey struct {
+	Type    AccountType
+	OwnerID *uuid.UUID
+}
+
+// PlatformAccount returns the key of a platform-wide account
+func PlatformAccount(t AccountType) AccountKey {
+	return AccountKey{Type: t}
+}
+
+// SellerAccount returns the key of a seller's account
+func SellerAccount(t AccountType, sellerID uuid.UUID) AccountKey {
+	return AccountKey{Type: t, OwnerID: &sellerID}
+}
+
+// Account is a ledger account. Balances are in minor currency units;
+// money owed to a seller or earned by the platform is positive.
+type Account struct {
+	ID        uuid.UUID
+	Type      AccountType
+	OwnerID   *uuid.UUID
+	Currency  string
+	Balance   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Kind represents the business event a transaction records
+type Kind string
+
+const (
+	KindOrderPayment   Kind = "order_payment"
+	KindOrderRelease   Kind = "order_release"
+	KindRefund         Kind = "refund"
+	KindPayout         Kind = "payout"
+	KindPayoutReversal Kind = "payout_reversal"
+)
+
+// Transaction is a balanced set of entries. Each (Kind, Reference) pair is posted once.
+type Transaction struct {
+	ID          uuid.UUID
+	Kind        Kind
+	Reference   uuid.UUID
+	OrderID     *uuid.UUID
+	Description string
+	Entries     []Entry
+	CreatedAt   time.Time
+}
+
+// Entry moves an amount into (positive) or out of (negative) an account
+type Entry struct {
+	ID            uuid.UUID
+	TransactionID uuid.UUID
+	AccountID     uuid.UUID
+	Account       AccountKey
+	Amount        int64
+}
+
+// NewTransaction creates an empty transaction
+func NewTransaction(kind Kind, reference uuid.UUID, description string, now time.Time) *Transaction {
+	return &Transaction{
+		ID:          uuid.New(),
+		Kind:        kind,
+		Reference:   reference,
+		Description: description,
+		CreatedAt:   now,
+	}
+}
+
+// Add appends an entry; zero amounts are skipped
+func (t *Transaction) Add(account AccountKey, amount int64) {
+	if amount == 0 {
+		return
+	}
+	t.Entries = append(t.Entries, Entry{
+		ID:            uuid.New(),
+		TransactionID: t.ID,
+		Account:       account,
+		Amount:        amount,
+	})
+}
+
+// Validate checks that the transaction has entries summing to zero
+func (t *Transaction) Validate() error {
+	if len(t.Entries) < 2 {
+		return ErrUnbalanced
+	}
+	var sum int64
+	for _, e := range t.Entries {
+		sum += e.Amount
+	}
+	if sum != 0 {
+		return ErrUnbalanced
+	}
+	return nil
+}
+
+// StatementLine is an entry of an account together with its transaction
+type StatementLine struct {
+	EntryID     uuid.UUID
+	Kind        Kind
+	Reference   uuid.UUID
+	OrderID     *uuid.UUID
+	Description string
+	Account     AccountType
+	Amount      int64
+	CreatedAt   time.Time
+}
+
+// FeeRule sets the platform fee for a product category and/or seller tier.
+// A rule with neither applies to everything not matched by a more specific rule.
+type FeeRule struct {
+	ID          uuid.UUID
+	CategoryID  *uuid.UUID
+	SellerTier  string
+	PercentBps  int   // basis points of the item total
+	FixedAmount int64 // minor units per order line
+	CreatedAt   time.Time
+}
+
+// Matches reports whether the rule applies to a category and seller tier
+func (r *FeeRule) Matches(categoryID *uuid.UUID, tier string) bool {
+	if r.CategoryID != nil && (categoryID == nil || *r.CategoryID != *categoryID) {
+		return false
+	}
+	if r.SellerTier != "" && r.SellerTier != tier {
+		return false
+	}
+	return true
+}
+
+// Specificity ranks rules: category and tier beats category beats tier beats default
+func (r *FeeRule) Specificity() int {
+	score := 0
+	if r.CategoryID != nil {
+		score += 2
+	}
+	if r.SellerTier != "" {
+		score++
+	}
+	return score
+}
+
+// Fee computes the fee for an amount in minor units, never exceeding the amount
+func (r *FeeRule) Fee(amount int64) int64 {
+	fee := amount*int64(r.PercentBps)/10000 + r.FixedAmount
+	if fee > amount {
+		fee = amount
+	}
+	if fee < 0 {
+		fee = 0
+	}
+	return fee
+}
+
+// SelectFeeRule returns the most specific matching rule, or nil
+func SelectFeeRule(rules []*FeeRule, categoryID *uuid.UUID, tier string) *FeeRule {
+	var best *FeeRule
+	for _, r := range rules {
+		if !r.Matches(categoryID, tier) {
+			continue
+		}
+		if best == nil || r.Specificity() > best.Specificity() {
+			best = r
+		}
+	}
+	return best
+}
+
+// PayoutStatus represents the status of a payout
+type PayoutStatus string
+
+const (
+	PayoutPending PayoutStatus = "pending"
+	PayoutPaid    PayoutStatus = "paid"
+	PayoutFailed  PayoutStatus = "failed"
+)
+
+// Payout is a transfer of a seller's available balance to the seller
+type Payout struct {
+	ID                uuid.UUID
+	SellerID          uuid.UUID
+	Amount            int64
+	Currency          string
+	Status            PayoutStatus
+	Provider          string
+	ProviderReference string
+	FailureReason     string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewPayout creates a pending payout
+func NewPayout(sellerID uuid.UUID, amount int64, currency, provider string, now time.Time) *Payout {
+	return &Payout{
+		ID:        uuid.New(),
+		SellerID:  sellerID,
+		Amount:    amount,
+		Currency:  currency,
+		Status:    PayoutPending,
+		Provider:  provider,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// MarkPaid records a successful transfer
+func (p *Payout) MarkPaid(reference string, now time.Time) {
+	p.Status = PayoutPaid
+	p.ProviderReference = reference
+	p.UpdatedAt = now
+}
+
+// MarkFailed records a failed transfer
+func (p *Payout) MarkFailed(reason string, now time.Time) {
+	p.Status = PayoutFailed
+	p.FailureReason = reason
+	p.UpdatedAt = now
+}
+
+// PayoutProvider sends money to sellers
+type PayoutProvider interface {
+	// Name returns the provider name stored on payouts
+	Name() string
+
+	// Send transfers the payout amount and returns the provider reference
+	Send(ctx context.Context, req PayoutRequest) (string, error)
+}
+
+// PayoutRequest represents a transfer request
+type PayoutRequest struct {
+	PayoutID       uuid.UUID
+	SellerID       uuid.UUID
+	Amount         int64
+	Currency       string
+	IdempotencyKey string
+}
+
+// Repository defines the interface for ledger data access
+type Repository interface {
+	// Post stores a balanced transaction and updates account balances atomically,
+	// creating accounts on first use. Returns ErrDuplicateTransaction if the
+	// (Kind, Reference) pair was already posted.
+	Post(ctx context.Context, t *Transaction) error
+
+	// GetTransaction retrieves a posted transaction by kind and reference
+	GetTransaction(ctx context.Context, kind Kind, reference uuid.UUID) (*Transaction, error)
+
+	// GetAccount retrieves an account by key
+	GetAccount(ctx context.Context, key AccountKey) (*Account, error)
+
+	// OrderBalance sums the entries an order's transactions made on an account
+	OrderBalance(ctx context.Context, orderID uuid.UUID, key AccountKey) (int64, error)
+
+	// ListStatement retrieves the entries of an owner's accounts, newest first
+	ListStatement(ctx context.Context, ownerID uuid.UUID, page, pageSize int) ([]*StatementLine, int64, error)
+
+	// ListPayableAccounts retrieves seller available accounts with at least minBalance
+	ListPayableAccounts(ctx context.Context, minBalance int64, limit int) ([]*Account, error)
+
+	// CreatePayout stores a payout and posts its transaction, failing with
+	// ErrInsufficientBalance if the seller's available balance is too low
+	CreatePayout(ctx context.Context, payout *Payout, t *Transaction) error
+
+	// UpdatePayout updates a payout
+	UpdatePayout(ctx context.Context, payout *Payout) error
+
+	// ListPayouts retrieves a seller's payouts, newest first
+	ListPayouts(ctx context.Context, sellerID uuid.UUID, page, pageSize int) ([]*Payout, int64, error)
+
+	// ListFeeRules retrieves all fee rules
+	ListFeeRules(ctx context.Context) ([]*FeeRule, error)
+
+	// CreateFeeRule creates a fee rule
+	CreateFeeRule(ctx context.Context, rule *FeeRule) error
+
+	// DeleteFeeRule deletes a fee rule
+	DeleteFeeRule(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,149 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTransactionValidate(t *testing.T) {
+	seller := uuid.New()
+	buyers := PlatformAccount(AccountBuyerPayments)
+	fees := PlatformAccount(AccountPlatformFees)
+	pending := SellerAccount(AccountSellerPending, seller)
+
+	tests := []struct {
+		name    string
+		entries func(tx *Transaction)
+		wantErr error
+	}{
+		{
+			name: "order payment split between seller and platform",
+			entries: func(tx *Transaction) {
+				tx.Add(buyers, -10000)
+				tx.Add(pending, 9000)
+				tx.Add(fees, 1000)
+			},
+		},
+		{
+			name: "zero entries are skipped",
+			entries: func(tx *Transaction) {
+				tx.Add(buyers, -10000)
+				tx.Add(fees, 0)
+				tx.Add(pending, 10000)
+			},
+		},
+		{
+			name: "entries do not sum to zero",
+			entries: func(tx *Transaction) {
+				tx.Add(buyers, -10000)
+				tx.Add(pending, 9000)
+				tx.Add(fees, 999)
+			},
+			wantErr: ErrUnbalanced,
+		},
+		{
+			name: "single entry",
+			entries: func(tx *Transaction) {
+				tx.Add(buyers, -10000)
+			},
+			wantErr: ErrUnbalanced,
+		},
+		{
+			name: "only zero entries",
+			entries: func(tx *Transaction) {
+				tx.Add(buyers, 0)
+				tx.Add(pending, 0)
+			},
+			wantErr: ErrUnbalanced,
+		},
+		{
+			name:    "no entries",
+			entries: func(tx *Transaction) {},
+			wantErr: ErrUnbalanced,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := NewTransaction(KindOrderPayment, uuid.New(), tt.name, time.Now())
+			tt.entries(tx)
+			if err := tx.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransactionAddSkipsZero(t *testing.T) {
+	tx := NewTransaction(KindRefund, uuid.New(), "refund", time.Now())
+	tx.Add(PlatformAccount(AccountPlatformFees), 0)
+	if len(tx.Entries) != 0 {
+		t.Fatalf("Add(0) appended an entry: %+v", tx.Entries)
+	}
+
+	tx.Add(PlatformAccount(AccountPlatformFees), -250)
+	if len(tx.Entries) != 1 || tx.Entries[0].TransactionID != tx.ID || tx.Entries[0].Amount != -250 {
+		t.Errorf("Add(-250) entries = %+v", tx.Entries)
+	}
+}
+
+func TestFeeRuleFee(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   FeeRule
+		amount int64
+		want   int64
+	}{
+		{name: "percentage", rule: FeeRule{PercentBps: 1000}, amount: 10000, want: 1000},
+		{name: "percentage rounds down", rule: FeeRule{PercentBps: 250}, amount: 999, want: 24},
+		{name: "percentage plus fixed", rule: FeeRule{PercentBps: 500, FixedAmount: 30}, amount: 2000, want: 130},
+		{name: "capped at the amount", rule: FeeRule{PercentBps: 500, FixedAmount: 300}, amount: 200, want: 200},
+		{name: "never negative", rule: FeeRule{FixedAmount: -500}, amount: 200, want: 0},
+		{name: "no fee", rule: FeeRule{}, amount: 5000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Fee(tt.amount); got != tt.want {
+				t.Errorf("Fee(%d) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectFeeRule(t *testing.T) {
+	category := uuid.New()
+	other := uuid.New()
+
+	fallback := &FeeRule{PercentBps: 1000}
+	gold := &FeeRule{SellerTier: "gold", PercentBps: 800}
+	electronics := &FeeRule{CategoryID: &category, PercentBps: 600}
+	goldElectronics := &FeeRule{CategoryID: &category, SellerTier: "gold", PercentBps: 400}
+	rules := []*FeeRule{goldElectronics, electronics, gold, fallback}
+
+	tests := []struct {
+		name       string
+		rules      []*FeeRule
+		categoryID *uuid.UUID
+		tier       string
+		want       *FeeRule
+	}{
+		{name: "category and tier", rules: rules, categoryID: &category, tier: "gold", want: goldElectronics},
+		{name: "category beats tier", rules: rules, categoryID: &category, tier: "silver", want: electronics},
+		{name: "tier", rules: rules, categoryID: &other, tier: "gold", want: gold},
+		{name: "default", rules: rules, categoryID: &other, tier: "silver", want: fallback},
+		{name: "no category", rules: rules, tier: "silver", want: fallback},
+		{name: "no match", rules: []*FeeRule{electronics}, categoryID: &other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectFeeRule(tt.rules, tt.categoryID, tt.tier); got != tt.want {
+				t.Errorf("SelectFeeRule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
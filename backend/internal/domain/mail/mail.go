@@ -0,0 +1,16 @@
+package mail
+
+import "context"
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email
+type Sender interface {
+	// Send delivers msg. The sender supplies the From address.
+	Send(ctx context.Context, msg *Message) error
+}
@@ -0,0 +1,326 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	ErrNotCancellable    = errors.New("order can no longer be cancelled")
+	ErrPaymentOverdue    = errors.New("order payment deadline has passed")
+	ErrShippingLocked    = errors.New("shipping can no longer be changed")
+	ErrStatusConflict    = errors.New("order status was changed concurrently")
+)
+
+const (
+	// AuctionPaymentWindow is how long an auction winner has to pay
+	AuctionPaymentWindow = 48 * time.Hour
+	// AuctionPaymentGracePeriod is how long past the deadline an auction
+	// winner may still pay before the order is cancelled for non-payment
+	AuctionPaymentGracePeriod = 24 * time.Hour
+	// CheckoutPaymentWindow is how long a cart checkout holds stock awaiting payment
+	CheckoutPaymentWindow = 15 * time.Minute
+)
+
+// Status represents the lifecycle status of an order
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusPaid      Status = "paid"
+	StatusShipped   Status = "shipped"
+	StatusDelivered Status = "delivered"
+	StatusCancelled Status = "cancelled"
+	StatusRefunded  Status = "refunded"
+)
+
+// transitions lists the statuses each status may move to
+var transitions = map[Status][]Status{
+	StatusPending:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusShipped, StatusCancelled, StatusRefunded},
+	StatusShipped:   {StatusDelivered, StatusRefunded},
+	StatusDelivered: {StatusRefunded},
+}
+
+// Order represents a purchase by a buyer from a single seller
+type Order struct {
+	ID              uuid.UUID
+	BuyerID         uuid.UUID
+	SellerID        uuid.UUID
+	AuctionID       *uuid.UUID
+	Status          Status
+	Items           []Item
+	Subtotal        float64
+	TaxAmount       float64
+	TaxInclusive    bool // TaxAmount is already part of Subtotal
+	ShippingCost    float64
+	DiscountAmount  float64
+	CouponCode      string
+	TotalAmount     float64
+	ShippingAddress *Address
+	BillingAddress  *Address
+	PaymentID       *uuid.UUID
+	Carrier         string
+	TrackingNumber  string
+	Notes           string
+	CancelReason    string
+	PaymentDueAt    *time.Time
+	PaidAt          *time.Time
+	ShippedAt       *time.Time
+	DeliveredAt     *time.Time
+	CancelledAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Item represents a line item of an order
+type Item struct {
+	ID        uuid.UUID
+	OrderID   uuid.UUID
+	ProductID uuid.UUID
+	Quantity  int
+	UnitPrice float64
+	Total     float64
+
+	// Coupon discount taken off Total at checkout
+	DiscountAmount float64
+
+	// Tax breakdown recorded at checkout
+	TaxJurisdiction string
+	TaxRateBps      int
+	TaxAmount       float64
+}
+
+// Address represents a postal address
+type Address struct {
+	Name       string `json:"name"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	Phone      string `json:"phone,omitempty"`
+}
+
+// NewAuctionOrder creates a pending order for an auction winner
+func NewAuctionOrder(auctionID, buyerID, sellerID, productID uuid.UUID, amount float64, now time.Time) *Order {
+	orderID := uuid.New()
+	dueAt := now.Add(AuctionPaymentWindow)
+	return &Order{
+		ID:        orderID,
+		BuyerID:   buyerID,
+		SellerID:  sellerID,
+		AuctionID: &auctionID,
+		Status:    StatusPending,
+		Items: []Item{{
+			ID:        uuid.New(),
+			OrderID:   orderID,
+			ProductID: productID,
+			Quantity:  1,
+			UnitPrice: amount,
+			Total:     amount,
+		}},
+		Subtotal:     amount,
+		TotalAmount:  amount,
+		PaymentDueAt: &dueAt,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// NewOrder creates a pending order for items bought from a single seller
+func NewOrder(buyerID, sellerID uuid.UUID, items []Item, paymentWindow time.Duration, now time.Time) *Order {
+	o := &Order{
+		ID:        uuid.New(),
+		BuyerID:   buyerID,
+		SellerID:  sellerID,
+		Status:    StatusPending,
+		Items:     make([]Item, 0, len(items)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, item := range items {
+		item.ID = uuid.New()
+		item.OrderID = o.ID
+		item.Total = item.UnitPrice * float64(item.Quantity)
+		o.Items = append(o.Items, item)
+		o.Subtotal += item.Total
+	}
+	o.RecalculateTotal()
+	if paymentWindow > 0 {
+		dueAt := now.Add(paymentWindow)
+		o.PaymentDueAt = &dueAt
+	}
+	return o
+}
+
+// CanTransition reports whether the order may move to the given status
+func (o *Order) CanTransition(to Status) bool {
+	for _, s := range transitions[o.Status] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsParticipant reports whether the user is the buyer or seller of the order
+func (o *Order) IsParticipant(userID uuid.UUID) bool {
+	return o.BuyerID == userID || o.SellerID == userID
+}
+
+// IsPaymentOverdue reports whether a pending order has passed its payment
+// deadline, including the grace period auction orders get
+func (o *Order) IsPaymentOverdue(now time.Time) bool {
+	if o.Status != StatusPending || o.PaymentDueAt == nil {
+		return false
+	}
+	deadline := *o.PaymentDueAt
+	if o.AuctionID != nil {
+		deadline = deadline.Add(AuctionPaymentGracePeriod)
+	}
+	return now.After(deadline)
+}
+
+// RecalculateTotal recomputes the total from its components
+func (o *Order) RecalculateTotal() {
+	tax := o.TaxAmount
+	if o.TaxInclusive {
+		tax = 0
+	}
+	o.TotalAmount = o.Subtotal + tax + o.ShippingCost - o.DiscountAmount
+	if o.TotalAmount < 0 {
+		o.TotalAmount = 0
+	}
+}
+
+// ApplyDiscount records a coupon's discount on each item and on the order,
+// then recomputes the total. discountOf returns an item's share of the discount.
+func (o *Order) ApplyDiscount(code string, discountOf func(productID uuid.UUID) float64) {
+	o.CouponCode = code
+	o.DiscountAmount = 0
+	for i := range o.Items {
+		item := &o.Items[i]
+		item.DiscountAmount = discountOf(item.ProductID)
+		o.DiscountAmount += item.DiscountAmount
+	}
+	o.RecalculateTotal()
+}
+
+// SetShipping sets where the order ships and what shipping costs
+func (o *Order) SetShipping(address *Address, cost float64) {
+	o.ShippingAddress = address
+	o.ShippingCost = cost
+	o.RecalculateTotal()
+}
+
+// MarkPaid records a successful payment
+func (o *Order) MarkPaid(paymentID uuid.UUID, now time.Time) error {
+	if o.IsPaymentOverdue(now) {
+		return ErrPaymentOverdue
+	}
+	if err := o.transition(StatusPaid, now); err != nil {
+		return err
+	}
+	o.PaymentID = &paymentID
+	o.PaidAt = &now
+	return nil
+}
+
+// Ship marks the order as handed to the carrier
+func (o *Order) Ship(carrier, trackingNumber string, now time.Time) error {
+	if err := o.transition(StatusShipped, now); err != nil {
+		return err
+	}
+	o.Carrier = carrier
+	o.TrackingNumber = trackingNumber
+	o.ShippedAt = &now
+	return nil
+}
+
+// Deliver marks the order as received by the buyer
+func (o *Order) Deliver(now time.Time) error {
+	if err := o.transition(StatusDelivered, now); err != nil {
+		return err
+	}
+	o.DeliveredAt = &now
+	return nil
+}
+
+// Cancel cancels an order that has not been paid yet
+func (o *Order) Cancel(reason string, now time.Time) error {
+	if o.Status != StatusPending {
+		return ErrNotCancellable
+	}
+	if err := o.transition(StatusCancelled, now); err != nil {
+		return err
+	}
+	o.CancelReason = reason
+	o.CancelledAt = &now
+	return nil
+}
+
+// MarkRefunded marks the order as fully refunded
+func (o *Order) MarkRefunded(now time.Time) error {
+	return o.transition(StatusRefunded, now)
+}
+
+func (o *Order) transition(to Status, now time.Time) error {
+	if !o.CanTransition(to) {
+		return ErrInvalidTransition
+	}
+	o.Status = to
+	o.UpdatedAt = now
+	return nil
+}
+
+// Repository defines the interface for order data access
+type Repository interface {
+	// Create creates an order with its items
+	Create(ctx context.Context, order *Order) error
+
+	// TransitionStatus saves an order's fields (items are immutable) only if
+	// the stored order is still in status from. It returns ErrStatusConflict
+	// when another writer moved the order first.
+	TransitionStatus(ctx context.Context, order *Order, from Status) error
+
+	// Reprice updates an order's fields and its items' tax breakdown. Like
+	// TransitionStatus it returns ErrStatusConflict if the stored order is
+	// no longer in the order's status.
+	Reprice(ctx context.Context, order *Order) error
+
+	// GetByID retrieves an order with its items
+	GetByID(ctx context.Context, id uuid.UUID) (*Order, error)
+
+	// GetByAuctionID retrieves the order created for an auction
+	GetByAuctionID(ctx context.Context, auctionID uuid.UUID) (*Order, error)
+
+	// List retrieves orders with filtering
+	List(ctx context.Context, filter Filter) ([]*Order, int64, error)
+
+	// ListOverdueAuctionOrders retrieves pending auction orders whose payment
+	// deadline passed before the given time, oldest deadline first
+	ListOverdueAuctionOrders(ctx context.Context, before time.Time, limit int) ([]*Order, error)
+}
+
+// EventPublisher publishes order lifecycle events
+type EventPublisher interface {
+	// PublishOrderRefunded announces a full or partial refund to the buyer
+	PublishOrderRefunded(ctx context.Context, order *Order, amount float64, reason string) error
+}
+
+// Filter represents filter criteria for listing orders
+type Filter struct {
+	BuyerID  *uuid.UUID
+	SellerID *uuid.UUID
+	Status   *Status
+	Page     int
+	PageSize int
+}
@@ -0,0 +1,84 @@
+package order
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TaxCalculator computes the tax owed on an order's lines
+type TaxCalculator interface {
+	// Calculate quotes tax for the lines shipped to the destination.
+	// Destinations without a configured rate are quoted zero tax.
+	Calculate(ctx context.Context, req TaxRequest) (*TaxQuote, error)
+}
+
+// TaxRequest describes what is being taxed and where it ships
+type TaxRequest struct {
+	Destination *Address
+	Lines       []TaxLine
+}
+
+// TaxLine is an order line as seen by the tax calculator
+type TaxLine struct {
+	ProductID  uuid.UUID
+	CategoryID *uuid.UUID
+	Amount     float64 // line total as priced to the buyer, after discounts
+}
+
+// TaxQuote is the tax owed on each line of a TaxRequest, in the same order
+type TaxQuote struct {
+	PricesIncludeTax bool // line amounts already contain the tax
+	Lines            []LineTax
+}
+
+// LineTax is the tax owed on a single line
+type LineTax struct {
+	Jurisdiction string
+	RateBps      int
+	Amount       float64
+}
+
+// Total returns the tax owed across all lines
+func (q *TaxQuote) Total() float64 {
+	var total float64
+	for _, l := range q.Lines {
+		total += l.Amount
+	}
+	return total
+}
+
+// TaxLines returns the order's items as tax calculator lines, looking up
+// each product's category with categoryOf
+func (o *Order) TaxLines(categoryOf func(productID uuid.UUID) *uuid.UUID) []TaxLine {
+	lines := make([]TaxLine, len(o.Items))
+	for i, item := range o.Items {
+		lines[i] = TaxLine{
+			ProductID:  item.ProductID,
+			CategoryID: categoryOf(item.ProductID),
+			Amount:     item.Total - item.DiscountAmount,
+		}
+	}
+	return lines
+}
+
+// ApplyTax records a quote for the order's items on each item and on the
+// order, then recomputes the total. Tax included in prices is recorded but
+// not added to the total.
+func (o *Order) ApplyTax(q *TaxQuote) {
+	o.TaxAmount = 0
+	o.TaxInclusive = q.PricesIncludeTax
+	for i := range o.Items {
+		item := &o.Items[i]
+		item.TaxJurisdiction = ""
+		item.TaxRateBps = 0
+		item.TaxAmount = 0
+		if i < len(q.Lines) {
+			item.TaxJurisdiction = q.Lines[i].Jurisdiction
+			item.TaxRateBps = q.Lines[i].RateBps
+			item.TaxAmount = q.Lines[i].Amount
+		}
+		o.TaxAmount += item.TaxAmount
+	}
+	o.RecalculateTotal()
+}
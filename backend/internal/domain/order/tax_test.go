@@ -0,0 +1,124 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestOrderApplyTax(t *testing.T) {
+	newOrder := func() *Order {
+		return &Order{
+			Items: []Item{
+				{ProductID: uuid.New(), Total: 100, TaxAmount: 99},
+				{ProductID: uuid.New(), Total: 50, TaxAmount: 99},
+			},
+			Subtotal:       150,
+			ShippingCost:   10,
+			DiscountAmount: 20,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		quote     *TaxQuote
+		wantTax   float64
+		wantTotal float64
+		wantItems []float64
+	}{
+		{
+			name: "exclusive tax is added to the total",
+			quote: &TaxQuote{Lines: []LineTax{
+				{Jurisdiction: "California", RateBps: 725, Amount: 7.25},
+				{Jurisdiction: "California", RateBps: 725, Amount: 3.63},
+			}},
+			wantTax:   10.88,
+			wantTotal: 150.88,
+			wantItems: []float64{7.25, 3.63},
+		},
+		{
+			name: "inclusive tax is recorded but not added",
+			quote: &TaxQuote{PricesIncludeTax: true, Lines: []LineTax{
+				{Jurisdiction: "Germany", RateBps: 1900, Amount: 15.97},
+				{Jurisdiction: "Germany", RateBps: 1900, Amount: 7.98},
+			}},
+			wantTax:   23.95,
+			wantTotal: 140,
+			wantItems: []float64{15.97, 7.98},
+		},
+		{
+			name:      "short quote clears tax on the remaining items",
+			quote:     &TaxQuote{Lines: []LineTax{{Jurisdiction: "US", RateBps: 500, Amount: 5}}},
+			wantTax:   5,
+			wantTotal: 145,
+			wantItems: []float64{5, 0},
+		},
+		{
+			name:      "empty quote clears previous tax",
+			quote:     &TaxQuote{},
+			wantTax:   0,
+			wantTotal: 140,
+			wantItems: []float64{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOrder()
+			o.ApplyTax(tt.quote)
+
+			if !centsEqual(o.TaxAmount, tt.wantTax) {
+				t.Errorf("TaxAmount = %.2f, want %.2f", o.TaxAmount, tt.wantTax)
+			}
+			if !centsEqual(o.TotalAmount, tt.wantTotal) {
+				t.Errorf("TotalAmount = %.2f, want %.2f", o.TotalAmount, tt.wantTotal)
+			}
+			if o.TaxInclusive != tt.quote.PricesIncludeTax {
+				t.Errorf("TaxInclusive = %v, want %v", o.TaxInclusive, tt.quote.PricesIncludeTax)
+			}
+			for i, want := range tt.wantItems {
+				if !centsEqual(o.Items[i].TaxAmount, want) {
+					t.Errorf("item %d TaxAmount = %.2f, want %.2f", i, o.Items[i].TaxAmount, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderRecalculateTotalNeverNegative(t *testing.T) {
+	o := &Order{Subtotal: 10, DiscountAmount: 25}
+	o.RecalculateTotal()
+	if o.TotalAmount != 0 {
+		t.Errorf("TotalAmount = %v, want 0", o.TotalAmount)
+	}
+}
+
+func TestOrderTaxLines(t *testing.T) {
+	category := uuid.New()
+	o := &Order{Items: []Item{
+		{ProductID: uuid.New(), Total: 100, DiscountAmount: 15},
+		{ProductID: uuid.New(), Total: 40},
+	}}
+	categoryOf := func(productID uuid.UUID) *uuid.UUID {
+		if productID == o.Items[0].ProductID {
+			return &category
+		}
+		return nil
+	}
+
+	lines := o.TaxLines(categoryOf)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0].Amount != 85 || lines[0].CategoryID == nil || *lines[0].CategoryID != category {
+		t.Errorf("line 0 = %+v, want amount 85 in category %s", lines[0], category)
+	}
+	if lines[1].Amount != 40 || lines[1].CategoryID != nil {
+		t.Errorf("line 1 = %+v, want amount 40 without category", lines[1])
+	}
+}
+
+func centsEqual(a, b float64) bool {
+	d := a - b
+	return d < 0.005 && d > -0.005
+}
@@ -0,0 +1,101 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Payment method errors
+var (
+	ErrMethodNotFound = errors.New("payment method not found")
+	ErrMethodExpired  = errors.New("payment method has expired")
+)
+
+// ExpiringSoonWindow is how long before expiry a saved card is flagged
+const ExpiringSoonWindow = 30 * 24 * time.Hour
+
+// Method is a saved payment method. Only the provider token and the last
+// four digits are kept; card numbers never reach this service.
+type Method struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Type        string
+	Provider    string
+	MethodRef   string // provider token used to charge the method
+	CustomerRef string // provider customer the method is attached to
+	Last4       string
+	ExpiresAt   *time.Time // end of the expiry month
+	IsDefault   bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewMethod creates a saved method from the provider's tokenization result
+func NewMethod(userID uuid.UUID, provider string, saved *SavedMethod, now time.Time) *Method {
+	m := &Method{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Type:        saved.Type,
+		Provider:    provider,
+		MethodRef:   saved.MethodRef,
+		CustomerRef: saved.CustomerRef,
+		Last4:       saved.Last4,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if saved.ExpMonth >= 1 && saved.ExpMonth <= 12 && saved.ExpYear > 0 {
+		// Cards are valid through the last moment of their expiry month
+		expiresAt := time.Date(saved.ExpYear, time.Month(saved.ExpMonth)+1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)
+		m.ExpiresAt = &expiresAt
+	}
+	return m
+}
+
+// IsExpired reports whether the method can no longer be charged
+func (m *Method) IsExpired(now time.Time) bool {
+	return m.ExpiresAt != nil && now.After(*m.ExpiresAt)
+}
+
+// IsExpiringSoon reports whether the method expires within ExpiringSoonWindow
+func (m *Method) IsExpiringSoon(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.IsExpired(now) && m.ExpiresAt.Sub(now) <= ExpiringSoonWindow
+}
+
+// SaveMethodRequest asks the provider to turn a client-side token into a reusable method
+type SaveMethodRequest struct {
+	Token       string
+	CustomerRef string // empty to create a provider customer
+	UserID      uuid.UUID
+}
+
+// SavedMethod is the provider's description of a reusable method
+type SavedMethod struct {
+	MethodRef   string
+	CustomerRef string
+	Type        string
+	Last4       string
+	ExpMonth    int
+	ExpYear     int
+}
+
+// MethodRepository defines the interface for saved payment method data access.
+// Implementations must keep at most one default method per user.
+type MethodRepository interface {
+	// Create saves a method, clearing the user's other default if it is the default
+	Create(ctx context.Context, method *Method) error
+
+	// GetByID retrieves a method by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Method, error)
+
+	// ListByUser retrieves a user's methods, default first then newest
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*Method, error)
+
+	// SetDefault makes a method the user's only default
+	SetDefault(ctx context.Context, userID, id uuid.UUID) error
+
+	// Delete removes a method, promoting the newest remaining one if it was the default
+	Delete(ctx context.Context, id uuid.UUID) error
+}
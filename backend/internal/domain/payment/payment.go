@@ -0,0 +1,277 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrPaymentNotFound     = errors.New("payment not found")
+	ErrRefundNotFound      = errors.New("refund not found")
+	ErrPaymentDeclined     = errors.New("payment declined")
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrInvalidSignature    = errors.New("invalid webhook signature")
+	ErrInvalidTransition   = errors.New("invalid payment status transition")
+	ErrGatewayUnavailable  = errors.New("payment gateway unavailable")
+	ErrUnknownPaymentEvent = errors.New("unknown payment event")
+)
+
+// DefaultCurrency is used when no currency is specified
+const DefaultCurrency = "USD"
+
+// InFlightTimeout is how long a pending or authorized payment is assumed to
+// still be on its way to a capture
+const InFlightTimeout = 15 * time.Minute
+
+// Status represents the status of a payment
+type Status string
+
+const (
+	StatusPending           Status = "pending"
+	StatusAuthorized        Status = "authorized"
+	StatusCaptured          Status = "captured"
+	StatusFailed            Status = "failed"
+	StatusVoided            Status = "voided"
+	StatusPartiallyRefunded Status = "partially_refunded"
+	StatusRefunded          Status = "refunded"
+)
+
+// Payment represents a payment attempt for an order
+type Payment struct {
+	ID               uuid.UUID
+	OrderID          uuid.UUID
+	UserID           uuid.UUID
+	Amount           float64
+	Currency         string
+	Status           Status
+	Provider         string
+	Method           string
+	TransactionID    string // idempotency key sent to the gateway
+	GatewayReference string // provider-side payment reference
+	FailureReason    string
+	RefundedAmount   float64
+	RefundedAt       *time.Time
+	Metadata         map[string]string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// NewPayment creates a pending payment
+func NewPayment(orderID, userID uuid.UUID, amount float64, currency, provider, method, transactionID string) *Payment {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	now := time.Now()
+	return &Payment{
+		ID:            uuid.New(),
+		OrderID:       orderID,
+		UserID:        userID,
+		Amount:        amount,
+		Currency:      currency,
+		Status:        StatusPending,
+		Provider:      provider,
+		Method:        method,
+		TransactionID: transactionID,
+		Metadata:      map[string]string{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// IsSettled reports whether the payment holds or has taken the buyer's money
+func (p *Payment) IsSettled() bool {
+	return p.Status == StatusAuthorized || p.Status == StatusCaptured ||
+		p.Status == StatusPartiallyRefunded
+}
+
+// IsLive reports whether the payment has taken the buyer's money or may
+// still do so, in which case its order must not be cancelled for non-payment
+func (p *Payment) IsLive(now time.Time) bool {
+	switch p.Status {
+	case StatusCaptured, StatusPartiallyRefunded:
+		return true
+	case StatusPending, StatusAuthorized:
+		return now.Sub(p.UpdatedAt) < InFlightTimeout
+	}
+	return false
+}
+
+// Refundable returns the captured amount not yet refunded
+func (p *Payment) Refundable() float64 {
+	if p.Status != StatusCaptured && p.Status != StatusPartiallyRefunded {
+		return 0
+	}
+	return p.Amount - p.RefundedAmount
+}
+
+// MarkAuthorized records a successful authorization
+func (p *Payment) MarkAuthorized(reference string) error {
+	if p.Status != StatusPending {
+		return ErrInvalidTransition
+	}
+	p.Status = StatusAuthorized
+	p.GatewayReference = reference
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkCaptured records a successful capture
+func (p *Payment) MarkCaptured() error {
+	if p.Status != StatusAuthorized && p.Status != StatusPending {
+		return ErrInvalidTransition
+	}
+	p.Status = StatusCaptured
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkFailed records a failed payment
+func (p *Payment) MarkFailed(reason string) {
+	p.Status = StatusFailed
+	p.FailureReason = reason
+	p.UpdatedAt = time.Now()
+}
+
+// MarkVoided records a released authorization
+func (p *Payment) MarkVoided() error {
+	if p.Status != StatusAuthorized {
+		return ErrInvalidTransition
+	}
+	p.Status = StatusVoided
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// ToMinorUnits converts an amount to the currency's smallest unit (cents)
+func ToMinorUnits(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// FromMinorUnits converts an amount in the currency's smallest unit back to a decimal amount
+func FromMinorUnits(amount int64) float64 {
+	return float64(amount) / 100
+}
+
+// Gateway abstracts a card payment provider
+type Gateway interface {
+	// Name returns the provider name stored on payments
+	Name() string
+
+	// Authorize places a hold for the amount on the payment method
+	Authorize(ctx context.Context, req AuthorizeRequest) (*GatewayResult, error)
+
+	// Capture takes an authorized amount (up to the authorized total)
+	Capture(ctx context.Context, reference string, amount float64) (*GatewayResult, error)
+
+	// Void releases an authorization without capturing it
+	Void(ctx context.Context, reference string) error
+
+	// Refund returns captured money to the buyer
+	Refund(ctx context.Context, req RefundRequest) (*RefundResult, error)
+
+	// VerifyWebhook validates a webhook signature and parses the event
+	VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error)
+
+	// SaveMethod attaches a client-side token to a provider customer for reuse
+	SaveMethod(ctx context.Context, req SaveMethodRequest) (*SavedMethod, error)
+
+	// RemoveMethod detaches a saved method so it can no longer be charged
+	RemoveMethod(ctx context.Context, methodRef string) error
+}
+
+// AuthorizeRequest represents an authorization request
+type AuthorizeRequest struct {
+	Amount         float64
+	Currency       string
+	MethodRef      string
+	CustomerRef    string
+	IdempotencyKey string
+	Description    string
+	Metadata       map[string]string
+}
+
+// GatewayResult represents the provider's answer to an authorize or capture
+type GatewayResult struct {
+	Reference string
+	Status    Status
+}
+
+// RefundRequest represents a refund request
+type RefundRequest struct {
+	Reference      string
+	Amount         float64
+	Currency       string
+	Reason         string
+	IdempotencyKey string
+}
+
+// RefundResult represents the provider's answer to a refund
+type RefundResult struct {
+	Reference string
+	Amount    float64
+}
+
+// WebhookEventType represents the type of provider webhook
+type WebhookEventType string
+
+const (
+	WebhookAuthorized WebhookEventType = "payment.authorized"
+	WebhookCaptured   WebhookEventType = "payment.captured"
+	WebhookFailed     WebhookEventType = "payment.failed"
+	WebhookVoided     WebhookEventType = "payment.voided"
+	WebhookRefunded   WebhookEventType = "payment.refunded"
+)
+
+// WebhookEvent represents a verified provider webhook
+type WebhookEvent struct {
+	ID            string
+	Type          WebhookEventType
+	Reference     string
+	Amount        float64
+	FailureReason string
+}
+
+// Repository defines the interface for payment data access
+type Repository interface {
+	// Create creates a payment
+	Create(ctx context.Context, payment *Payment) error
+
+	// Update updates a payment
+	Update(ctx context.Context, payment *Payment) error
+
+	// GetByID retrieves a payment by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Payment, error)
+
+	// GetByTransactionID retrieves a payment by its idempotency key
+	GetByTransactionID(ctx context.Context, transactionID string) (*Payment, error)
+
+	// GetByReference retrieves a payment by its provider reference
+	GetByReference(ctx context.Context, reference string) (*Payment, error)
+
+	// ListByOrder retrieves all payments of an order, newest first
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*Payment, error)
+
+	// ReserveRefund records a pending refund and atomically adds its amount
+	// to the payment's refunded total, returning the updated payment. It
+	// returns ErrRefundExceedsCaptured if the payment has less than the
+	// amount left to refund.
+	ReserveRefund(ctx context.Context, refund *Refund) (*Payment, error)
+
+	// CompleteRefund marks a reserved refund succeeded at the provider
+	CompleteRefund(ctx context.Context, refund *Refund) error
+
+	// ReleaseRefund removes a pending refund the provider did not make and
+	// takes its amount back off the payment's refunded total
+	ReleaseRefund(ctx context.Context, refund *Refund) error
+
+	// GetRefundByIdempotencyKey retrieves a refund by its idempotency key
+	GetRefundByIdempotencyKey(ctx context.Context, key string) (*Refund, error)
+
+	// ListRefundsByOrder retrieves all refunds of an order, oldest first
+	ListRefundsByOrder(ctx context.Context, orderID uuid.UUID) ([]*Refund, error)
+}
@@ -0,0 +1,82 @@
+package payment
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Refund errors
+var (
+	ErrNotRefundable         = errors.New("payment cannot be refunded")
+	ErrRefundExceedsCaptured = errors.New("refund exceeds captured amount")
+)
+
+// RefundStatus represents the progress of a refund at the provider
+type RefundStatus string
+
+const (
+	// RefundPending holds its amount against the payment while the provider
+	// is asked to refund it
+	RefundPending   RefundStatus = "pending"
+	RefundSucceeded RefundStatus = "succeeded"
+)
+
+// Refund represents money returned to the buyer for a captured payment
+type Refund struct {
+	ID               uuid.UUID
+	PaymentID        uuid.UUID
+	OrderID          uuid.UUID
+	Amount           float64
+	Reason           string
+	InitiatedBy      uuid.UUID
+	IdempotencyKey   string
+	GatewayReference string
+	Status           RefundStatus
+	Restocked        []RestockItem
+	CreatedAt        time.Time
+}
+
+// RestockItem is a quantity of a product returned to stock by a refund
+type RestockItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// NewRefund creates a pending refund record for a payment
+func NewRefund(p *Payment, amount float64, reason string, initiatedBy uuid.UUID, idempotencyKey string, now time.Time) *Refund {
+	return &Refund{
+		ID:             uuid.New(),
+		PaymentID:      p.ID,
+		OrderID:        p.OrderID,
+		Amount:         amount,
+		Reason:         reason,
+		InitiatedBy:    initiatedBy,
+		IdempotencyKey: idempotencyKey,
+		Status:         RefundPending,
+		CreatedAt:      now,
+	}
+}
+
+// MarkSucceeded records the provider's confirmation of the refund
+func (r *Refund) MarkSucceeded(reference string) {
+	r.Status = RefundSucceeded
+	r.GatewayReference = reference
+}
+
+// CanRefund validates a refund amount against the captured amount not yet refunded
+func (p *Payment) CanRefund(amount float64) error {
+	if p.Status != StatusCaptured && p.Status != StatusPartiallyRefunded {
+		return ErrNotRefundable
+	}
+	if amount <= 0 || ToMinorUnits(amount) > ToMinorUnits(p.Refundable()) {
+		return ErrRefundExceedsCaptured
+	}
+	return nil
+}
+
+// IsFullyRefunded reports whether the whole captured amount has been refunded
+func (p *Payment) IsFullyRefunded() bool {
+	return ToMinorUnits(p.RefundedAmount) >= ToMinorUnits(p.Amount)
+}
@@ -0,0 +1,287 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/payment"
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrCouponNotFound    = errors.New("coupon not found")
+	ErrDuplicateCode     = errors.New("coupon code already exists")
+	ErrInvalidCoupon     = errors.New("invalid coupon")
+	ErrCouponInactive    = errors.New("coupon is not active")
+	ErrCouponNotStarted  = errors.New("coupon is not valid yet")
+	ErrCouponExpired     = errors.New("coupon has expired")
+	ErrUsageLimitReached = errors.New("coupon usage limit reached")
+	ErrUserLimitReached  = errors.New("coupon already used the maximum number of times")
+	ErrMinOrderNotMet    = errors.New("order does not meet the coupon minimum")
+	ErrNoEligibleItems   = errors.New("coupon does not apply to any item")
+)
+
+// DiscountType is how a coupon discounts the eligible items
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage"
+	DiscountFixed      DiscountType = "fixed"
+)
+
+// Coupon is a discount code. Seller coupons only discount that seller's
+// items; platform coupons (no SellerID) apply across sellers.
+type Coupon struct {
+	ID             uuid.UUID
+	Code           string
+	Description    string
+	SellerID       *uuid.UUID
+	Type           DiscountType
+	PercentBps     int      // percentage coupons, in basis points
+	Amount         float64  // fixed coupons
+	MaxDiscount    *float64 // caps percentage discounts
+	MinOrderAmount float64  // minimum eligible subtotal
+	ProductIDs     []uuid.UUID
+	CategoryIDs    []uuid.UUID
+	UsageLimit     *int // total redemptions across all users
+	PerUserLimit   *int
+	UsedCount      int
+	StartsAt       *time.Time
+	ExpiresAt      *time.Time
+	Active         bool
+	CreatedBy      uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NormalizeCode returns the canonical form of a coupon code
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate normalizes the coupon and checks its settings are consistent
+func (c *Coupon) Validate() error {
+	c.Code = NormalizeCode(c.Code)
+	if len(c.Code) < 3 || len(c.Code) > 32 {
+		return ErrInvalidCoupon
+	}
+
+	switch c.Type {
+	case DiscountPercentage:
+		if c.PercentBps <= 0 || c.PercentBps > 10000 {
+			return ErrInvalidCoupon
+		}
+	case DiscountFixed:
+		if c.Amount <= 0 {
+			return ErrInvalidCoupon
+		}
+	default:
+		return ErrInvalidCoupon
+	}
+
+	if c.MaxDiscount != nil && *c.MaxDiscount <= 0 {
+		return ErrInvalidCoupon
+	}
+	if c.MinOrderAmount < 0 {
+		return ErrInvalidCoupon
+	}
+	if (c.UsageLimit != nil && *c.UsageLimit <= 0) || (c.PerUserLimit != nil && *c.PerUserLimit <= 0) {
+		return ErrInvalidCoupon
+	}
+	if c.StartsAt != nil && c.ExpiresAt != nil && !c.ExpiresAt.After(*c.StartsAt) {
+		return ErrInvalidCoupon
+	}
+	return nil
+}
+
+// CheckRedeemable reports why the coupon cannot be used right now, if at all
+func (c *Coupon) CheckRedeemable(now time.Time) error {
+	switch {
+	case !c.Active:
+		return ErrCouponInactive
+	case c.StartsAt != nil && now.Before(*c.StartsAt):
+		return ErrCouponNotStarted
+	case c.ExpiresAt != nil && !now.Before(*c.ExpiresAt):
+		return ErrCouponExpired
+	case c.UsageLimit != nil && c.UsedCount >= *c.UsageLimit:
+		return ErrUsageLimitReached
+	}
+	return nil
+}
+
+// Line is a priced item the coupon may apply to
+type Line struct {
+	ProductID  uuid.UUID
+	CategoryID *uuid.UUID
+	SellerID   uuid.UUID
+	Amount     float64
+}
+
+// LineDiscount is the share of a discount taken off one line
+type LineDiscount struct {
+	ProductID uuid.UUID
+	SellerID  uuid.UUID
+	Amount    float64
+}
+
+// Discount is the result of applying a coupon to a set of lines
+type Discount struct {
+	Coupon           *Coupon
+	EligibleSubtotal float64
+	Amount           float64
+	Lines            []LineDiscount // eligible lines only
+}
+
+// ForSeller returns the part of the discount taken off a seller's lines
+func (d *Discount) ForSeller(sellerID uuid.UUID) float64 {
+	var minor int64
+	for _, l := range d.Lines {
+		if l.SellerID == sellerID {
+			minor += payment.ToMinorUnits(l.Amount)
+		}
+	}
+	return payment.FromMinorUnits(minor)
+}
+
+// ForProduct returns the part of the discount taken off a product's line
+func (d *Discount) ForProduct(productID uuid.UUID) float64 {
+	for _, l := range d.Lines {
+		if l.ProductID == productID {
+			return l.Amount
+		}
+	}
+	return 0
+}
+
+// Applies reports whether the coupon's seller and product/category
+// restrictions cover a line
+func (c *Coupon) Applies(l Line) bool {
+	if c.SellerID != nil && *c.SellerID != l.SellerID {
+		return false
+	}
+	if len(c.ProductIDs) == 0 && len(c.CategoryIDs) == 0 {
+		return true
+	}
+	for _, id := range c.ProductIDs {
+		if id == l.ProductID {
+			return true
+		}
+	}
+	if l.CategoryID != nil {
+		for _, id := range c.CategoryIDs {
+			if id == *l.CategoryID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Evaluate computes the discount on the lines. The discount is spread over
+// the eligible lines in proportion to their amounts, to the cent.
+func (c *Coupon) Evaluate(lines []Line, now time.Time) (*Discount, error) {
+	if err := c.CheckRedeemable(now); err != nil {
+		return nil, err
+	}
+
+	var eligible []Line
+	var subtotal int64
+	for _, l := range lines {
+		if c.Applies(l) && l.Amount > 0 {
+			eligible = append(eligible, l)
+			subtotal += payment.ToMinorUnits(l.Amount)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, ErrNoEligibleItems
+	}
+	if subtotal < payment.ToMinorUnits(c.MinOrderAmount) {
+		return nil, ErrMinOrderNotMet
+	}
+
+	var amount int64
+	if c.Type == DiscountPercentage {
+		amount = int64(math.Round(float64(subtotal) * float64(c.PercentBps) / 10000))
+		if c.MaxDiscount != nil && amount > payment.ToMinorUnits(*c.MaxDiscount) {
+			amount = payment.ToMinorUnits(*c.MaxDiscount)
+		}
+	} else {
+		amount = payment.ToMinorUnits(c.Amount)
+	}
+	if amount > subtotal {
+		amount = subtotal
+	}
+
+	d := &Discount{
+		Coupon:           c,
+		EligibleSubtotal: payment.FromMinorUnits(subtotal),
+		Amount:           payment.FromMinorUnits(amount),
+		Lines:            make([]LineDiscount, len(eligible)),
+	}
+	remaining := amount
+	for i, l := range eligible {
+		share := amount * payment.ToMinorUnits(l.Amount) / subtotal
+		if i == len(eligible)-1 {
+			share = remaining
+		}
+		remaining -= share
+		d.Lines[i] = LineDiscount{ProductID: l.ProductID, SellerID: l.SellerID, Amount: payment.FromMinorUnits(share)}
+	}
+	return d, nil
+}
+
+// Redemption records a coupon used on one order. A checkout that splits into
+// several orders shares a Reference and counts as a single use.
+type Redemption struct {
+	ID        uuid.UUID
+	CouponID  uuid.UUID
+	UserID    uuid.UUID
+	OrderID   uuid.UUID
+	Reference uuid.UUID
+	Amount    float64
+	CreatedAt time.Time
+}
+
+// Filter represents filter criteria for listing coupons
+type Filter struct {
+	SellerID     *uuid.UUID
+	PlatformOnly bool
+	Page         int
+	PageSize     int
+}
+
+// Repository defines the interface for coupon data access
+type Repository interface {
+	// Create creates a coupon, failing with ErrDuplicateCode if the code is taken
+	Create(ctx context.Context, coupon *Coupon) error
+
+	// Update updates a coupon's settings (not its usage count)
+	Update(ctx context.Context, coupon *Coupon) error
+
+	// GetByID retrieves a coupon by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Coupon, error)
+
+	// GetByCode retrieves a coupon by its normalized code
+	GetByCode(ctx context.Context, code string) (*Coupon, error)
+
+	// List retrieves coupons with filtering
+	List(ctx context.Context, filter Filter) ([]*Coupon, int64, error)
+
+	// Delete deletes a coupon
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// CountUserUses counts the checkouts a user has used a coupon in
+	CountUserUses(ctx context.Context, couponID, userID uuid.UUID) (int, error)
+
+	// Redeem atomically records one use of a coupon across the given orders,
+	// enforcing the global and per-user limits
+	Redeem(ctx context.Context, coupon *Coupon, redemptions []*Redemption) error
+
+	// ReleaseOrder removes a cancelled order's redemption. The use is given
+	// back once no order of the same checkout still holds it.
+	ReleaseOrder(ctx context.Context, orderID uuid.UUID) error
+}
@@ -0,0 +1,208 @@
+package promotion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/payment"
+	"github.com/google/uuid"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestCouponEvaluate(t *testing.T) {
+	now := time.Now()
+	seller := uuid.New()
+	otherSeller := uuid.New()
+	category := uuid.New()
+	p1, p2, p3 := uuid.New(), uuid.New(), uuid.New()
+
+	lines := []Line{
+		{ProductID: p1, SellerID: seller, Amount: 10.00},
+		{ProductID: p2, SellerID: seller, CategoryID: &category, Amount: 20.00},
+		{ProductID: p3, SellerID: otherSeller, Amount: 30.01},
+	}
+
+	tests := []struct {
+		name      string
+		coupon    Coupon
+		lines     []Line
+		wantErr   error
+		wantTotal float64
+		wantLines map[uuid.UUID]float64 // discount per eligible product
+	}{
+		{
+			name:      "percentage split in proportion to the cent",
+			coupon:    Coupon{Type: DiscountPercentage, PercentBps: 1000},
+			lines:     lines,
+			wantTotal: 6.00,
+			wantLines: map[uuid.UUID]float64{p1: 0.99, p2: 1.99, p3: 3.02},
+		},
+		{
+			name:      "percentage capped by max discount",
+			coupon:    Coupon{Type: DiscountPercentage, PercentBps: 5000, MaxDiscount: floatPtr(5)},
+			lines:     lines[:1],
+			wantTotal: 5.00,
+			wantLines: map[uuid.UUID]float64{p1: 5.00},
+		},
+		{
+			name:      "fixed",
+			coupon:    Coupon{Type: DiscountFixed, Amount: 3},
+			lines:     lines[:2],
+			wantTotal: 3.00,
+			wantLines: map[uuid.UUID]float64{p1: 1.00, p2: 2.00},
+		},
+		{
+			name:      "fixed capped at the eligible subtotal",
+			coupon:    Coupon{Type: DiscountFixed, Amount: 50},
+			lines:     lines[:1],
+			wantTotal: 10.00,
+			wantLines: map[uuid.UUID]float64{p1: 10.00},
+		},
+		{
+			name:      "seller coupon skips other sellers",
+			coupon:    Coupon{Type: DiscountPercentage, PercentBps: 1000, SellerID: &seller},
+			lines:     lines,
+			wantTotal: 3.00,
+			wantLines: map[uuid.UUID]float64{p1: 1.00, p2: 2.00},
+		},
+		{
+			name:      "category restriction",
+			coupon:    Coupon{Type: DiscountFixed, Amount: 5, CategoryIDs: []uuid.UUID{category}},
+			lines:     lines,
+			wantTotal: 5.00,
+			wantLines: map[uuid.UUID]float64{p2: 5.00},
+		},
+		{
+			name:      "product restriction",
+			coupon:    Coupon{Type: DiscountPercentage, PercentBps: 10000, ProductIDs: []uuid.UUID{p3}},
+			lines:     lines,
+			wantTotal: 30.01,
+			wantLines: map[uuid.UUID]float64{p3: 30.01},
+		},
+		{
+			name:    "minimum counts eligible lines only",
+			coupon:  Coupon{Type: DiscountFixed, Amount: 5, SellerID: &seller, MinOrderAmount: 30.01},
+			lines:   lines,
+			wantErr: ErrMinOrderNotMet,
+		},
+		{
+			name:    "no eligible items",
+			coupon:  Coupon{Type: DiscountFixed, Amount: 5, ProductIDs: []uuid.UUID{uuid.New()}},
+			lines:   lines,
+			wantErr: ErrNoEligibleItems,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.coupon
+			c.Active = true
+			d, err := c.Evaluate(tt.lines, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Evaluate() err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if payment.ToMinorUnits(d.Amount) != payment.ToMinorUnits(tt.wantTotal) {
+				t.Errorf("Amount = %.2f, want %.2f", d.Amount, tt.wantTotal)
+			}
+			if len(d.Lines) != len(tt.wantLines) {
+				t.Fatalf("got %d discounted lines, want %d", len(d.Lines), len(tt.wantLines))
+			}
+			var sum int64
+			for _, l := range d.Lines {
+				sum += payment.ToMinorUnits(l.Amount)
+				want, ok := tt.wantLines[l.ProductID]
+				if !ok {
+					t.Errorf("product %s discounted but not eligible", l.ProductID)
+					continue
+				}
+				if payment.ToMinorUnits(l.Amount) != payment.ToMinorUnits(want) {
+					t.Errorf("product %s discount = %.2f, want %.2f", l.ProductID, l.Amount, want)
+				}
+			}
+			if sum != payment.ToMinorUnits(d.Amount) {
+				t.Errorf("line discounts sum to %d cents, want %d", sum, payment.ToMinorUnits(d.Amount))
+			}
+		})
+	}
+}
+
+func TestCouponEvaluateNotRedeemable(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	limit := 1
+	lines := []Line{{ProductID: uuid.New(), SellerID: uuid.New(), Amount: 10}}
+
+	tests := []struct {
+		name    string
+		coupon  Coupon
+		wantErr error
+	}{
+		{name: "inactive", coupon: Coupon{Active: false}, wantErr: ErrCouponInactive},
+		{name: "not started", coupon: Coupon{Active: true, StartsAt: &future}, wantErr: ErrCouponNotStarted},
+		{name: "expired", coupon: Coupon{Active: true, ExpiresAt: &past}, wantErr: ErrCouponExpired},
+		{name: "expires now", coupon: Coupon{Active: true, ExpiresAt: &now}, wantErr: ErrCouponExpired},
+		{name: "used up", coupon: Coupon{Active: true, UsageLimit: &limit, UsedCount: 1}, wantErr: ErrUsageLimitReached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.coupon.Type = DiscountFixed
+			tt.coupon.Amount = 1
+			if _, err := tt.coupon.Evaluate(lines, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Evaluate() err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiscountForSeller(t *testing.T) {
+	seller, other := uuid.New(), uuid.New()
+	d := &Discount{Lines: []LineDiscount{
+		{ProductID: uuid.New(), SellerID: seller, Amount: 0.10},
+		{ProductID: uuid.New(), SellerID: other, Amount: 1.00},
+		{ProductID: uuid.New(), SellerID: seller, Amount: 0.20},
+	}}
+
+	if got := d.ForSeller(seller); got != 0.30 {
+		t.Errorf("ForSeller(seller) = %v, want 0.30", got)
+	}
+	if got := d.ForSeller(uuid.New()); got != 0 {
+		t.Errorf("ForSeller(unknown) = %v, want 0", got)
+	}
+}
+
+func TestCouponValidate(t *testing.T) {
+	zero := 0
+	start := time.Now()
+	end := start.Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		coupon  Coupon
+		wantErr error
+	}{
+		{name: "percentage", coupon: Coupon{Code: " save10 ", Type: DiscountPercentage, PercentBps: 1000}},
+		{name: "fixed", coupon: Coupon{Code: "FIVE", Type: DiscountFixed, Amount: 5}},
+		{name: "code too short", coupon: Coupon{Code: "AB", Type: DiscountFixed, Amount: 5}, wantErr: ErrInvalidCoupon},
+		{name: "percentage over 100", coupon: Coupon{Code: "ALL", Type: DiscountPercentage, PercentBps: 10001}, wantErr: ErrInvalidCoupon},
+		{name: "fixed without amount", coupon: Coupon{Code: "NONE", Type: DiscountFixed}, wantErr: ErrInvalidCoupon},
+		{name: "unknown type", coupon: Coupon{Code: "ODD", Type: "bogo", Amount: 5}, wantErr: ErrInvalidCoupon},
+		{name: "zero usage limit", coupon: Coupon{Code: "ZERO", Type: DiscountFixed, Amount: 5, UsageLimit: &zero}, wantErr: ErrInvalidCoupon},
+		{name: "expires before start", coupon: Coupon{Code: "BACK", Type: DiscountFixed, Amount: 5, StartsAt: &start, ExpiresAt: &end}, wantErr: ErrInvalidCoupon},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.coupon.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
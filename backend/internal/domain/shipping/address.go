@@ -0,0 +1,75 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+// Address book errors
+var (
+	ErrAddressNotFound = errors.New("address not found")
+	ErrInvalidAddress  = errors.New("address is incomplete")
+)
+
+// SavedAddress is an entry of a user's address book
+type SavedAddress struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Label     string // e.g. "Home", "Office"
+	Address   order.Address
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewSavedAddress creates an address book entry
+func NewSavedAddress(userID uuid.UUID, label string, addr order.Address, now time.Time) (*SavedAddress, error) {
+	if err := ValidateAddress(&addr); err != nil {
+		return nil, err
+	}
+	return &SavedAddress{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Label:     label,
+		Address:   addr,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// ValidateAddress normalizes an address and checks it can be shipped to
+func ValidateAddress(a *order.Address) error {
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+	a.State = strings.ToUpper(strings.TrimSpace(a.State))
+	if a.Name == "" || a.Line1 == "" || a.City == "" || a.PostalCode == "" || len(a.Country) != 2 {
+		return ErrInvalidAddress
+	}
+	return nil
+}
+
+// AddressRepository defines the interface for address book data access.
+// Implementations must keep at most one default address per user.
+type AddressRepository interface {
+	// Create saves an address, clearing the user's other default if it is the default
+	Create(ctx context.Context, address *SavedAddress) error
+
+	// Update updates an address's label and fields
+	Update(ctx context.Context, address *SavedAddress) error
+
+	// GetByID retrieves an address by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*SavedAddress, error)
+
+	// ListByUser retrieves a user's addresses, default first then newest
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*SavedAddress, error)
+
+	// SetDefault makes an address the user's only default
+	SetDefault(ctx context.Context, userID, id uuid.UUID) error
+
+	// Delete removes an address, promoting the newest remaining one if it was the default
+	Delete(ctx context.Context, id uuid.UUID) error
+}
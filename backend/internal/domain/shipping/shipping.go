@@ -0,0 +1,236 @@
+package shipping
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/google/uuid"
+)
+
+// Errors
+var (
+	ErrProfileNotFound = errors.New("shipping profile not found")
+	ErrInvalidProfile  = errors.New("invalid shipping profile")
+	ErrNoRoute         = errors.New("seller does not ship to this destination")
+	ErrUnknownCarrier  = errors.New("unknown carrier")
+)
+
+// RateType is how a shipping profile prices a parcel
+type RateType string
+
+const (
+	RateFlat         RateType = "flat"          // one price per order
+	RateWeightTiered RateType = "weight_tiered" // price by billable weight
+)
+
+// VolumetricDivisor converts cubic centimetres to billable grams (5000 cm³ per kg)
+const VolumetricDivisor = 5
+
+// Profile is a seller's shipping price list for a set of destination countries.
+// A profile with no countries is the seller's fallback for everywhere else.
+type Profile struct {
+	ID             uuid.UUID
+	SellerID       uuid.UUID
+	Name           string
+	Countries      []string // ISO country codes, upper case
+	RateType       RateType
+	FlatRate       float64
+	Tiers          []Tier   // ascending by MaxWeightGrams
+	FreeOverAmount *float64 // orders with at least this subtotal ship free
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Tier prices parcels up to and including MaxWeightGrams
+type Tier struct {
+	MaxWeightGrams int     `json:"max_weight_grams"`
+	Rate           float64 `json:"rate"`
+}
+
+// Validate normalizes the profile and checks it can price a parcel
+func (p *Profile) Validate() error {
+	for i, c := range p.Countries {
+		p.Countries[i] = strings.ToUpper(strings.TrimSpace(c))
+		if len(p.Countries[i]) != 2 {
+			return ErrInvalidProfile
+		}
+	}
+	if p.FreeOverAmount != nil && *p.FreeOverAmount < 0 {
+		return ErrInvalidProfile
+	}
+
+	switch p.RateType {
+	case RateFlat:
+		if p.FlatRate < 0 {
+			return ErrInvalidProfile
+		}
+	case RateWeightTiered:
+		if len(p.Tiers) == 0 {
+			return ErrInvalidProfile
+		}
+		sort.Slice(p.Tiers, func(i, j int) bool { return p.Tiers[i].MaxWeightGrams < p.Tiers[j].MaxWeightGrams })
+		for i, t := range p.Tiers {
+			if t.MaxWeightGrams <= 0 || t.Rate < 0 {
+				return ErrInvalidProfile
+			}
+			if i > 0 && t.MaxWeightGrams == p.Tiers[i-1].MaxWeightGrams {
+				return ErrInvalidProfile
+			}
+		}
+	default:
+		return ErrInvalidProfile
+	}
+	return nil
+}
+
+// Covers reports whether the profile lists the destination country
+func (p *Profile) Covers(country string) bool {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	for _, c := range p.Countries {
+		if c == country {
+			return true
+		}
+	}
+	return false
+}
+
+// Price returns the shipping cost of a parcel. Parcels heavier than the top
+// tier are not shippable with the profile.
+func (p *Profile) Price(weightGrams int, subtotal float64) (float64, error) {
+	if p.FreeOverAmount != nil && subtotal >= *p.FreeOverAmount {
+		return 0, nil
+	}
+
+	if p.RateType == RateFlat {
+		return p.FlatRate, nil
+	}
+	for _, t := range p.Tiers {
+		if weightGrams <= t.MaxWeightGrams {
+			return t.Rate, nil
+		}
+	}
+	return 0, ErrNoRoute
+}
+
+// SelectProfile picks the profile for a destination: one listing the country,
+// otherwise the seller's fallback profile
+func SelectProfile(profiles []*Profile, country string) *Profile {
+	var fallback *Profile
+	for _, p := range profiles {
+		if p.Covers(country) {
+			return p
+		}
+		if len(p.Countries) == 0 && fallback == nil {
+			fallback = p
+		}
+	}
+	return fallback
+}
+
+// Parcel is an item to be shipped
+type Parcel struct {
+	Quantity     int
+	WeightGrams  *int
+	DimensionsCm *product.Dimensions
+}
+
+// BillableWeight returns the greater of the actual and volumetric weight of
+// the parcels in grams. Items without a weight or dimensions count as zero.
+func BillableWeight(parcels []Parcel) int {
+	var total int
+	for _, p := range parcels {
+		var actual, volumetric int
+		if p.WeightGrams != nil {
+			actual = *p.WeightGrams
+		}
+		if p.DimensionsCm != nil {
+			d := p.DimensionsCm
+			volumetric = int(math.Ceil(float64(d.Length*d.Width*d.Height) / VolumetricDivisor))
+		}
+		if volumetric > actual {
+			actual = volumetric
+		}
+		total += actual * p.Quantity
+	}
+	return total
+}
+
+// Quote is the shipping cost of one seller's items to a destination
+type Quote struct {
+	SellerID            uuid.UUID
+	ProfileID           *uuid.UUID // nil when the seller has no shipping profiles
+	ProfileName         string
+	BillableWeightGrams int
+	Cost                float64
+}
+
+// Carrier is a parcel carrier buyers can track shipments with
+type Carrier struct {
+	Code                string
+	Name                string
+	TrackingURLTemplate string // {tracking} is replaced with the tracking number
+}
+
+// Carriers lists the supported carriers by code
+var Carriers = map[string]Carrier{
+	"ups":          {Code: "ups", Name: "UPS", TrackingURLTemplate: "https://www.ups.com/track?tracknum={tracking}"},
+	"fedex":        {Code: "fedex", Name: "FedEx", TrackingURLTemplate: "https://www.fedex.com/fedextrack/?trknbr={tracking}"},
+	"usps":         {Code: "usps", Name: "USPS", TrackingURLTemplate: "https://tools.usps.com/go/TrackConfirmAction?tLabels={tracking}"},
+	"dhl":          {Code: "dhl", Name: "DHL", TrackingURLTemplate: "https://www.dhl.com/en/express/tracking.html?AWB={tracking}"},
+	"royal_mail":   {Code: "royal_mail", Name: "Royal Mail", TrackingURLTemplate: "https://www.royalmail.com/track-your-item#/tracking-results/{tracking}"},
+	"pos_malaysia": {Code: "pos_malaysia", Name: "Pos Malaysia", TrackingURLTemplate: "https://tracking.pos.com.my/tracking/{tracking}"},
+	"jnt":          {Code: "jnt", Name: "J&T Express", TrackingURLTemplate: "https://www.jtexpress.my/tracking/{tracking}"},
+	"other":        {Code: "other", Name: "Other"},
+}
+
+// LookupCarrier finds a supported carrier by code
+func LookupCarrier(code string) (Carrier, error) {
+	c, ok := Carriers[strings.ToLower(strings.TrimSpace(code))]
+	if !ok {
+		return Carrier{}, ErrUnknownCarrier
+	}
+	return c, nil
+}
+
+// TrackingURL returns the carrier's tracking page for a shipment, or "" if
+// the carrier has none
+func TrackingURL(carrierCode, trackingNumber string) string {
+	c, err := LookupCarrier(carrierCode)
+	if err != nil || c.TrackingURLTemplate == "" || trackingNumber == "" {
+		return ""
+	}
+	return strings.ReplaceAll(c.TrackingURLTemplate, "{tracking}", escapeTracking(trackingNumber))
+}
+
+func escapeTracking(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ProfileRepository defines the interface for shipping profile data access
+type ProfileRepository interface {
+	// Create creates a profile
+	Create(ctx context.Context, profile *Profile) error
+
+	// Update updates a profile
+	Update(ctx context.Context, profile *Profile) error
+
+	// GetByID retrieves a profile by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*Profile, error)
+
+	// ListBySeller retrieves a seller's profiles, oldest first
+	ListBySeller(ctx context.Context, sellerID uuid.UUID) ([]*Profile, error)
+
+	// Delete deletes a profile
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,53 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrActionTokenInvalid is returned for action tokens that are malformed,
+// expired, already used or superseded by a newer one
+var ErrActionTokenInvalid = errors.New("invalid or expired token")
+
+// TokenPurpose is what an action token allows its holder to do
+type TokenPurpose string
+
+const (
+	PurposeEmailVerification TokenPurpose = "email_verification"
+	PurposePasswordReset     TokenPurpose = "password_reset"
+	PurposeTwoFactorLogin    TokenPurpose = "two_factor_login"
+)
+
+const (
+	// EmailVerificationTTL is how long an email verification link works
+	EmailVerificationTTL = 48 * time.Hour
+	// PasswordResetTTL is how long a password reset link works
+	PasswordResetTTL = time.Hour
+	// TwoFactorChallengeTTL is how long a user has to enter their second
+	// factor after giving their password
+	TwoFactorChallengeTTL = 5 * time.Minute
+)
+
+// ActionClaims identify a single-use action token, either emailed or, for a
+// two-factor login, handed out after the password step
+type ActionClaims struct {
+	ID      uuid.UUID
+	UserID  uuid.UUID
+	Purpose TokenPurpose
+	Email   string // address the token was issued for
+}
+
+// ActionTokenStore tracks the outstanding action token of each purpose for
+// a user, which is what makes tokens single use. Saving a token supersedes
+// the user's previous one of the same purpose.
+type ActionTokenStore interface {
+	// Save records tokenID as the user's outstanding token for purpose
+	Save(ctx context.Context, purpose TokenPurpose, userID, tokenID uuid.UUID, ttl time.Duration) error
+
+	// Consume removes the user's outstanding token for purpose if it is
+	// tokenID, reporting whether it was
+	Consume(ctx context.Context, purpose TokenPurpose, userID, tokenID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// StrikeLifetime is how long a strike counts against a user
+	StrikeLifetime = 180 * 24 * time.Hour
+	// MaxActiveStrikes is how many live strikes a user may have before they
+	// are suspended from bidding
+	MaxActiveStrikes = 3
+)
+
+// StrikeReason explains why a strike was recorded
+type StrikeReason string
+
+const (
+	StrikeNonPayment StrikeReason = "non_payment"
+)
+
+// Strike records a buyer failing a commitment, such as not paying for an
+// auction they won
+type Strike struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Reason    StrikeReason
+	OrderID   *uuid.UUID
+	AuctionID *uuid.UUID
+	CreatedAt time.Time
+}
+
+// StrikeRepository defines the interface for strike data access
+type StrikeRepository interface {
+	// Create records a strike. Recording a second strike for the same order
+	// is a no-op.
+	Create(ctx context.Context, strike *Strike) error
+
+	// CountSince counts a user's strikes recorded at or after since
+	CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
+}
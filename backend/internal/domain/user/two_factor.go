@@ -0,0 +1,81 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCodeCount is how many recovery codes a user is given at a time
+const RecoveryCodeCount = 10
+
+const (
+	// MaxChallengeFailures is how many wrong codes a two-factor login
+	// challenge survives before the user has to give their password again
+	MaxChallengeFailures = 5
+	// MaxTwoFactorFailures is how many wrong codes a user may enter within
+	// TwoFactorLockout, across challenges, before codes are refused
+	MaxTwoFactorFailures = 10
+	// TwoFactorLockout is the window wrong codes are counted over
+	TwoFactorLockout = 15 * time.Minute
+)
+
+// RecoveryCode is a one-off second factor for a user who has lost their
+// authenticator. Only a bcrypt hash of the code is kept.
+type RecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// CanRequireTwoFactor reports whether admins may make two-factor
+// authentication mandatory for role
+func CanRequireTwoFactor(role Role) bool {
+	return role == RoleSeller || role == RoleAdmin
+}
+
+// RecoveryCodeRepository defines the interface for recovery code data access
+type RecoveryCodeRepository interface {
+	// Replace discards a user's recovery codes and saves new ones
+	Replace(ctx context.Context, userID uuid.UUID, codes []*RecoveryCode) error
+
+	// ListUnused retrieves a user's recovery codes that have not been used
+	ListUnused(ctx context.Context, userID uuid.UUID) ([]*RecoveryCode, error)
+
+	// MarkUsed marks a recovery code as used, reporting whether it was still
+	// unused, so a code cannot be spent twice concurrently
+	MarkUsed(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+
+	// CountUnused counts a user's remaining recovery codes
+	CountUnused(ctx context.Context, userID uuid.UUID) (int, error)
+
+	// DeleteByUserID discards all of a user's recovery codes
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// AttemptCounter counts failed attempts under a key within a window, such
+// as wrong two-factor codes per user and per login challenge
+type AttemptCounter interface {
+	// Fail records a failed attempt, returning the failures so far. The
+	// count expires window after the first failure.
+	Fail(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// Failures returns the failures recorded under key
+	Failures(ctx context.Context, key string) (int, error)
+
+	// Reset clears the failures recorded under key
+	Reset(ctx context.Context, key string) error
+}
+
+// TwoFactorPolicyRepository stores which roles admins require two-factor
+// authentication for
+type TwoFactorPolicyRepository interface {
+	// RequiredRoles lists the roles that must use two-factor authentication
+	RequiredRoles(ctx context.Context) ([]Role, error)
+
+	// SetRequired records whether role must use two-factor authentication
+	SetRequired(ctx context.Context, role Role, required bool, updatedBy uuid.UUID) error
+}
@@ -16,6 +16,9 @@ const (
 	RoleAdmin  Role = "admin"
 )
 
+// DefaultSellerTier is the fee tier of sellers without a negotiated tier
+const DefaultSellerTier = "standard"
+
 type User struct {
 	ID            uuid.UUID
 	Email         string
@@ -26,9 +29,15 @@ type User struct {
 	AvatarURL     string
 	Phone         string
 	EmailVerified bool
-	LastLoginAt   *time.Time
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	SellerTier    string // selects marketplace fee rules; "standard" by default
+	// TOTPSecret is set on enrolment and only in use once TwoFactorEnabled
+	// is set by the confirm step
+	TOTPSecret       string
+	TwoFactorEnabled bool
+	TOTPLastStep     int64 // time step of the last accepted code, which cannot be replayed
+	LastLoginAt      *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 func (u *User) CanBid() bool {
@@ -45,12 +54,38 @@ type Repository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+
+	// AdvanceTOTPStep records step as the user's last used TOTP step if it is
+	// later than the stored one, reporting whether it was. A code can
+	// therefore only be used once, even by concurrent requests.
+	AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
 }
 
+// SessionRepository defines the interface for session data access.
+// Sessions are dropped by the store once they expire.
 type SessionRepository interface {
+	// Create saves a new session
 	Create(ctx context.Context, session *Session) error
+
+	// Rotate saves a session's new tokens, keeping its expiry, provided
+	// usedRefreshToken is the session's current refresh token. Otherwise the
+	// used token was already rotated out and ErrRefreshTokenReused is returned.
+	Rotate(ctx context.Context, session *Session, usedRefreshToken string) error
+
+	// GetByID retrieves a live session, returning ErrSessionNotFound once
+	// it has expired or been revoked
+	GetByID(ctx context.Context, id uuid.UUID) (*Session, error)
+
+	// GetByToken retrieves the live session an access or refresh token was issued for
 	GetByToken(ctx context.Context, token string) (*Session, error)
+
+	// ListByUserID retrieves a user's live sessions, newest first
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*Session, error)
+
+	// Delete revokes a session
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// DeleteByUserID revokes all of a user's sessions
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
 
@@ -60,29 +95,58 @@ type PasswordVerifier interface {
 }
 
 type TokenManager interface {
-	Generate(userID uuid.UUID, email string, role Role) (accessToken, refreshToken string, err error)
+	// Generate issues an access and refresh token pair bound to a session
+	Generate(sessionID, userID uuid.UUID, email string, role Role) (accessToken, refreshToken string, err error)
+
+	// Validate validates an access token
 	Validate(token string) (*TokenClaims, error)
+
+	// ValidateRefresh validates a refresh token, setting only the user and
+	// session on the claims
+	ValidateRefresh(token string) (*TokenClaims, error)
+
+	// GenerateActionToken issues a signed token for an emailed action,
+	// such as verifying an address or resetting a password
+	GenerateActionToken(claims ActionClaims, ttl time.Duration) (string, error)
+
+	// ValidateActionToken validates an action token issued for purpose
+	ValidateActionToken(token string, purpose TokenPurpose) (*ActionClaims, error)
+
+	// AccessTokenTTL is how long access tokens stay valid
+	AccessTokenTTL() time.Duration
+
+	// RefreshTokenTTL is how long refresh tokens, and so sessions, stay valid
+	RefreshTokenTTL() time.Duration
 }
 
 type TokenClaims struct {
-	UserID uuid.UUID
-	Email  string
-	Role   Role
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	Email     string
+	Role      Role
 }
 
+// Session is one signed-in device. Each refresh hands out a new refresh
+// token and retires the old one, so the session is the family of all its
+// refresh tokens. Token and RefreshToken are only set on sessions being
+// issued; stores keep hashes of them, not the tokens.
 type Session struct {
 	ID           uuid.UUID
 	UserID       uuid.UUID
 	Token        string
 	RefreshToken string
+	UserAgent    string
+	IPAddress    string
 	ExpiresAt    time.Time
 	CreatedAt    time.Time
 }
 
 var (
-	ErrUserNotFound    = errors.New("user not found")
-	ErrEmailExists     = errors.New("email already exists")
-	ErrInvalidPassword = errors.New("invalid password")
-	ErrInvalidToken    = errors.New("invalid token")
-	ErrSessionExpired  = errors.New("session expired")
-)
\ No newline at end of file
+	ErrUserNotFound       = errors.New("user not found")
+	ErrEmailExists        = errors.New("email already exists")
+	ErrInvalidPassword    = errors.New("invalid password")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrSessionExpired     = errors.New("session expired")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token already used")
+)
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// consumeScript deletes a key only if it still holds the given value.
+// KEYS: outstanding token key. ARGV: token ID.
+// Returns 1 if the token was consumed.
+var consumeScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// ActionTokenStore implements user.ActionTokenStore, keeping one key per
+// user and purpose that holds the outstanding token ID and expires with it
+type ActionTokenStore struct {
+	client *Client
+	prefix string
+}
+
+// NewActionTokenStore creates a new action token store
+func NewActionTokenStore(client *Client) *ActionTokenStore {
+	return &ActionTokenStore{
+		client: client,
+		prefix: "actiontoken:",
+	}
+}
+
+// Save records tokenID as the user's outstanding token for purpose
+func (s *ActionTokenStore) Save(ctx context.Context, purpose user.TokenPurpose, userID, tokenID uuid.UUID, ttl time.Duration) error {
+	return s.client.GetClient().Set(ctx, s.key(purpose, userID), tokenID.String(), ttl).Err()
+}
+
+// Consume removes the user's outstanding token for purpose if it is tokenID
+func (s *ActionTokenStore) Consume(ctx context.Context, purpose user.TokenPurpose, userID, tokenID uuid.UUID) (bool, error) {
+	n, err := consumeScript.Run(ctx, s.client.GetClient(),
+		[]string{s.key(purpose, userID)},
+		tokenID.String(),
+	).Int64()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// key generates the key holding a user's outstanding token for purpose
+func (s *ActionTokenStore) key(purpose user.TokenPurpose, userID uuid.UUID) string {
+	return fmt.Sprintf("%s%s:%s", s.prefix, purpose, userID.String())
+}
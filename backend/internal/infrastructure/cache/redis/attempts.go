@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// failScript counts a failure, starting the window on the first one.
+// KEYS: counter key. ARGV: window in milliseconds.
+// Returns the failures so far.
+var failScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// AttemptCounter implements user.AttemptCounter with one expiring counter
+// per key
+type AttemptCounter struct {
+	client *Client
+	prefix string
+}
+
+// NewAttemptCounter creates a new attempt counter
+func NewAttemptCounter(client *Client) *AttemptCounter {
+	return &AttemptCounter{
+		client: client,
+		prefix: "attempts:",
+	}
+}
+
+// Fail records a failed attempt, returning the failures so far
+func (c *AttemptCounter) Fail(ctx context.Context, key string, window time.Duration) (int, error) {
+	n, err := failScript.Run(ctx, c.client.GetClient(),
+		[]string{c.prefix + key},
+		window.Milliseconds(),
+	).Int()
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Failures returns the failures recorded under key
+func (c *AttemptCounter) Failures(ctx context.Context, key string) (int, error) {
+	n, err := c.client.GetClient().Get(ctx, c.prefix+key).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Reset clears the failures recorded under key
+func (c *AttemptCounter) Reset(ctx context.Context, key string) error {
+	return c.client.GetClient().Del(ctx, c.prefix+key).Err()
+}
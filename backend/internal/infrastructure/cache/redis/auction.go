@@ -8,7 +8,6 @@ import (
 
 	"github.com/blytz/live/backend/internal/domain/auction"
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
 )
 
 // AuctionCache implements auction.Cache interface
@@ -47,52 +46,100 @@ func (c *AuctionCache) DeleteAuctionState(ctx context.Context, auctionID uuid.UU
 	return c.client.Delete(ctx, key)
 }
 
-// IncrementViewerCount increments viewer count for an auction
-func (c *AuctionCache) IncrementViewerCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
-	key := c.key(auctionID) + ":viewers"
-	count, err := c.client.Increment(ctx, key)
+// SetPresence records the viewers connected to one instance. The entry expires
+// after ttl unless refreshed, so viewers held by a dead instance drop out on their own.
+func (c *AuctionCache) SetPresence(ctx context.Context, auctionID uuid.UUID, instanceID string, presence *auction.InstancePresence, ttl time.Duration) error {
+	data, err := json.Marshal(presence)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	// Set expiration
-	c.client.Expire(ctx, key, time.Hour)
-	return int(count), nil
+
+	pipe := c.client.GetClient().TxPipeline()
+	pipe.Set(ctx, c.presenceKey(auctionID, instanceID), data, ttl)
+	pipe.SAdd(ctx, c.instancesKey(auctionID), instanceID)
+	pipe.Expire(ctx, c.instancesKey(auctionID), time.Hour)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// RemovePresence removes an instance's viewers for an auction
+func (c *AuctionCache) RemovePresence(ctx context.Context, auctionID uuid.UUID, instanceID string) error {
+	pipe := c.client.GetClient().TxPipeline()
+	pipe.Del(ctx, c.presenceKey(auctionID, instanceID))
+	pipe.SRem(ctx, c.instancesKey(auctionID), instanceID)
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
-// DecrementViewerCount decrements viewer count for an auction
-func (c *AuctionCache) DecrementViewerCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
-	key := c.key(auctionID) + ":viewers"
-	count, err := c.client.Decrement(ctx, key)
+// GetPresence aggregates viewers across all live instances
+func (c *AuctionCache) GetPresence(ctx context.Context, auctionID uuid.UUID) (*auction.Presence, error) {
+	rdb := c.client.GetClient()
+
+	instances, err := rdb.SMembers(ctx, c.instancesKey(auctionID)).Result()
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	if count < 0 {
-		count = 0
-		c.client.Set(ctx, key, 0, time.Hour)
+	presence := &auction.Presence{}
+	if len(instances) == 0 {
+		return presence, nil
 	}
-	return int(count), nil
-}
 
-// GetViewerCount gets current viewer count
-func (c *AuctionCache) GetViewerCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
-	key := c.key(auctionID) + ":viewers"
-	
-	data, err := c.client.GetClient().Get(ctx, key).Result()
+	keys := make([]string, len(instances))
+	for i, instanceID := range instances {
+		keys[i] = c.presenceKey(auctionID, instanceID)
+	}
+	values, err := rdb.MGet(ctx, keys...).Result()
 	if err != nil {
-		if err == redis.Nil {
-			return 0, nil
+		return nil, err
+	}
+
+	users := make(map[string]struct{})
+	var expired []interface{}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			// Instance stopped heartbeating and its entry expired
+			expired = append(expired, instances[i])
+			continue
 		}
-		return 0, err
+		var p auction.InstancePresence
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			continue
+		}
+		for _, userID := range p.UserIDs {
+			users[userID] = struct{}{}
+		}
+		presence.Anonymous += p.Anonymous
 	}
-	
-	var count int
-	if err := json.Unmarshal([]byte(data), &count); err != nil {
+	if len(expired) > 0 {
+		rdb.SRem(ctx, c.instancesKey(auctionID), expired...)
+	}
+
+	presence.Authenticated = len(users)
+	presence.Viewers = presence.Authenticated + presence.Anonymous
+	return presence, nil
+}
+
+// GetViewerCount gets current cluster-wide viewer count
+func (c *AuctionCache) GetViewerCount(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	presence, err := c.GetPresence(ctx, auctionID)
+	if err != nil {
 		return 0, err
 	}
-	return count, nil
+	return presence.Viewers, nil
 }
 
 // key generates cache key
 func (c *AuctionCache) key(auctionID uuid.UUID) string {
 	return fmt.Sprintf("%s%s:state", c.prefix, auctionID.String())
-}
\ No newline at end of file
+}
+
+// instancesKey generates the key tracking instances with viewers in an auction
+func (c *AuctionCache) instancesKey(auctionID uuid.UUID) string {
+	return fmt.Sprintf("%s%s:instances", c.prefix, auctionID.String())
+}
+
+// presenceKey generates the key holding one instance's viewers
+func (c *AuctionCache) presenceKey(auctionID uuid.UUID, instanceID string) string {
+	return fmt.Sprintf("%s%s:presence:%s", c.prefix, auctionID.String(), instanceID)
+}
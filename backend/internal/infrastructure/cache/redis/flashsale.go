@@ -0,0 +1,148 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/flashsale"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// flashSaleKeyTTL keeps a sale's counters around after it ends so late
+// releases still find them
+const flashSaleKeyTTL = 24 * time.Hour
+
+// reserveScript takes units of a sale for a buyer in one step.
+// KEYS: sold counter, per-buyer hash. ARGV: buyer, quantity, sale quantity,
+// per-buyer limit (0 for none), units already sold when the counter is
+// missing, expiry as a unix timestamp.
+// Returns -1 when sold out, -2 when the buyer's limit is reached and -3 when
+// units were sold but the per-buyer hash is gone, so it must be restored.
+var reserveScript = redis.NewScript(`
+local sold = tonumber(redis.call('GET', KEYS[1]) or ARGV[5])
+local quantity = tonumber(ARGV[2])
+if sold + quantity > tonumber(ARGV[3]) then
+	return -1
+end
+local limit = tonumber(ARGV[4])
+if limit > 0 then
+	if sold > 0 and redis.call('EXISTS', KEYS[2]) == 0 then
+		return -3
+	end
+	local bought = tonumber(redis.call('HGET', KEYS[2], ARGV[1]) or '0')
+	if bought + quantity > limit then
+		return -2
+	end
+end
+redis.call('SET', KEYS[1], sold + quantity)
+redis.call('HINCRBY', KEYS[2], ARGV[1], quantity)
+redis.call('EXPIREAT', KEYS[1], ARGV[6])
+redis.call('EXPIREAT', KEYS[2], ARGV[6])
+return sold + quantity
+`)
+
+// restoreBuyersScript rebuilds a lost per-buyer hash, unless another buyer's
+// request already did. A marker field keeps the hash in existence when no
+// buyer has anything claimed.
+// KEYS: per-buyer hash. ARGV: expiry as a unix timestamp, then buyer and
+// quantity pairs.
+var restoreBuyersScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('HSET', KEYS[1], '_', 0)
+for i = 2, #ARGV, 2 do
+	redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+redis.call('EXPIREAT', KEYS[1], ARGV[1])
+return 1
+`)
+
+// releaseScript gives units back, never taking a counter below zero or
+// recreating one that has expired.
+// KEYS: sold counter, per-buyer hash. ARGV: buyer, quantity.
+var releaseScript = redis.NewScript(`
+local quantity = tonumber(ARGV[2])
+local sold = tonumber(redis.call('GET', KEYS[1]) or '-1')
+if sold >= 0 then
+	redis.call('SET', KEYS[1], math.max(sold - quantity, 0), 'KEEPTTL')
+end
+local bought = tonumber(redis.call('HGET', KEYS[2], ARGV[1]) or '-1')
+if bought > quantity then
+	redis.call('HINCRBY', KEYS[2], ARGV[1], -quantity)
+elseif bought >= 0 then
+	redis.call('HDEL', KEYS[2], ARGV[1])
+end
+return 0
+`)
+
+// FlashSaleCounter implements flashsale.Counter with atomic Redis scripts
+type FlashSaleCounter struct {
+	client *Client
+	prefix string
+}
+
+// NewFlashSaleCounter creates a new flash sale counter
+func NewFlashSaleCounter(client *Client) *FlashSaleCounter {
+	return &FlashSaleCounter{
+		client: client,
+		prefix: "flashsale:",
+	}
+}
+
+// Reserve takes quantity units of the sale for a user. A counter lost from
+// Redis is rebuilt from the sale's sold count.
+func (c *FlashSaleCounter) Reserve(ctx context.Context, sale *flashsale.Sale, userID uuid.UUID, quantity int) error {
+	limit := 0
+	if sale.PerUserLimit != nil {
+		limit = *sale.PerUserLimit
+	}
+
+	result, err := reserveScript.Run(ctx, c.client.GetClient(),
+		[]string{c.soldKey(sale.ID), c.buyersKey(sale.ID)},
+		userID.String(), quantity, sale.Quantity, limit, sale.SoldCount, sale.EndsAt.Add(flashSaleKeyTTL).Unix(),
+	).Int64()
+	if err != nil {
+		return err
+	}
+
+	switch result {
+	case -1:
+		return flashsale.ErrSoldOut
+	case -2:
+		return flashsale.ErrUserLimitReached
+	case -3:
+		return flashsale.ErrBuyerCountsLost
+	}
+	return nil
+}
+
+// RestoreBuyers rebuilds a sale's per-buyer counts lost from Redis
+func (c *FlashSaleCounter) RestoreBuyers(ctx context.Context, sale *flashsale.Sale, claimed map[uuid.UUID]int) error {
+	args := make([]interface{}, 0, 1+2*len(claimed))
+	args = append(args, sale.EndsAt.Add(flashSaleKeyTTL).Unix())
+	for userID, quantity := range claimed {
+		args = append(args, userID.String(), quantity)
+	}
+	return restoreBuyersScript.Run(ctx, c.client.GetClient(), []string{c.buyersKey(sale.ID)}, args...).Err()
+}
+
+// Release gives back units taken by Reserve
+func (c *FlashSaleCounter) Release(ctx context.Context, saleID, userID uuid.UUID, quantity int) error {
+	return releaseScript.Run(ctx, c.client.GetClient(),
+		[]string{c.soldKey(saleID), c.buyersKey(saleID)},
+		userID.String(), quantity,
+	).Err()
+}
+
+// soldKey generates the key counting a sale's units sold
+func (c *FlashSaleCounter) soldKey(saleID uuid.UUID) string {
+	return fmt.Sprintf("%s%s:sold", c.prefix, saleID.String())
+}
+
+// buyersKey generates the key counting each buyer's units of a sale
+func (c *FlashSaleCounter) buyersKey(saleID uuid.UUID) string {
+	return fmt.Sprintf("%s%s:buyers", c.prefix, saleID.String())
+}
@@ -0,0 +1,303 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// storedSession is a session as kept in Redis. Tokens are stored hashed so
+// a leaked dump cannot be replayed.
+type storedSession struct {
+	ID               uuid.UUID `json:"id"`
+	UserID           uuid.UUID `json:"user_id"`
+	TokenHash        string    `json:"token_hash"`
+	RefreshTokenHash string    `json:"refresh_token_hash"`
+	UserAgent        string    `json:"user_agent,omitempty"`
+	IPAddress        string    `json:"ip_address,omitempty"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// SessionStore implements user.SessionRepository. Each session key expires
+// with the session; token keys point at the session and expire with it, and
+// a per-user set indexes the user's sessions.
+type SessionStore struct {
+	client *Client
+	prefix string
+}
+
+// NewSessionStore creates a new session store
+func NewSessionStore(client *Client) *SessionStore {
+	return &SessionStore{
+		client: client,
+		prefix: "session:",
+	}
+}
+
+// Create saves a new session
+func (s *SessionStore) Create(ctx context.Context, session *user.Session) error {
+	stored := &storedSession{
+		ID:               session.ID,
+		UserID:           session.UserID,
+		TokenHash:        hashToken(session.Token),
+		RefreshTokenHash: hashToken(session.RefreshToken),
+		UserAgent:        session.UserAgent,
+		IPAddress:        session.IPAddress,
+		ExpiresAt:        session.ExpiresAt,
+		CreatedAt:        session.CreatedAt,
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		return user.ErrSessionExpired
+	}
+
+	_, err = s.client.GetClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.key(session.ID), data, ttl)
+		pipe.Set(ctx, s.tokenKey(stored.TokenHash), session.ID.String(), ttl)
+		pipe.Set(ctx, s.tokenKey(stored.RefreshTokenHash), session.ID.String(), ttl)
+		pipe.SAdd(ctx, s.userKey(session.UserID), session.ID.String())
+		// Sessions all live equally long, so the newest one expires last
+		pipe.ExpireAt(ctx, s.userKey(session.UserID), session.ExpiresAt)
+		return nil
+	})
+	return err
+}
+
+// Rotate saves a session's new tokens, keeping its expiry, provided
+// usedRefreshToken is its current refresh token. The session key is watched
+// so two refreshes racing with the same token cannot both succeed; the loser
+// is treated as a reuse.
+func (s *SessionStore) Rotate(ctx context.Context, session *user.Session, usedRefreshToken string) error {
+	key := s.key(session.ID)
+	err := s.client.GetClient().Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := s.getWith(ctx, tx, session.ID)
+		if err != nil {
+			return err
+		}
+		if stored.RefreshTokenHash != hashToken(usedRefreshToken) {
+			return user.ErrRefreshTokenReused
+		}
+
+		oldTokenHash, oldRefreshTokenHash := stored.TokenHash, stored.RefreshTokenHash
+		stored.TokenHash = hashToken(session.Token)
+		stored.RefreshTokenHash = hashToken(session.RefreshToken)
+		data, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+
+		ttl := time.Until(stored.ExpiresAt)
+		if ttl <= 0 {
+			return user.ErrSessionNotFound
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, s.tokenKey(oldTokenHash), s.tokenKey(oldRefreshTokenHash))
+			pipe.Set(ctx, key, data, ttl)
+			pipe.Set(ctx, s.tokenKey(stored.TokenHash), session.ID.String(), ttl)
+			pipe.Set(ctx, s.tokenKey(stored.RefreshTokenHash), session.ID.String(), ttl)
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return user.ErrRefreshTokenReused
+	}
+	return err
+}
+
+// GetByID retrieves a live session
+func (s *SessionStore) GetByID(ctx context.Context, id uuid.UUID) (*user.Session, error) {
+	stored, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return stored.toDomain(), nil
+}
+
+// GetByToken retrieves the live session an access or refresh token was issued for
+func (s *SessionStore) GetByToken(ctx context.Context, token string) (*user.Session, error) {
+	idStr, err := s.client.GetClient().Get(ctx, s.tokenKey(hashToken(token))).Result()
+	if err == redis.Nil {
+		return nil, user.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, user.ErrSessionNotFound
+	}
+	return s.GetByID(ctx, id)
+}
+
+// ListByUserID retrieves a user's live sessions, newest first. Expired
+// sessions are pruned from the user's index as they are found.
+func (s *SessionStore) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*user.Session, error) {
+	stored, stale, err := s.listStored(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(stale) > 0 {
+		s.client.GetClient().SRem(ctx, s.userKey(userID), stale...)
+	}
+
+	sessions := make([]*user.Session, len(stored))
+	for i, st := range stored {
+		sessions[i] = st.toDomain()
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// Delete revokes a session
+func (s *SessionStore) Delete(ctx context.Context, id uuid.UUID) error {
+	stored, err := s.get(ctx, id)
+	if errors.Is(err, user.ErrSessionNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.GetClient().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.key(id), s.tokenKey(stored.TokenHash), s.tokenKey(stored.RefreshTokenHash))
+		pipe.SRem(ctx, s.userKey(stored.UserID), id.String())
+		return nil
+	})
+	return err
+}
+
+// DeleteByUserID revokes all of a user's sessions
+func (s *SessionStore) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	stored, _, err := s.listStored(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, 3*len(stored)+1)
+	for _, st := range stored {
+		keys = append(keys, s.key(st.ID), s.tokenKey(st.TokenHash), s.tokenKey(st.RefreshTokenHash))
+	}
+	keys = append(keys, s.userKey(userID))
+	return s.client.GetClient().Del(ctx, keys...).Err()
+}
+
+// listStored loads the sessions in a user's index, also returning the IDs
+// of indexed sessions that have expired
+func (s *SessionStore) listStored(ctx context.Context, userID uuid.UUID) ([]*storedSession, []interface{}, error) {
+	ids, err := s.client.GetClient().SMembers(ctx, s.userKey(userID)).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	valid := make([]string, 0, len(ids))
+	var stale []interface{}
+	for _, idStr := range ids {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			stale = append(stale, idStr)
+			continue
+		}
+		keys = append(keys, s.key(id))
+		valid = append(valid, idStr)
+	}
+	if len(keys) == 0 {
+		return nil, stale, nil
+	}
+
+	values, err := s.client.GetClient().MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := make([]*storedSession, 0, len(values))
+	for i, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			stale = append(stale, valid[i])
+			continue
+		}
+		var st storedSession
+		if err := json.Unmarshal([]byte(data), &st); err != nil {
+			stale = append(stale, valid[i])
+			continue
+		}
+		stored = append(stored, &st)
+	}
+	return stored, stale, nil
+}
+
+func (s *SessionStore) get(ctx context.Context, id uuid.UUID) (*storedSession, error) {
+	return s.getWith(ctx, s.client.GetClient(), id)
+}
+
+func (s *SessionStore) getWith(ctx context.Context, cmd redis.Cmdable, id uuid.UUID) (*storedSession, error) {
+	data, err := cmd.Get(ctx, s.key(id)).Bytes()
+	if err == redis.Nil {
+		return nil, user.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var stored storedSession
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (st *storedSession) toDomain() *user.Session {
+	return &user.Session{
+		ID:        st.ID,
+		UserID:    st.UserID,
+		UserAgent: st.UserAgent,
+		IPAddress: st.IPAddress,
+		ExpiresAt: st.ExpiresAt,
+		CreatedAt: st.CreatedAt,
+	}
+}
+
+// hashToken hashes a token for use as a lookup key
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// key generates the key holding a session
+func (s *SessionStore) key(id uuid.UUID) string {
+	return fmt.Sprintf("%s%s", s.prefix, id.String())
+}
+
+// tokenKey generates the key pointing a token hash at its session
+func (s *SessionStore) tokenKey(tokenHash string) string {
+	return fmt.Sprintf("%stoken:%s", s.prefix, tokenHash)
+}
+
+// userKey generates the key indexing a user's sessions
+func (s *SessionStore) userKey(userID uuid.UUID) string {
+	return fmt.Sprintf("%suser:%s", s.prefix, userID.String())
+}
@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+func newTestSessionStore(t *testing.T) *SessionStore {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := NewClient(Config{Host: mr.Host(), Port: mr.Port()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewSessionStore(client)
+}
+
+func newTestSession(t *testing.T, store *SessionStore) *user.Session {
+	t.Helper()
+	now := time.Now()
+	session := &user.Session{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		Token:        "access-0",
+		RefreshToken: "refresh-0",
+		ExpiresAt:    now.Add(time.Hour),
+		CreatedAt:    now,
+	}
+	if err := store.Create(context.Background(), session); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return session
+}
+
+// rotated returns a copy of session carrying new tokens
+func rotated(session *user.Session, n int) *user.Session {
+	next := *session
+	next.Token = fmt.Sprintf("access-%d", n)
+	next.RefreshToken = fmt.Sprintf("refresh-%d", n)
+	return &next
+}
+
+func TestSessionStoreRotate(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSessionStore(t)
+	session := newTestSession(t, store)
+
+	next := rotated(session, 1)
+	if err := store.Rotate(ctx, next, session.RefreshToken); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	for _, token := range []string{next.Token, next.RefreshToken} {
+		got, err := store.GetByToken(ctx, token)
+		if err != nil {
+			t.Fatalf("GetByToken(%q): %v", token, err)
+		}
+		if got.ID != session.ID {
+			t.Errorf("GetByToken(%q) = session %s, want %s", token, got.ID, session.ID)
+		}
+	}
+	for _, token := range []string{session.Token, session.RefreshToken} {
+		if _, err := store.GetByToken(ctx, token); !errors.Is(err, user.ErrSessionNotFound) {
+			t.Errorf("GetByToken(%q) after rotation: err = %v, want %v", token, err, user.ErrSessionNotFound)
+		}
+	}
+}
+
+func TestSessionStoreRotateDetectsReuse(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSessionStore(t)
+	session := newTestSession(t, store)
+
+	if err := store.Rotate(ctx, rotated(session, 1), session.RefreshToken); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	// Replaying the refresh token that was just rotated away is a reuse
+	err := store.Rotate(ctx, rotated(session, 2), session.RefreshToken)
+	if !errors.Is(err, user.ErrRefreshTokenReused) {
+		t.Fatalf("Rotate with used token: err = %v, want %v", err, user.ErrRefreshTokenReused)
+	}
+
+	// The failed rotation must not have replaced the current tokens
+	if _, err := store.GetByToken(ctx, "refresh-1"); err != nil {
+		t.Errorf("GetByToken(current refresh token): %v", err)
+	}
+	if _, err := store.GetByToken(ctx, "refresh-2"); !errors.Is(err, user.ErrSessionNotFound) {
+		t.Errorf("GetByToken(rejected refresh token): err = %v, want %v", err, user.ErrSessionNotFound)
+	}
+}
+
+func TestSessionStoreRotateRace(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSessionStore(t)
+	session := newTestSession(t, store)
+
+	const racers = 8
+	errs := make([]error, racers)
+	var wg sync.WaitGroup
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = store.Rotate(ctx, rotated(session, i+1), session.RefreshToken)
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, user.ErrRefreshTokenReused):
+			t.Errorf("racer %d: err = %v, want nil or %v", i, err, user.ErrRefreshTokenReused)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d rotations with the same refresh token succeeded, want 1", succeeded)
+	}
+}
+
+func TestSessionStoreRotateMissingSession(t *testing.T) {
+	store := newTestSessionStore(t)
+	session := &user.Session{ID: uuid.New(), UserID: uuid.New(), Token: "access-1", RefreshToken: "refresh-1"}
+
+	err := store.Rotate(context.Background(), session, "refresh-0")
+	if !errors.Is(err, user.ErrSessionNotFound) {
+		t.Errorf("Rotate: err = %v, want %v", err, user.ErrSessionNotFound)
+	}
+}
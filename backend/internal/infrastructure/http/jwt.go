@@ -2,6 +2,8 @@ package http
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/blytz/live/backend/internal/domain/user"
@@ -9,59 +11,137 @@ import (
 	"github.com/google/uuid"
 )
 
-// JWTTokenManager implements user.TokenManager
+// JWTTokenManager implements user.TokenManager. Tokens are signed with one
+// key and verified with whichever key their kid header names, so a new key
+// can be introduced for verification before it starts signing.
 type JWTTokenManager struct {
-	secret        []byte
+	keys          map[string]*JWTKey
+	signingKey    *JWTKey
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
 }
 
-// JWTClaims represents JWT claims
+// Token types, carried in both the typ claim and the audience so a token of
+// one kind is never accepted as the other
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+
+	accessAudience  = "blytz-live:access"
+	refreshAudience = "blytz-live:refresh"
+)
+
+// JWTClaims represents access token claims
 type JWTClaims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Role   user.Role `json:"role"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"sid"`
+	Email     string    `json:"email"`
+	Role      user.Role `json:"role"`
+	TokenType string    `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// RefreshClaims represents refresh token claims
+type RefreshClaims struct {
+	SessionID uuid.UUID `json:"sid"`
+	TokenType string    `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// ActionTokenClaims represents emailed action token claims. The typ claim
+// is the token's purpose and the audience is derived from it.
+type ActionTokenClaims struct {
+	Email     string `json:"email"`
+	TokenType string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
-// NewJWTTokenManager creates a new JWT token manager
-func NewJWTTokenManager(secret string, accessExpiry, refreshExpiry time.Duration) *JWTTokenManager {
-	return &JWTTokenManager{
-		secret:        []byte(secret),
+// actionAudience is the audience of action tokens issued for purpose
+func actionAudience(purpose user.TokenPurpose) string {
+	return "blytz-live:" + string(purpose)
+}
+
+// NewJWTTokenManager creates a new JWT token manager that signs with the key
+// identified by signingKeyID and verifies with any of keys
+func NewJWTTokenManager(keys []*JWTKey, signingKeyID string, accessExpiry, refreshExpiry time.Duration) (*JWTTokenManager, error) {
+	m := &JWTTokenManager{
+		keys:          make(map[string]*JWTKey, len(keys)),
 		accessExpiry:  accessExpiry,
 		refreshExpiry: refreshExpiry,
 	}
+	for _, k := range keys {
+		if _, ok := m.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		}
+		m.keys[k.ID] = k
+	}
+
+	signing, ok := m.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found", signingKeyID)
+	}
+	if !signing.CanSign() {
+		return nil, fmt.Errorf("signing key %q is a public key", signingKeyID)
+	}
+	m.signingKey = signing
+	return m, nil
+}
+
+// JWKS returns the public keys tokens are verified with. HMAC keys are
+// left out.
+func (m *JWTTokenManager) JWKS() *JWKSet {
+	set := &JWKSet{Keys: []JWK{}}
+	for _, k := range m.keys {
+		if jwk, ok := k.toJWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return set
 }
 
-// Generate generates access and refresh tokens
-func (m *JWTTokenManager) Generate(userID uuid.UUID, email string, role user.Role) (accessToken, refreshToken string, err error) {
+// Generate generates access and refresh tokens. Every token gets a unique
+// ID, so a rotated refresh token never repeats an earlier one.
+func (m *JWTTokenManager) Generate(sessionID, userID uuid.UUID, email string, role user.Role) (accessToken, refreshToken string, err error) {
+	now := time.Now()
+
 	// Access token
 	accessClaims := JWTClaims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		SessionID: sessionID,
+		Email:     email,
+		Role:      role,
+		TokenType: tokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			Audience:  jwt.ClaimStrings{accessAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Subject:   userID.String(),
 		},
 	}
-	
-	access := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessToken, err = access.SignedString(m.secret)
+
+	accessToken, err = m.sign(accessClaims)
 	if err != nil {
 		return "", "", err
 	}
 
 	// Refresh token
-	refreshClaims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshExpiry)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		Subject:   userID.String(),
-	}
-	
-	refresh := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshToken, err = refresh.SignedString(m.secret)
+	refreshClaims := RefreshClaims{
+		SessionID: sessionID,
+		TokenType: tokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Audience:  jwt.ClaimStrings{refreshAudience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.refreshExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   userID.String(),
+		},
+	}
+
+	refreshToken, err = m.sign(refreshClaims)
 	if err != nil {
 		return "", "", err
 	}
@@ -69,25 +149,126 @@ func (m *JWTTokenManager) Generate(userID uuid.UUID, email string, role user.Rol
 	return accessToken, refreshToken, nil
 }
 
-// Validate validates a token
+// Validate validates an access token
 func (m *JWTTokenManager) Validate(tokenString string) (*user.TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return m.secret, nil
-	})
+	claims := &JWTClaims{}
+	if err := m.parse(tokenString, claims, accessAudience); err != nil {
+		return nil, err
+	}
+	if claims.TokenType != tokenTypeAccess {
+		return nil, errors.New("not an access token")
+	}
+
+	return &user.TokenClaims{
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+		Email:     claims.Email,
+		Role:      claims.Role,
+	}, nil
+}
+
+// ValidateRefresh validates a refresh token. Only the user and session are
+// set on the returned claims.
+func (m *JWTTokenManager) ValidateRefresh(tokenString string) (*user.TokenClaims, error) {
+	claims := &RefreshClaims{}
+	if err := m.parse(tokenString, claims, refreshAudience); err != nil {
+		return nil, err
+	}
+	if claims.TokenType != tokenTypeRefresh {
+		return nil, errors.New("not a refresh token")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
 	if err != nil {
+		return nil, errors.New("invalid token subject")
+	}
+	return &user.TokenClaims{
+		UserID:    userID,
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+// GenerateActionToken generates a token for an emailed action. The claims'
+// ID becomes the token ID, which the caller records to make it single use.
+func (m *JWTTokenManager) GenerateActionToken(claims user.ActionClaims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	return m.sign(ActionTokenClaims{
+		Email:     claims.Email,
+		TokenType: string(claims.Purpose),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        claims.ID.String(),
+			Audience:  jwt.ClaimStrings{actionAudience(claims.Purpose)},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   claims.UserID.String(),
+		},
+	})
+}
+
+// ValidateActionToken validates a token issued for purpose. Whether it has
+// already been used is up to the caller.
+func (m *JWTTokenManager) ValidateActionToken(tokenString string, purpose user.TokenPurpose) (*user.ActionClaims, error) {
+	claims := &ActionTokenClaims{}
+	if err := m.parse(tokenString, claims, actionAudience(purpose)); err != nil {
 		return nil, err
 	}
+	if claims.TokenType != string(purpose) {
+		return nil, errors.New("token issued for another purpose")
+	}
 
-	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-		return &user.TokenClaims{
-			UserID: claims.UserID,
-			Email:  claims.Email,
-			Role:   claims.Role,
-		}, nil
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, errors.New("invalid token id")
 	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, errors.New("invalid token subject")
+	}
+	return &user.ActionClaims{
+		ID:      id,
+		UserID:  userID,
+		Purpose: purpose,
+		Email:   claims.Email,
+	}, nil
+}
+
+// sign signs claims with the signing key, naming it in the kid header
+func (m *JWTTokenManager) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(m.signingKey.Method, claims)
+	token.Header["kid"] = m.signingKey.ID
+	return token.SignedString(m.signingKey.sign)
+}
+
+// parse verifies a token's signature, expiry and audience into claims. The
+// algorithm must match the one of the key named by kid, so a token cannot
+// pick how it is checked.
+func (m *JWTTokenManager) parse(tokenString string, claims jwt.Claims, audience string) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verify, nil
+	}, jwt.WithAudience(audience), jwt.WithExpirationRequired())
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("invalid token claims")
+	}
+	return nil
+}
+
+// AccessTokenTTL returns how long access tokens stay valid
+func (m *JWTTokenManager) AccessTokenTTL() time.Duration {
+	return m.accessExpiry
+}
 
-	return nil, errors.New("invalid token claims")
-}
\ No newline at end of file
+// RefreshTokenTTL returns how long refresh tokens stay valid
+func (m *JWTTokenManager) RefreshTokenTTL() time.Duration {
+	return m.refreshExpiry
+}
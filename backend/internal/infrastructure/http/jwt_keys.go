@@ -0,0 +1,181 @@
+package http
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits is the smallest RSA key accepted for signing tokens
+const minRSAKeyBits = 2048
+
+// JWTKey is a key tokens are signed or verified with, identified in token
+// headers by its kid. Keys loaded from a public key file only verify, which
+// lets a retired key keep honouring tokens it signed until they expire.
+type JWTKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   interface{} // nil for verify-only keys
+	verify interface{}
+}
+
+// NewHMACKey creates an HS256 key from a shared secret. HMAC keys are never
+// published in the JWKS.
+func NewHMACKey(id, secret string) *JWTKey {
+	return &JWTKey{
+		ID:     id,
+		Method: jwt.SigningMethodHS256,
+		sign:   []byte(secret),
+		verify: []byte(secret),
+	}
+}
+
+// CanSign reports whether the key holds private material
+func (k *JWTKey) CanSign() bool {
+	return k.sign != nil
+}
+
+// LoadJWTKeys loads every *.pem file in dir as a key whose kid is the file
+// name without its extension. Private keys (PKCS#1 or PKCS#8) sign and
+// verify; public keys (PKIX) only verify. RSA keys use RS256 and Ed25519
+// keys use EdDSA.
+func LoadJWTKeys(dir string) ([]*JWTKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	keys := make([]*JWTKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := parseJWTKey(id, data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parseJWTKey(id string, data []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newKeyFromPrivate(id, priv)
+	case "PRIVATE KEY":
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newKeyFromPrivate(id, priv)
+	case "PUBLIC KEY":
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newKeyFromPublic(id, pub)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func newKeyFromPrivate(id string, priv interface{}) (*JWTKey, error) {
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		key, err := newKeyFromPublic(id, &k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		key.sign = k
+		return key, nil
+	case ed25519.PrivateKey:
+		return &JWTKey{ID: id, Method: jwt.SigningMethodEdDSA, sign: k, verify: k.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", priv)
+	}
+}
+
+func newKeyFromPublic(id string, pub interface{}) (*JWTKey, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key is %d bits, at least %d required", k.N.BitLen(), minRSAKeyBits)
+		}
+		return &JWTKey{ID: id, Method: jwt.SigningMethodRS256, verify: k}, nil
+	case ed25519.PublicKey:
+		return &JWTKey{ID: id, Method: jwt.SigningMethodEdDSA, verify: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// toJWK returns the key's public half, or false for HMAC keys, which must
+// stay secret
+func (k *JWTKey) toJWK() (JWK, bool) {
+	enc := base64.RawURLEncoding
+	switch pub := k.verify.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			N:   enc.EncodeToString(pub.N.Bytes()),
+			E:   enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			Crv: "Ed25519",
+			X:   enc.EncodeToString(pub),
+		}, true
+	}
+	return JWK{}, false
+}
@@ -18,6 +18,7 @@ type Server struct {
 	router  *gin.Engine
 	server  *http.Server
 	handlers *Handlers
+	jwks     *JWKSet
 }
 
 // Handlers holds all HTTP handlers
@@ -28,10 +29,22 @@ type Handlers struct {
 	Category  *handlers.CategoryHandler
 	AuctionWS *handlers.AuctionWSHandler
 	Upload    *handlers.UploadHandler
+	Order     *handlers.OrderHandler
+	Cart      *handlers.CartHandler
+	Promotion *handlers.PromotionHandler
+	FlashSale *handlers.FlashSaleHandler
+	Invoice   *handlers.InvoiceHandler
+	Dispute   *handlers.DisputeHandler
+	Deposit   *handlers.DepositHandler
+	Payment   *handlers.PaymentHandler
+	Ledger    *handlers.LedgerHandler
+	Shipping  *handlers.ShippingHandler
 }
 
-// NewServer creates a new HTTP server
-func NewServer(port string, h *Handlers, tokenManager user.TokenManager, redisClient *redis.Client) *Server {
+// NewServer creates a new HTTP server. Bidding and selling require a verified
+// email unless emailVerifier is nil. Users whose role requires two-factor
+// authentication can only reach their account settings until they enable it.
+func NewServer(port string, h *Handlers, tokenManager userDomain.TokenManager, sessions userDomain.SessionRepository, emailVerifier middleware.EmailVerifier, twoFactor middleware.TwoFactorChecker, jwks *JWKSet, redisClient *redis.Client) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	
 	router := gin.New()
@@ -42,9 +55,10 @@ func NewServer(port string, h *Handlers, tokenManager user.TokenManager, redisCl
 	s := &Server{
 		router:   router,
 		handlers: h,
+		jwks:     jwks,
 	}
 
-	s.setupRoutes(tokenManager, redisClient)
+	s.setupRoutes(tokenManager, sessions, emailVerifier, twoFactor, redisClient)
 
 	s.server = &http.Server{
 		Addr:    ":" + port,
@@ -72,12 +86,18 @@ func (s *Server) Shutdown(ctx context.Context) error {
 }
 
 // setupRoutes configures all routes
-func (s *Server) setupRoutes(tokenManager user.TokenManager, redisClient *redis.Client) {
+func (s *Server) setupRoutes(tokenManager userDomain.TokenManager, sessions userDomain.SessionRepository, emailVerifier middleware.EmailVerifier, twoFactor middleware.TwoFactorChecker, redisClient *redis.Client) {
 	// Health check
 	s.router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	// Public keys for services verifying our tokens
+	s.router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, s.jwks)
+	})
+
 	// API v1
 	v1 := s.router.Group("/api/v1")
 	
@@ -87,7 +107,11 @@ func (s *Server) setupRoutes(tokenManager user.TokenManager, redisClient *redis.
 	{
 		auth.POST("/register", s.handlers.Auth.Register)
 		auth.POST("/login", s.handlers.Auth.Login)
+		auth.POST("/login/2fa", s.handlers.Auth.VerifyTwoFactorLogin)
 		auth.POST("/refresh", s.handlers.Auth.Refresh)
+		auth.POST("/verify-email/confirm", s.handlers.Auth.ConfirmEmail)
+		auth.POST("/password-reset/request", s.handlers.Auth.RequestPasswordReset)
+		auth.POST("/password-reset/confirm", s.handlers.Auth.ResetPassword)
 	}
 
 	// Public auction routes
@@ -97,16 +121,21 @@ func (s *Server) setupRoutes(tokenManager user.TokenManager, redisClient *redis.
 		auctions.GET("", s.handlers.Auction.ListLiveAuctions)
 		auctions.GET("/live", s.handlers.Auction.ListLiveAuctions)
 		auctions.GET("/:id", s.handlers.Auction.GetAuction)
+
+		// Server-Sent Events fallback for networks that block WebSockets
+		auctions.GET("/:id/events", middleware.OptionalAuth(tokenManager, sessions), s.handlers.AuctionWS.StreamEvents)
+		auctions.POST("/:id/reactions", middleware.OptionalAuth(tokenManager, sessions), middleware.ReactionRateLimit(redisClient), s.handlers.AuctionWS.SendReaction)
 	}
 
 	// WebSocket endpoint for auctions (public, but auth recommended)
-	s.router.GET("/ws/auctions/:id", func(c *gin.Context) {
+	s.router.GET("/ws/auctions/:id", middleware.OptionalAuth(tokenManager, sessions), func(c *gin.Context) {
 		s.handlers.AuctionWS.HandleWebSocket(c)
 	})
 
 	// Upload endpoints (protected)
 	uploads := v1.Group("/uploads")
-	uploads.Use(middleware.AuthMiddleware(tokenManager))
+	uploads.Use(middleware.AuthMiddleware(tokenManager, sessions))
+	uploads.Use(middleware.RequireTwoFactor(twoFactor))
 	uploads.Use(middleware.GeneralRateLimit(redisClient))
 	{
 		uploads.POST("/product-image", s.handlers.Upload.UploadProductImage)
@@ -135,48 +164,174 @@ func (s *Server) setupRoutes(tokenManager user.TokenManager, redisClient *redis.
 		categories.GET("/:id", s.handlers.Category.Get)
 	}
 
+	// Shipping carriers (public)
+	v1.GET("/shipping/carriers", s.handlers.Shipping.ListCarriers)
+
+	// Flash sales feed (public)
+	v1.GET("/flash-sales/active", s.handlers.FlashSale.ActiveFeed)
+
+	// Payment provider webhooks (authenticated by signature)
+	v1.POST("/webhooks/payments", s.handlers.Payment.Webhook)
+
+	// Cart routes (guests identified by cart token, users by auth)
+	cart := v1.Group("/cart")
+	cart.Use(middleware.OptionalAuth(tokenManager, sessions))
+	cart.Use(middleware.GeneralRateLimit(redisClient))
+	{
+		cart.GET("", s.handlers.Cart.GetCart)
+		cart.DELETE("", s.handlers.Cart.ClearCart)
+		cart.POST("/items", s.handlers.Cart.AddItem)
+		cart.PUT("/items/:productId", s.handlers.Cart.UpdateItem)
+		cart.DELETE("/items/:productId", s.handlers.Cart.RemoveItem)
+		cart.POST("/coupon", s.handlers.Cart.ApplyCoupon)
+		cart.DELETE("/coupon", s.handlers.Cart.RemoveCoupon)
+		cart.POST("/merge", middleware.AuthMiddleware(tokenManager, sessions), s.handlers.Cart.MergeCart)
+		cart.POST("/checkout", middleware.AuthMiddleware(tokenManager, sessions), s.handlers.Cart.Checkout)
+	}
+
+	// Account routes, open to signed-in users who still have to enrol in
+	// two-factor authentication
+	account := v1.Group("/auth")
+	account.Use(middleware.AuthMiddleware(tokenManager, sessions))
+	account.Use(middleware.GeneralRateLimit(redisClient))
+	{
+		account.GET("/profile", s.handlers.Auth.GetProfile)
+		account.POST("/change-password", s.handlers.Auth.ChangePassword)
+		account.POST("/logout", s.handlers.Auth.Logout)
+		account.POST("/logout-all", s.handlers.Auth.LogoutAll)
+		account.GET("/sessions", s.handlers.Auth.ListSessions)
+		account.DELETE("/sessions/:id", s.handlers.Auth.RevokeSession)
+		account.POST("/verify-email/request", s.handlers.Auth.RequestEmailVerification)
+
+		// Two-factor authentication
+		account.GET("/2fa", s.handlers.Auth.GetTwoFactorStatus)
+		account.POST("/2fa/setup", s.handlers.Auth.SetupTwoFactor)
+		account.POST("/2fa/confirm", s.handlers.Auth.ConfirmTwoFactor)
+		account.POST("/2fa/disable", s.handlers.Auth.DisableTwoFactor)
+		account.POST("/2fa/recovery-codes", s.handlers.Auth.RegenerateRecoveryCodes)
+	}
+
 	// Protected routes
+	verified := middleware.RequireVerifiedEmail(emailVerifier)
 	protected := v1.Group("")
-	protected.Use(middleware.AuthMiddleware(tokenManager))
+	protected.Use(middleware.AuthMiddleware(tokenManager, sessions))
+	protected.Use(middleware.RequireTwoFactor(twoFactor))
 	protected.Use(middleware.GeneralRateLimit(redisClient))
 	{
-		// Auth
-		protected.GET("/auth/profile", s.handlers.Auth.GetProfile)
-		protected.POST("/auth/change-password", s.handlers.Auth.ChangePassword)
-		protected.POST("/auth/logout", s.handlers.Auth.Logout)
-
 		// Auctions (protected)
-		protected.POST("/auctions", s.handlers.Auction.CreateAuction)
-		protected.POST("/auctions/:id/bid", middleware.AuctionBidRateLimit(redisClient), s.handlers.Auction.PlaceBid)
+		protected.POST("/auctions", middleware.RequireRole(userDomain.RoleSeller), verified, s.handlers.Auction.CreateAuction)
+		protected.POST("/auctions/:id/bid", verified, middleware.AuctionBidRateLimit(redisClient), s.handlers.Auction.PlaceBid)
 		protected.POST("/auctions/:id/start", s.handlers.Auction.StartAuction)
 		protected.POST("/auctions/:id/end", s.handlers.Auction.EndAuction)
+		protected.POST("/auctions/:id/relist", verified, s.handlers.Auction.RelistAuction)
+		protected.POST("/auctions/:id/deposit", verified, s.handlers.Deposit.PlaceDeposit)
+		protected.GET("/auctions/:id/deposit", s.handlers.Deposit.GetDeposit)
 
 		// Products (protected - seller only)
-		protected.POST("/products", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Product.Create)
+		protected.POST("/products", middleware.RequireRole(userDomain.RoleSeller), verified, s.handlers.Product.Create)
 		protected.PUT("/products/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Product.Update)
 		protected.DELETE("/products/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Product.Delete)
-		protected.POST("/products/:id/publish", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Product.Publish)
+		protected.POST("/products/:id/publish", middleware.RequireRole(userDomain.RoleSeller), verified, s.handlers.Product.Publish)
 		protected.POST("/products/:id/archive", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Product.Archive)
 		protected.GET("/my-products", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Product.GetMyProducts)
+
+		// Orders
+		protected.GET("/orders", s.handlers.Order.ListOrders)
+		protected.GET("/orders/:id", s.handlers.Order.GetOrder)
+		protected.POST("/orders/:id/cancel", s.handlers.Order.CancelOrder)
+		protected.POST("/orders/:id/deliver", s.handlers.Order.ConfirmDelivery)
+		protected.PUT("/orders/:id/shipping-address", s.handlers.Order.SetShippingAddress)
+		protected.POST("/orders/:id/ship", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Order.ShipOrder)
+		protected.GET("/seller/orders", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Order.ListSellerOrders)
+
+		// Payments
+		protected.POST("/orders/:id/pay", s.handlers.Payment.PayOrder)
+		protected.GET("/orders/:id/payments", s.handlers.Payment.ListPayments)
+		protected.POST("/orders/:id/refunds", middleware.RequireRole(userDomain.RoleSeller, userDomain.RoleAdmin), s.handlers.Payment.RefundOrder)
+		protected.GET("/orders/:id/refunds", s.handlers.Payment.ListRefunds)
+
+		// Invoices
+		protected.GET("/orders/:id/invoice", s.handlers.Invoice.GetInvoice)
+		protected.GET("/seller/invoices/export", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Invoice.ExportInvoices)
+
+		// Disputes
+		protected.POST("/orders/:id/disputes", s.handlers.Dispute.OpenDispute)
+		protected.GET("/orders/:id/disputes", s.handlers.Dispute.ListOrderDisputes)
+		protected.GET("/disputes", s.handlers.Dispute.ListDisputes)
+		protected.GET("/disputes/:id", s.handlers.Dispute.GetDispute)
+		protected.POST("/disputes/:id/respond", s.handlers.Dispute.RespondDispute)
+		protected.POST("/disputes/:id/escalate", s.handlers.Dispute.EscalateDispute)
+		protected.POST("/disputes/:id/accept", s.handlers.Dispute.AcceptDisputeResponse)
+		protected.POST("/disputes/:id/resolve", s.handlers.Dispute.ResolveDispute)
+		protected.POST("/disputes/:id/evidence", s.handlers.Dispute.AddEvidence)
+
+		// Saved payment methods
+		protected.GET("/payment-methods", s.handlers.Payment.ListPaymentMethods)
+		protected.POST("/payment-methods", s.handlers.Payment.AddPaymentMethod)
+		protected.POST("/payment-methods/:id/default", s.handlers.Payment.SetDefaultPaymentMethod)
+		protected.DELETE("/payment-methods/:id", s.handlers.Payment.RemovePaymentMethod)
+
+		// Address book and shipping
+		protected.GET("/addresses", s.handlers.Shipping.ListAddresses)
+		protected.POST("/addresses", s.handlers.Shipping.AddAddress)
+		protected.PUT("/addresses/:id", s.handlers.Shipping.UpdateAddress)
+		protected.POST("/addresses/:id/default", s.handlers.Shipping.SetDefaultAddress)
+		protected.DELETE("/addresses/:id", s.handlers.Shipping.DeleteAddress)
+		protected.POST("/shipping/quote", s.handlers.Shipping.Quote)
+		protected.GET("/seller/shipping-profiles", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Shipping.ListProfiles)
+		protected.POST("/seller/shipping-profiles", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Shipping.CreateProfile)
+		protected.PUT("/seller/shipping-profiles/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Shipping.UpdateProfile)
+		protected.DELETE("/seller/shipping-profiles/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Shipping.DeleteProfile)
+
+		// Coupons (seller only)
+		protected.GET("/seller/coupons", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Promotion.ListCoupons)
+		protected.POST("/seller/coupons", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Promotion.CreateCoupon)
+		protected.PUT("/seller/coupons/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Promotion.UpdateCoupon)
+		protected.DELETE("/seller/coupons/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Promotion.DeleteCoupon)
+
+		// Flash sales (seller only)
+		protected.GET("/seller/flash-sales", middleware.RequireRole(userDomain.RoleSeller), s.handlers.FlashSale.ListSellerSales)
+		protected.POST("/seller/flash-sales", middleware.RequireRole(userDomain.RoleSeller), s.handlers.FlashSale.CreateSale)
+		protected.PUT("/seller/flash-sales/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.FlashSale.UpdateSale)
+		protected.DELETE("/seller/flash-sales/:id", middleware.RequireRole(userDomain.RoleSeller), s.handlers.FlashSale.DeleteSale)
+
+		// Seller balance and payouts
+		protected.GET("/seller/balance", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Ledger.GetBalance)
+		protected.GET("/seller/transactions", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Ledger.ListTransactions)
+		protected.GET("/seller/payouts", middleware.RequireRole(userDomain.RoleSeller), s.handlers.Ledger.ListPayouts)
 	}
 
 	// Admin routes
 	admin := v1.Group("/admin")
-	admin.Use(middleware.AuthMiddleware(tokenManager))
+	admin.Use(middleware.AuthMiddleware(tokenManager, sessions))
 	admin.Use(middleware.RequireRole(userDomain.RoleAdmin))
+	admin.Use(middleware.RequireTwoFactor(twoFactor))
 	{
+		// Two-factor authentication policy
+		admin.GET("/two-factor-policy", s.handlers.Auth.GetTwoFactorPolicy)
+		admin.PUT("/two-factor-policy", s.handlers.Auth.SetTwoFactorPolicy)
+
 		// Categories (admin only)
 		admin.POST("/categories", s.handlers.Category.Create)
 		admin.PUT("/categories/:id", s.handlers.Category.Update)
 		admin.DELETE("/categories/:id", s.handlers.Category.Delete)
-	}
 
-	// Admin routes
-	admin := v1.Group("/admin")
-	admin.Use(middleware.AuthMiddleware(tokenManager))
-	admin.Use(middleware.RequireRole(userDomain.RoleAdmin))
-	{
-		// Admin endpoints here
+		// WebSocket hub metrics
+		admin.GET("/ws/metrics", s.handlers.AuctionWS.Metrics)
+
+		// Marketplace fee rules
+		admin.GET("/fee-rules", s.handlers.Ledger.ListFeeRules)
+		admin.POST("/fee-rules", s.handlers.Ledger.CreateFeeRule)
+		admin.DELETE("/fee-rules/:id", s.handlers.Ledger.DeleteFeeRule)
+
+		// Platform coupons
+		admin.GET("/coupons", s.handlers.Promotion.ListCoupons)
+		admin.POST("/coupons", s.handlers.Promotion.CreateCoupon)
+		admin.PUT("/coupons/:id", s.handlers.Promotion.UpdateCoupon)
+		admin.DELETE("/coupons/:id", s.handlers.Promotion.DeleteCoupon)
+
+		// Disputes awaiting a decision
+		admin.GET("/disputes", s.handlers.Dispute.ListAllDisputes)
 	}
 }
 
@@ -185,7 +340,8 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Cart-Token, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Cart-Token")
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -0,0 +1,95 @@
+package pdf
+
+import (
+	"bytes"
+	_ "embed"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// A4 page size in points
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+)
+
+// DejaVu Sans covers the scripts buyer and seller names and addresses are
+// written in, which the standard PDF fonts do not
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	regularFont []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	boldFont []byte
+)
+
+const fontFamily = "DejaVuSans"
+
+// document draws A4 pages of text and straight rules, which is all an
+// invoice needs. Coordinates are in points from the bottom-left corner of
+// the page.
+type document struct {
+	pdf *fpdf.Fpdf
+}
+
+func newDocument() *document {
+	pdf := fpdf.New("P", "pt", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetLineWidth(0.5)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", boldFont)
+	d := &document{pdf: pdf}
+	d.addPage()
+	return d
+}
+
+// addPage starts a new page; later drawing goes to it
+func (d *document) addPage() {
+	d.pdf.AddPage()
+}
+
+func (d *document) setFont(size float64, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	d.pdf.SetFont(fontFamily, style, size)
+}
+
+// text draws s with its baseline starting at (x, y)
+func (d *document) text(x, y, size float64, bold bool, s string) {
+	d.setFont(size, bold)
+	d.pdf.Text(x, pageHeight-y, clean(s))
+}
+
+// textRight draws s so that it ends at x
+func (d *document) textRight(x, y, size float64, bold bool, s string) {
+	s = clean(s)
+	d.setFont(size, bold)
+	d.pdf.Text(x-d.pdf.GetStringWidth(s), pageHeight-y, s)
+}
+
+// line draws a thin rule from (x1, y1) to (x2, y2)
+func (d *document) line(x1, y1, x2, y2 float64) {
+	d.pdf.Line(x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+// bytes serializes the document
+func (d *document) bytes() ([]byte, error) {
+	var out bytes.Buffer
+	if err := d.pdf.Output(&out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// clean replaces control characters, which a single line of text cannot
+// show, with spaces
+func clean(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 32 || r == 127 {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,167 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blytz/live/backend/internal/domain/invoice"
+	"github.com/blytz/live/backend/internal/domain/order"
+)
+
+// Layout, in points
+const (
+	margin      = 50.0
+	rowHeight   = 16.0
+	bottomLimit = 120.0 // leave room for the totals block
+	maxDescLen  = 48
+)
+
+// Right edges of the item table's numeric columns
+const (
+	colQuantity = 330.0
+	colUnit     = 400.0
+	colDiscount = 460.0
+	colTax      = 510.0
+	colAmount   = pageWidth - margin
+)
+
+// Renderer implements invoice.Renderer, producing A4 PDF invoices
+type Renderer struct{}
+
+// NewRenderer creates a new PDF invoice renderer
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// ContentType returns the MIME type of rendered documents
+func (r *Renderer) ContentType() string {
+	return "application/pdf"
+}
+
+// Render returns the invoice as a PDF document
+func (r *Renderer) Render(inv *invoice.Invoice) ([]byte, error) {
+	d := newDocument()
+	y := pageHeight - margin
+
+	d.text(margin, y-10, 22, true, "INVOICE")
+	d.textRight(colAmount, y-4, 10, true, inv.Number)
+	d.textRight(colAmount, y-18, 9, false, "Issued "+inv.IssuedAt.Format("2006-01-02"))
+	d.textRight(colAmount, y-30, 9, false, "Paid "+inv.PaidAt.Format("2006-01-02"))
+	d.textRight(colAmount, y-42, 9, false, "Order "+inv.OrderID.String())
+	y -= 80
+
+	partyTop := y
+	y = drawParty(d, margin, partyTop, "From", inv.Seller)
+	if buyerY := drawParty(d, pageWidth/2, partyTop, "Bill to", inv.Buyer); buyerY < y {
+		y = buyerY
+	}
+	y -= 20
+
+	y = drawTableHeader(d, y)
+	for _, line := range inv.Lines {
+		if y < bottomLimit {
+			d.addPage()
+			y = drawTableHeader(d, pageHeight-margin)
+		}
+		d.text(margin, y, 9, false, truncate(line.Description, maxDescLen))
+		d.textRight(colQuantity, y, 9, false, fmt.Sprintf("%d", line.Quantity))
+		d.textRight(colUnit, y, 9, false, money(line.UnitPrice))
+		d.textRight(colDiscount, y, 9, false, money(line.Discount))
+		d.textRight(colTax, y, 9, false, taxRate(line.TaxRateBps))
+		d.textRight(colAmount, y, 9, false, money(line.Total))
+		y -= rowHeight
+	}
+	d.line(margin, y+rowHeight-4, colAmount, y+rowHeight-4)
+
+	if y < bottomLimit {
+		d.addPage()
+		y = pageHeight - margin
+	}
+	y -= 6
+	total := func(label, value string, bold bool) {
+		d.textRight(colTax, y, 10, bold, label)
+		d.textRight(colAmount, y, 10, bold, value)
+		y -= rowHeight
+	}
+	total("Subtotal", money(inv.Subtotal), false)
+	if inv.Discount > 0 {
+		label := "Discount"
+		if inv.CouponCode != "" {
+			label += " (" + inv.CouponCode + ")"
+		}
+		total(label, "-"+money(inv.Discount), false)
+	}
+	total("Shipping", money(inv.ShippingCost), false)
+	if inv.TaxInclusive {
+		total("Tax included", money(inv.TaxAmount), false)
+	} else {
+		total("Tax", money(inv.TaxAmount), false)
+	}
+	total("Total", money(inv.Total), true)
+
+	return d.bytes()
+}
+
+// drawParty draws a labelled name and address block and returns the y
+// below it
+func drawParty(d *document, x, y float64, label string, p invoice.Party) float64 {
+	d.text(x, y, 8, true, strings.ToUpper(label))
+	y -= 14
+	d.text(x, y, 10, true, p.Name)
+	y -= 13
+	for _, line := range partyLines(p) {
+		d.text(x, y, 9, false, line)
+		y -= 12
+	}
+	return y
+}
+
+func partyLines(p invoice.Party) []string {
+	var lines []string
+	if p.Email != "" {
+		lines = append(lines, p.Email)
+	}
+	if a := p.Address; a != nil {
+		lines = append(lines, addressLines(a)...)
+	}
+	return lines
+}
+
+func addressLines(a *order.Address) []string {
+	lines := []string{a.Line1}
+	if a.Line2 != "" {
+		lines = append(lines, a.Line2)
+	}
+	city := strings.TrimSpace(strings.Join([]string{a.City, a.State, a.PostalCode}, " "))
+	lines = append(lines, city, a.Country)
+	return lines
+}
+
+// drawTableHeader draws the item table's column headings and returns the y
+// of the first row
+func drawTableHeader(d *document, y float64) float64 {
+	d.text(margin, y, 8, true, "DESCRIPTION")
+	d.textRight(colQuantity, y, 8, true, "QTY")
+	d.textRight(colUnit, y, 8, true, "UNIT")
+	d.textRight(colDiscount, y, 8, true, "DISCOUNT")
+	d.textRight(colTax, y, 8, true, "TAX")
+	d.textRight(colAmount, y, 8, true, "AMOUNT")
+	d.line(margin, y-5, colAmount, y-5)
+	return y - rowHeight - 2
+}
+
+func money(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
+
+func taxRate(bps int) string {
+	return fmt.Sprintf("%.2f%%", float64(bps)/100)
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,52 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/mail"
+	"github.com/google/uuid"
+)
+
+// Sender is a mail.Sender for local development. Messages are written to an
+// outbox directory as .eml files, or logged when no directory is set.
+type Sender struct {
+	dir  string
+	from string
+}
+
+// NewSender creates a new file sender writing to dir, which is created if
+// missing. An empty dir logs messages instead.
+func NewSender(dir, from string) (*Sender, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Sender{dir: dir, from: from}, nil
+}
+
+// Send writes or logs msg
+func (s *Sender) Send(ctx context.Context, msg *mail.Message) error {
+	now := time.Now()
+	if s.dir == "" {
+		log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405"), uuid.New().String()[:8])
+	return os.WriteFile(filepath.Join(s.dir, name), []byte(b.String()), 0o644)
+}
@@ -3,10 +3,15 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blytz/live/backend/internal/domain/auction"
+	"github.com/blytz/live/backend/internal/domain/order"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
@@ -16,16 +21,30 @@ const (
 	EventAuctionStarted  = "auction.started"
 	EventAuctionEnded    = "auction.ended"
 	EventAuctionExtended = "auction.extended"
+	EventReactions       = "auction.reactions"
+	EventOrderRefunded   = "order.refunded"
 )
 
+const (
+	// historyMaxLen bounds the per-auction event history kept for resuming clients
+	historyMaxLen = 500
+	// historyTTL is how long an idle auction's history is kept
+	historyTTL = 24 * time.Hour
+)
+
+// ErrHistoryGap is returned when events after the requested ID were trimmed
+var ErrHistoryGap = errors.New("event history no longer available")
+
 type EventBus struct {
 	client *redis.Client
 	prefix string
 }
 
 type Event struct {
+	ID        string                 `json:"id,omitempty"` // history stream ID
 	Type      string                 `json:"type"`
 	AuctionID string                 `json:"auction_id"`
+	OrderID   string                 `json:"order_id,omitempty"`
 	Timestamp time.Time              `json:"timestamp"`
 	Payload   map[string]interface{} `json:"payload"`
 }
@@ -82,6 +101,63 @@ func (b *EventBus) PublishAuctionExtended(ctx context.Context, auctionID uuid.UU
 	})
 }
 
+// PublishReactions publishes reaction counts aggregated over a short window.
+// Reactions are lossy by design and are not recorded in the auction history.
+// They go only to the global channel the hubs consume, so each batch costs a
+// single publish.
+func (b *EventBus) PublishReactions(ctx context.Context, auctionID uuid.UUID, counts map[string]int, window time.Duration) error {
+	e := Event{
+		Type:      EventReactions,
+		AuctionID: auctionID.String(),
+		Timestamp: time.Now(),
+		Payload: map[string]interface{}{
+			"counts":    counts,
+			"window_ms": window.Milliseconds(),
+		},
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	if err := b.client.Publish(ctx, b.prefix+"all", data).Err(); err != nil {
+		return fmt.Errorf("failed to publish to global channel: %w", err)
+	}
+	return nil
+}
+
+// PublishOrderRefunded publishes a refund so the buyer can be notified
+func (b *EventBus) PublishOrderRefunded(ctx context.Context, o *order.Order, amount float64, reason string) error {
+	return b.publishOrder(ctx, o, EventOrderRefunded, map[string]interface{}{
+		"buyer_id":  o.BuyerID.String(),
+		"seller_id": o.SellerID.String(),
+		"amount":    amount,
+		"reason":    reason,
+		"status":    string(o.Status),
+	})
+}
+
+// publishOrder publishes an order event on the global channel only;
+// order events are not part of any auction room's history
+func (b *EventBus) publishOrder(ctx context.Context, o *order.Order, eventType string, payload map[string]interface{}) error {
+	e := Event{
+		Type:      eventType,
+		OrderID:   o.ID.String(),
+		Timestamp: time.Now(),
+		Payload:   payload,
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, b.prefix+"all", data).Err(); err != nil {
+		return fmt.Errorf("failed to publish to global channel: %w", err)
+	}
+	return nil
+}
+
 func (b *EventBus) publish(ctx context.Context, auctionID uuid.UUID, eventType string, payload map[string]interface{}) error {
 	e := Event{
 		Type:      eventType,
@@ -95,6 +171,30 @@ func (b *EventBus) publish(ctx context.Context, auctionID uuid.UUID, eventType s
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	// Record in the auction's history so disconnected clients can resume
+	history := b.historyName(auctionID)
+	id, err := b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: history,
+		MaxLen: historyMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": data},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	b.client.Expire(ctx, history, historyTTL)
+
+	e.ID = id
+	data, err = json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return b.broadcast(ctx, auctionID, data)
+}
+
+// broadcast publishes a serialized event to the auction and global channels
+func (b *EventBus) broadcast(ctx context.Context, auctionID uuid.UUID, data []byte) error {
 	channel := b.channelName(auctionID)
 	if err := b.client.Publish(ctx, channel, data).Err(); err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
@@ -136,6 +236,113 @@ func (b *EventBus) SubscribeGlobal(ctx context.Context) (*Subscription, error) {
 	}, nil
 }
 
+// Consume subscribes to the global channel and calls handler for every
+// event until ctx is cancelled. Every instance receives every event, so
+// handlers must be idempotent.
+func (b *EventBus) Consume(ctx context.Context, handler func(context.Context, Event)) error {
+	sub, err := b.SubscribeGlobal(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return nil
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				log.Printf("Failed to unmarshal event: %v", err)
+				continue
+			}
+			handler(ctx, event)
+		}
+	}
+}
+
+// Replay returns up to limit events recorded after afterID, oldest first.
+// ErrHistoryGap means the client is too far behind and needs a full resync.
+func (b *EventBus) Replay(ctx context.Context, auctionID uuid.UUID, afterID string, limit int64) ([]Event, error) {
+	if _, _, ok := parseEventID(afterID); !ok {
+		return nil, ErrHistoryGap
+	}
+	history := b.historyName(auctionID)
+
+	oldest, err := b.client.XRangeN(ctx, history, "-", "+", 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	if len(oldest) == 0 || CompareEventIDs(oldest[0].ID, afterID) > 0 {
+		return nil, ErrHistoryGap
+	}
+
+	msgs, err := b.client.XRangeN(ctx, history, "("+afterID, "+", limit+1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	if int64(len(msgs)) > limit {
+		return nil, ErrHistoryGap
+	}
+
+	events := make([]Event, 0, len(msgs))
+	for _, msg := range msgs {
+		raw, _ := msg.Values["event"].(string)
+		var e Event
+		if err := json.Unmarshal([]byte(raw), &e); err != nil {
+			continue
+		}
+		e.ID = msg.ID
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// CompareEventIDs orders two event IDs, returning -1, 0 or 1
+func CompareEventIDs(a, b string) int {
+	aMs, aSeq, aOK := parseEventID(a)
+	bMs, bSeq, bOK := parseEventID(b)
+	if !aOK || !bOK {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case aMs < bMs:
+		return -1
+	case aMs > bMs:
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// parseEventID splits a Redis stream ID of the form <ms>-<seq>
+func parseEventID(id string) (uint64, uint64, bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
+
+func (b *EventBus) historyName(auctionID uuid.UUID) string {
+	return fmt.Sprintf("%sauction:%s:history", b.prefix, auctionID.String())
+}
+
 func (b *EventBus) channelName(auctionID uuid.UUID) string {
 	return fmt.Sprintf("%sauction:%s", b.prefix, auctionID.String())
 }
\ No newline at end of file
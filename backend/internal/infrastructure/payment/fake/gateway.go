@@ -0,0 +1,248 @@
+package fake
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/payment"
+)
+
+// Payment method references with deterministic outcomes. Any other
+// non-empty reference is treated as a valid card.
+const (
+	MethodSuccess           = "pm_fake_visa"
+	MethodDeclined          = "pm_fake_declined"
+	MethodInsufficientFunds = "pm_fake_insufficient_funds"
+	MethodCaptureFails      = "pm_fake_capture_fails"
+	// MethodExpiring saves as a card expiring at the end of the current month
+	MethodExpiring = "pm_fake_expiring"
+)
+
+// Gateway is an in-process payment.Gateway for local development and tests.
+// It never talks to the network and produces the same result for the same input.
+type Gateway struct {
+	secret string
+
+	mu      sync.Mutex
+	seq     int
+	intents map[string]*intent
+	byKey   map[string]string // idempotency key -> reference
+}
+
+type intent struct {
+	methodRef string
+	amount    int64
+	captured  int64
+	refunded  int64
+	status    payment.Status
+}
+
+// NewGateway creates a new fake gateway; secret signs webhook payloads
+func NewGateway(secret string) *Gateway {
+	return &Gateway{
+		secret:  secret,
+		intents: make(map[string]*intent),
+		byKey:   make(map[string]string),
+	}
+}
+
+// Name returns the provider name
+func (g *Gateway) Name() string {
+	return "fake"
+}
+
+// Authorize places a hold on a fake card
+func (g *Gateway) Authorize(ctx context.Context, req payment.AuthorizeRequest) (*payment.GatewayResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if ref, ok := g.byKey[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		return &payment.GatewayResult{Reference: ref, Status: g.intents[ref].status}, nil
+	}
+
+	switch req.MethodRef {
+	case "":
+		return nil, fmt.Errorf("%w: missing payment method", payment.ErrPaymentDeclined)
+	case MethodDeclined:
+		return nil, fmt.Errorf("%w: card declined", payment.ErrPaymentDeclined)
+	case MethodInsufficientFunds:
+		return nil, fmt.Errorf("%w: card has insufficient funds", payment.ErrInsufficientFunds)
+	}
+
+	g.seq++
+	ref := fmt.Sprintf("fake_pi_%06d", g.seq)
+	g.intents[ref] = &intent{
+		methodRef: req.MethodRef,
+		amount:    payment.ToMinorUnits(req.Amount),
+		status:    payment.StatusAuthorized,
+	}
+	if req.IdempotencyKey != "" {
+		g.byKey[req.IdempotencyKey] = ref
+	}
+
+	return &payment.GatewayResult{Reference: ref, Status: payment.StatusAuthorized}, nil
+}
+
+// Capture captures a fake authorization
+func (g *Gateway) Capture(ctx context.Context, reference string, amount float64) (*payment.GatewayResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	in, ok := g.intents[reference]
+	if !ok {
+		return nil, payment.ErrPaymentNotFound
+	}
+	if in.status == payment.StatusCaptured {
+		return &payment.GatewayResult{Reference: reference, Status: in.status}, nil
+	}
+	if in.status != payment.StatusAuthorized {
+		return nil, fmt.Errorf("%w: intent is %s", payment.ErrPaymentDeclined, in.status)
+	}
+	if in.methodRef == MethodCaptureFails {
+		return nil, fmt.Errorf("%w: capture failed", payment.ErrPaymentDeclined)
+	}
+
+	minor := payment.ToMinorUnits(amount)
+	if minor > in.amount {
+		return nil, fmt.Errorf("%w: capture exceeds authorized amount", payment.ErrPaymentDeclined)
+	}
+	in.captured = minor
+	in.status = payment.StatusCaptured
+
+	return &payment.GatewayResult{Reference: reference, Status: payment.StatusCaptured}, nil
+}
+
+// Void releases a fake authorization
+func (g *Gateway) Void(ctx context.Context, reference string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	in, ok := g.intents[reference]
+	if !ok {
+		return payment.ErrPaymentNotFound
+	}
+	if in.status != payment.StatusAuthorized && in.status != payment.StatusVoided {
+		return fmt.Errorf("%w: intent is %s", payment.ErrPaymentDeclined, in.status)
+	}
+	in.status = payment.StatusVoided
+	return nil
+}
+
+// Refund refunds part or all of a fake capture
+func (g *Gateway) Refund(ctx context.Context, req payment.RefundRequest) (*payment.RefundResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	in, ok := g.intents[req.Reference]
+	if !ok {
+		return nil, payment.ErrPaymentNotFound
+	}
+
+	minor := payment.ToMinorUnits(req.Amount)
+	if in.captured-in.refunded < minor {
+		return nil, fmt.Errorf("%w: refund exceeds captured amount", payment.ErrPaymentDeclined)
+	}
+	in.refunded += minor
+	if in.refunded == in.captured {
+		in.status = payment.StatusRefunded
+	} else {
+		in.status = payment.StatusPartiallyRefunded
+	}
+
+	g.seq++
+	return &payment.RefundResult{
+		Reference: fmt.Sprintf("fake_re_%06d", g.seq),
+		Amount:    req.Amount,
+	}, nil
+}
+
+// SaveMethod accepts any non-empty token as a card ending in 4242.
+// The token itself becomes the method reference so the test methods keep their outcomes.
+func (g *Gateway) SaveMethod(ctx context.Context, req payment.SaveMethodRequest) (*payment.SavedMethod, error) {
+	if req.Token == "" {
+		return nil, fmt.Errorf("%w: missing token", payment.ErrPaymentDeclined)
+	}
+	if req.Token == MethodDeclined {
+		return nil, fmt.Errorf("%w: card declined", payment.ErrPaymentDeclined)
+	}
+
+	customerRef := req.CustomerRef
+	if customerRef == "" {
+		customerRef = "fake_cus_" + req.UserID.String()
+	}
+
+	now := time.Now().UTC()
+	expYear, expMonth := now.Year()+3, 12
+	if req.Token == MethodExpiring {
+		expYear, expMonth = now.Year(), int(now.Month())
+	}
+
+	return &payment.SavedMethod{
+		MethodRef:   req.Token,
+		CustomerRef: customerRef,
+		Type:        "card",
+		Last4:       "4242",
+		ExpMonth:    expMonth,
+		ExpYear:     expYear,
+	}, nil
+}
+
+// RemoveMethod forgets a saved method; the fake keeps no method state
+func (g *Gateway) RemoveMethod(ctx context.Context, methodRef string) error {
+	return nil
+}
+
+// webhookPayload is the JSON body of fake webhooks
+type webhookPayload struct {
+	ID            string  `json:"id"`
+	Type          string  `json:"type"`
+	Reference     string  `json:"reference"`
+	Amount        float64 `json:"amount"`
+	FailureReason string  `json:"failure_reason,omitempty"`
+}
+
+// VerifyWebhook verifies a hex HMAC-SHA256 signature of the payload
+func (g *Gateway) VerifyWebhook(payload []byte, signature string) (*payment.WebhookEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, g.sign(payload)) {
+		return nil, payment.ErrInvalidSignature
+	}
+
+	var body webhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	eventType := payment.WebhookEventType(body.Type)
+	switch eventType {
+	case payment.WebhookAuthorized, payment.WebhookCaptured, payment.WebhookFailed,
+		payment.WebhookVoided, payment.WebhookRefunded:
+	default:
+		return nil, payment.ErrUnknownPaymentEvent
+	}
+
+	return &payment.WebhookEvent{
+		ID:            body.ID,
+		Type:          eventType,
+		Reference:     body.Reference,
+		Amount:        body.Amount,
+		FailureReason: body.FailureReason,
+	}, nil
+}
+
+// Sign returns the signature header value for a webhook payload, for local testing
+func (g *Gateway) Sign(payload []byte) string {
+	return hex.EncodeToString(g.sign(payload))
+}
+
+func (g *Gateway) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(g.secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,378 @@
+package stripe
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/payment"
+)
+
+const (
+	defaultBaseURL = "https://api.stripe.com"
+	// signatureTolerance is the maximum age of a webhook signature
+	signatureTolerance = 5 * time.Minute
+)
+
+// Config holds Stripe configuration
+type Config struct {
+	SecretKey     string
+	WebhookSecret string
+	BaseURL       string // override for Stripe-compatible APIs and mocks
+}
+
+// Gateway implements payment.Gateway against the Stripe PaymentIntents API
+// using manual capture, so authorize and capture are separate calls.
+type Gateway struct {
+	secretKey     string
+	webhookSecret string
+	baseURL       string
+	httpClient    *http.Client
+}
+
+// NewGateway creates a new Stripe gateway
+func NewGateway(cfg Config) *Gateway {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Gateway{
+		secretKey:     cfg.SecretKey,
+		webhookSecret: cfg.WebhookSecret,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the provider name
+func (g *Gateway) Name() string {
+	return "stripe"
+}
+
+// paymentIntent is the subset of the Stripe PaymentIntent object we use
+type paymentIntent struct {
+	ID               string `json:"id"`
+	Status           string `json:"status"`
+	Amount           int64  `json:"amount"`
+	AmountReceived   int64  `json:"amount_received"`
+	LastPaymentError *struct {
+		Code        string `json:"code"`
+		DeclineCode string `json:"decline_code"`
+		Message     string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+type refund struct {
+	ID     string `json:"id"`
+	Amount int64  `json:"amount"`
+	Status string `json:"status"`
+}
+
+// paymentMethod is the subset of the Stripe PaymentMethod object we use
+type paymentMethod struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Customer string `json:"customer"`
+	Card     *struct {
+		Last4    string `json:"last4"`
+		ExpMonth int    `json:"exp_month"`
+		ExpYear  int    `json:"exp_year"`
+	} `json:"card"`
+}
+
+type customer struct {
+	ID string `json:"id"`
+}
+
+type apiError struct {
+	Error struct {
+		Type        string `json:"type"`
+		Code        string `json:"code"`
+		DeclineCode string `json:"decline_code"`
+		Message     string `json:"message"`
+	} `json:"error"`
+}
+
+// Authorize creates and confirms a manual-capture PaymentIntent
+func (g *Gateway) Authorize(ctx context.Context, req payment.AuthorizeRequest) (*payment.GatewayResult, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(payment.ToMinorUnits(req.Amount), 10))
+	form.Set("currency", strings.ToLower(req.Currency))
+	form.Set("payment_method", req.MethodRef)
+	form.Set("capture_method", "manual")
+	form.Set("confirm", "true")
+	form.Set("off_session", "true")
+	if req.CustomerRef != "" {
+		form.Set("customer", req.CustomerRef)
+	}
+	if req.Description != "" {
+		form.Set("description", req.Description)
+	}
+	for k, v := range req.Metadata {
+		form.Set(fmt.Sprintf("metadata[%s]", k), v)
+	}
+
+	var pi paymentIntent
+	if err := g.post(ctx, "/v1/payment_intents", form, req.IdempotencyKey, &pi); err != nil {
+		return nil, err
+	}
+
+	if pi.Status != "requires_capture" && pi.Status != "succeeded" {
+		return nil, fmt.Errorf("%w: payment intent status %s", payment.ErrPaymentDeclined, pi.Status)
+	}
+
+	return &payment.GatewayResult{Reference: pi.ID, Status: intentStatus(pi.Status)}, nil
+}
+
+// Capture captures an authorized PaymentIntent
+func (g *Gateway) Capture(ctx context.Context, reference string, amount float64) (*payment.GatewayResult, error) {
+	form := url.Values{}
+	form.Set("amount_to_capture", strconv.FormatInt(payment.ToMinorUnits(amount), 10))
+
+	var pi paymentIntent
+	path := fmt.Sprintf("/v1/payment_intents/%s/capture", url.PathEscape(reference))
+	if err := g.post(ctx, path, form, "capture-"+reference, &pi); err != nil {
+		return nil, err
+	}
+
+	return &payment.GatewayResult{Reference: pi.ID, Status: intentStatus(pi.Status)}, nil
+}
+
+// Void cancels an uncaptured PaymentIntent
+func (g *Gateway) Void(ctx context.Context, reference string) error {
+	var pi paymentIntent
+	path := fmt.Sprintf("/v1/payment_intents/%s/cancel", url.PathEscape(reference))
+	return g.post(ctx, path, url.Values{}, "void-"+reference, &pi)
+}
+
+// Refund refunds a captured PaymentIntent
+func (g *Gateway) Refund(ctx context.Context, req payment.RefundRequest) (*payment.RefundResult, error) {
+	form := url.Values{}
+	form.Set("payment_intent", req.Reference)
+	form.Set("amount", strconv.FormatInt(payment.ToMinorUnits(req.Amount), 10))
+	if req.Reason != "" {
+		form.Set("metadata[reason]", req.Reason)
+	}
+
+	var r refund
+	if err := g.post(ctx, "/v1/refunds", form, req.IdempotencyKey, &r); err != nil {
+		return nil, err
+	}
+
+	return &payment.RefundResult{Reference: r.ID, Amount: payment.FromMinorUnits(r.Amount)}, nil
+}
+
+// SaveMethod attaches a PaymentMethod created with Stripe.js to a customer,
+// creating the customer on first use
+func (g *Gateway) SaveMethod(ctx context.Context, req payment.SaveMethodRequest) (*payment.SavedMethod, error) {
+	customerRef := req.CustomerRef
+	if customerRef == "" {
+		form := url.Values{}
+		form.Set("metadata[user_id]", req.UserID.String())
+
+		var c customer
+		if err := g.post(ctx, "/v1/customers", form, "customer-"+req.UserID.String(), &c); err != nil {
+			return nil, err
+		}
+		customerRef = c.ID
+	}
+
+	form := url.Values{}
+	form.Set("customer", customerRef)
+
+	var pm paymentMethod
+	path := fmt.Sprintf("/v1/payment_methods/%s/attach", url.PathEscape(req.Token))
+	if err := g.post(ctx, path, form, "", &pm); err != nil {
+		return nil, err
+	}
+
+	saved := &payment.SavedMethod{
+		MethodRef:   pm.ID,
+		CustomerRef: customerRef,
+		Type:        pm.Type,
+	}
+	if pm.Card != nil {
+		saved.Last4 = pm.Card.Last4
+		saved.ExpMonth = pm.Card.ExpMonth
+		saved.ExpYear = pm.Card.ExpYear
+	}
+	return saved, nil
+}
+
+// RemoveMethod detaches a PaymentMethod from its customer
+func (g *Gateway) RemoveMethod(ctx context.Context, methodRef string) error {
+	var pm paymentMethod
+	path := fmt.Sprintf("/v1/payment_methods/%s/detach", url.PathEscape(methodRef))
+	return g.post(ctx, path, url.Values{}, "", &pm)
+}
+
+// VerifyWebhook verifies a Stripe-Signature header and parses the event
+func (g *Gateway) VerifyWebhook(payload []byte, signature string) (*payment.WebhookEvent, error) {
+	if err := verifySignature(payload, signature, g.webhookSecret, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var event struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	result := &payment.WebhookEvent{ID: event.ID}
+
+	switch event.Type {
+	case "payment_intent.amount_capturable_updated":
+		result.Type = payment.WebhookAuthorized
+	case "payment_intent.succeeded":
+		result.Type = payment.WebhookCaptured
+	case "payment_intent.payment_failed":
+		result.Type = payment.WebhookFailed
+	case "payment_intent.canceled":
+		result.Type = payment.WebhookVoided
+	case "charge.refunded":
+		var charge struct {
+			PaymentIntent  string `json:"payment_intent"`
+			AmountRefunded int64  `json:"amount_refunded"`
+		}
+		if err := json.Unmarshal(event.Data.Object, &charge); err != nil {
+			return nil, fmt.Errorf("invalid charge object: %w", err)
+		}
+		result.Type = payment.WebhookRefunded
+		result.Reference = charge.PaymentIntent
+		result.Amount = payment.FromMinorUnits(charge.AmountRefunded)
+		return result, nil
+	default:
+		return nil, payment.ErrUnknownPaymentEvent
+	}
+
+	var pi paymentIntent
+	if err := json.Unmarshal(event.Data.Object, &pi); err != nil {
+		return nil, fmt.Errorf("invalid payment intent object: %w", err)
+	}
+	result.Reference = pi.ID
+	result.Amount = payment.FromMinorUnits(pi.Amount)
+	if pi.LastPaymentError != nil {
+		result.FailureReason = pi.LastPaymentError.Message
+	}
+
+	return result, nil
+}
+
+func (g *Gateway) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(g.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", payment.ErrGatewayUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", payment.ErrGatewayUnavailable, err)
+	}
+
+	if resp.StatusCode >= 300 {
+		return parseError(resp.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func parseError(status int, body []byte) error {
+	var apiErr apiError
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return fmt.Errorf("%w: status %d", payment.ErrGatewayUnavailable, status)
+	}
+
+	e := apiErr.Error
+	switch {
+	case e.DeclineCode == "insufficient_funds":
+		return fmt.Errorf("%w: %s", payment.ErrInsufficientFunds, e.Message)
+	case e.Type == "card_error":
+		return fmt.Errorf("%w: %s", payment.ErrPaymentDeclined, e.Message)
+	case status >= 500 || status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", payment.ErrGatewayUnavailable, e.Message)
+	default:
+		return fmt.Errorf("stripe error (%s): %s", e.Code, e.Message)
+	}
+}
+
+func intentStatus(status string) payment.Status {
+	switch status {
+	case "requires_capture":
+		return payment.StatusAuthorized
+	case "succeeded":
+		return payment.StatusCaptured
+	case "canceled":
+		return payment.StatusVoided
+	default:
+		return payment.StatusPending
+	}
+}
+
+// verifySignature checks a "t=<unix>,v1=<hex>" signature header
+func verifySignature(payload []byte, header, secret string, now time.Time) error {
+	if secret == "" || header == "" {
+		return payment.ErrInvalidSignature
+	}
+
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp, _ = strconv.ParseInt(kv[1], 10, 64)
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return payment.ErrInvalidSignature
+	}
+
+	signedAt := time.Unix(timestamp, 0)
+	if now.Sub(signedAt) > signatureTolerance || signedAt.Sub(now) > signatureTolerance {
+		return payment.ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, sig := range signatures {
+		decoded, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return payment.ErrInvalidSignature
+}
@@ -0,0 +1,45 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/blytz/live/backend/internal/domain/ledger"
+)
+
+// Provider is an in-process ledger.PayoutProvider for local development and tests.
+// Transfers always succeed; repeated idempotency keys return the original reference.
+type Provider struct {
+	mu    sync.Mutex
+	seq   int
+	byKey map[string]string // idempotency key -> reference
+}
+
+// NewProvider creates a new fake payout provider
+func NewProvider() *Provider {
+	return &Provider{byKey: make(map[string]string)}
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "fake"
+}
+
+// Send records a transfer without moving any money
+func (p *Provider) Send(ctx context.Context, req ledger.PayoutRequest) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ref, ok := p.byKey[req.IdempotencyKey]; ok {
+		return ref, nil
+	}
+
+	p.seq++
+	ref := fmt.Sprintf("fake_po_%06d", p.seq)
+	p.byKey[req.IdempotencyKey] = ref
+
+	log.Printf("Fake payout %s: %d %s to seller %s", ref, req.Amount, req.Currency, req.SellerID)
+	return ref, nil
+}
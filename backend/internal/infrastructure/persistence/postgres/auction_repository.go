@@ -22,10 +22,24 @@ func NewAuctionRepository(db *gorm.DB) *AuctionRepository {
 	return &AuctionRepository{db: db}
 }
 
-// Create creates a new auction
+// Create creates a new auction, marking the auction it relists, if any
 func (r *AuctionRepository) Create(ctx context.Context, a *auction.Auction) error {
 	model := toAuctionModel(a)
-	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if model.RelistedFrom != nil {
+			result := tx.Model(&Auction{}).
+				Where("id = ? AND relisted_as IS NULL", *model.RelistedFrom).
+				Update("relisted_as", model.ID)
+			if result.Error != nil {
+				return fmt.Errorf("failed to mark relisted auction: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return auction.ErrAlreadyRelisted
+			}
+		}
+		return tx.Create(model).Error
+	})
+	if err != nil {
 		return err
 	}
 	a.ID = model.ID
@@ -60,13 +74,25 @@ func (r *AuctionRepository) GetByID(ctx context.Context, id uuid.UUID) (*auction
 // GetWithBids gets auction with current bid
 func (r *AuctionRepository) GetWithBids(ctx context.Context, id uuid.UUID) (*auction.Auction, error) {
 	var model Auction
-	if err := r.db.WithContext(ctx).Preload("CurrentBid").First(&model, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, appErrors.New(appErrors.ErrNotFound, "auction not found")
 		}
 		return nil, err
 	}
-	return toAuctionDomain(&model), nil
+
+	a := toAuctionDomain(&model)
+	if model.CurrentBidID != nil {
+		var bid Bid
+		if err := r.db.WithContext(ctx).First(&bid, "id = ?", *model.CurrentBidID).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+		} else {
+			a.CurrentBid = toBidDomain(&bid)
+		}
+	}
+	return a, nil
 }
 
 // GetLiveAuctions gets currently live auctions
@@ -121,7 +147,7 @@ func (r *AuctionRepository) AddBid(ctx context.Context, bid *auction.Bid) error
 
 		// Update previous winning bids
 		if err := tx.Model(&Bid{}).
-			Where("auction_id = ? AND user_id != ? AND is_winning = ?", 
+			Where("auction_id = ? AND user_id != ? AND is_winning = ?",
 				bid.AuctionID, bid.UserID, true).
 			Update("is_winning", false).Error; err != nil {
 			return fmt.Errorf("failed to update previous bids: %w", err)
@@ -215,49 +241,57 @@ func toAuctionModel(a *auction.Auction) *Auction {
 			CreatedAt: a.CreatedAt,
 			UpdatedAt: a.UpdatedAt,
 		},
-		ProductID:    a.ProductID,
-		SellerID:     a.SellerID,
-		Title:        a.Title,
-		Description:  a.Description,
-		StartTime:    a.StartTime,
-		EndTime:      a.EndTime,
-		Status:       string(a.Status),
-		StartPrice:   a.StartPrice,
-		ReservePrice: a.ReservePrice,
-		BuyNowPrice:  a.BuyNowPrice,
-		CurrentBidID: currentBidID,
-		BidCount:     a.BidCount,
-		WinnerID:     a.WinnerID,
-		LiveKitRoom:  a.LiveKitRoom,
-		StreamKey:    a.StreamKey,
-		AutoExtend:   a.AutoExtend,
-		ExtendTime:   int(a.ExtendTime.Seconds()),
-		IsFeatured:   a.IsFeatured,
+		ProductID:         a.ProductID,
+		SellerID:          a.SellerID,
+		Title:             a.Title,
+		Description:       a.Description,
+		StartTime:         a.StartTime,
+		EndTime:           a.EndTime,
+		Status:            string(a.Status),
+		StartPrice:        a.StartPrice,
+		ReservePrice:      a.ReservePrice,
+		BuyNowPrice:       a.BuyNowPrice,
+		BidderRequirement: string(a.BidderRequirement),
+		DepositAmount:     a.DepositAmount,
+		CurrentBidID:      currentBidID,
+		BidCount:          a.BidCount,
+		WinnerID:          a.WinnerID,
+		LiveKitRoom:       a.LiveKitRoom,
+		StreamKey:         a.StreamKey,
+		AutoExtend:        a.AutoExtend,
+		ExtendTime:        int(a.ExtendTime.Seconds()),
+		IsFeatured:        a.IsFeatured,
+		RelistedFrom:      a.RelistedFrom,
+		RelistedAs:        a.RelistedAs,
 	}
 }
 
 func toAuctionDomain(m *Auction) *auction.Auction {
 	a := &auction.Auction{
-		ID:           m.ID,
-		ProductID:    m.ProductID,
-		SellerID:     m.SellerID,
-		Title:        m.Title,
-		Description:  m.Description,
-		StartTime:    m.StartTime,
-		EndTime:      m.EndTime,
-		Status:       auction.Status(m.Status),
-		StartPrice:   m.StartPrice,
-		ReservePrice: m.ReservePrice,
-		BuyNowPrice:  m.BuyNowPrice,
-		BidCount:     m.BidCount,
-		WinnerID:     m.WinnerID,
-		LiveKitRoom:  m.LiveKitRoom,
-		StreamKey:    m.StreamKey,
-		AutoExtend:   m.AutoExtend,
-		ExtendTime:   time.Duration(m.ExtendTime) * time.Second,
-		IsFeatured:   m.IsFeatured,
-		CreatedAt:    m.CreatedAt,
-		UpdatedAt:    m.UpdatedAt,
+		ID:                m.ID,
+		ProductID:         m.ProductID,
+		SellerID:          m.SellerID,
+		Title:             m.Title,
+		Description:       m.Description,
+		StartTime:         m.StartTime,
+		EndTime:           m.EndTime,
+		Status:            auction.Status(m.Status),
+		StartPrice:        m.StartPrice,
+		ReservePrice:      m.ReservePrice,
+		BuyNowPrice:       m.BuyNowPrice,
+		BidderRequirement: auction.BidderRequirement(m.BidderRequirement),
+		DepositAmount:     m.DepositAmount,
+		BidCount:          m.BidCount,
+		WinnerID:          m.WinnerID,
+		LiveKitRoom:       m.LiveKitRoom,
+		StreamKey:         m.StreamKey,
+		AutoExtend:        m.AutoExtend,
+		ExtendTime:        time.Duration(m.ExtendTime) * time.Second,
+		IsFeatured:        m.IsFeatured,
+		RelistedFrom:      m.RelistedFrom,
+		RelistedAs:        m.RelistedAs,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
 	}
 
 	// Current bid would be populated via preload or separate query
@@ -320,4 +354,4 @@ func toAutoBidDomain(m *AutoBid) *auction.AutoBid {
 		CreatedAt:    m.CreatedAt,
 		UpdatedAt:    m.UpdatedAt,
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/cart"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CartRepository implements cart.Repository
+type CartRepository struct {
+	db *gorm.DB
+}
+
+// NewCartRepository creates a new cart repository
+func NewCartRepository(db *gorm.DB) *CartRepository {
+	return &CartRepository{db: db}
+}
+
+// Create creates a cart with its items
+func (r *CartRepository) Create(ctx context.Context, c *cart.Cart) error {
+	model := toCartModel(c)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create cart: %w", err)
+		}
+		c.ID = model.ID
+		c.CreatedAt = model.CreatedAt
+		c.UpdatedAt = model.UpdatedAt
+
+		return r.createItems(tx, c)
+	})
+}
+
+// Save updates a cart and replaces its items
+func (r *CartRepository) Save(ctx context.Context, c *cart.Cart) error {
+	model := toCartModel(c)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(model).Error; err != nil {
+			return fmt.Errorf("failed to update cart: %w", err)
+		}
+		if err := tx.Unscoped().Where("cart_id = ?", c.ID).Delete(&CartItem{}).Error; err != nil {
+			return fmt.Errorf("failed to clear cart items: %w", err)
+		}
+		return r.createItems(tx, c)
+	})
+}
+
+// GetByToken retrieves a cart by its token
+func (r *CartRepository) GetByToken(ctx context.Context, token string) (*cart.Cart, error) {
+	var model Cart
+	if err := r.db.WithContext(ctx).First(&model, "token = ?", token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cart.ErrCartNotFound
+		}
+		return nil, err
+	}
+	return r.withItems(ctx, &model)
+}
+
+// GetByUserID retrieves a user's cart
+func (r *CartRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*cart.Cart, error) {
+	var model Cart
+	if err := r.db.WithContext(ctx).First(&model, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cart.ErrCartNotFound
+		}
+		return nil, err
+	}
+	return r.withItems(ctx, &model)
+}
+
+// Delete deletes a cart and its items
+func (r *CartRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("cart_id = ?", id).Delete(&CartItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&Cart{}, "id = ?", id).Error
+	})
+}
+
+// DeleteExpired deletes carts that expired before the given time
+func (r *CartRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&Cart{}).Unscoped().Select("id").Where("expires_at < ?", before)
+		if err := tx.Unscoped().Where("cart_id IN (?)", expired).Delete(&CartItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("expires_at < ?", before).Delete(&Cart{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *CartRepository) createItems(tx *gorm.DB, c *cart.Cart) error {
+	for i := range c.Items {
+		c.Items[i].CartID = c.ID
+		item := toCartItemModel(&c.Items[i])
+		if err := tx.Create(item).Error; err != nil {
+			return fmt.Errorf("failed to create cart item: %w", err)
+		}
+		c.Items[i].ID = item.ID
+	}
+	return nil
+}
+
+func (r *CartRepository) withItems(ctx context.Context, model *Cart) (*cart.Cart, error) {
+	var items []CartItem
+	if err := r.db.WithContext(ctx).Where("cart_id = ?", model.ID).Order("added_at ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	c := toCartDomain(model)
+	for i := range items {
+		c.Items = append(c.Items, toCartItemDomain(&items[i]))
+	}
+	return c, nil
+}
+
+// Helper functions
+func toCartModel(c *cart.Cart) *Cart {
+	return &Cart{
+		BaseModel: BaseModel{
+			ID:        c.ID,
+			CreatedAt: c.CreatedAt,
+			UpdatedAt: c.UpdatedAt,
+		},
+		UserID:     c.UserID,
+		Token:      c.Token,
+		CouponCode: c.CouponCode,
+		ExpiresAt:  c.ExpiresAt,
+	}
+}
+
+func toCartDomain(m *Cart) *cart.Cart {
+	return &cart.Cart{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Token:      m.Token,
+		CouponCode: m.CouponCode,
+		Items:      []cart.Item{},
+		ExpiresAt:  m.ExpiresAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func toCartItemModel(i *cart.Item) *CartItem {
+	return &CartItem{
+		BaseModel: BaseModel{
+			ID: i.ID,
+		},
+		CartID:    i.CartID,
+		ProductID: i.ProductID,
+		Quantity:  i.Quantity,
+		UnitPrice: i.UnitPrice,
+		AddedAt:   i.AddedAt,
+	}
+}
+
+func toCartItemDomain(m *CartItem) cart.Item {
+	return cart.Item{
+		ID:        m.ID,
+		CartID:    m.CartID,
+		ProductID: m.ProductID,
+		Quantity:  m.Quantity,
+		UnitPrice: m.UnitPrice,
+		AddedAt:   m.AddedAt,
+	}
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/deposit"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BidderHoldModel represents the bidder deposit hold database model
+type BidderHoldModel struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AuctionID        uuid.UUID  `gorm:"type:uuid;not null;index:idx_bidder_holds_auction_user"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index:idx_bidder_holds_auction_user"`
+	Amount           float64    `gorm:"type:decimal(12,2);not null"`
+	Currency         string     `gorm:"size:3;not null"`
+	Provider         string     `gorm:"not null"`
+	MethodID         *uuid.UUID `gorm:"type:uuid"`
+	GatewayReference string     `gorm:"not null"`
+	Status           string     `gorm:"not null;index"`
+	CreatedAt        time.Time
+	ReleasedAt       *time.Time
+}
+
+func (BidderHoldModel) TableName() string {
+	return "bidder_holds"
+}
+
+// DepositRepository implements deposit.Repository
+type DepositRepository struct {
+	db *gorm.DB
+}
+
+// NewDepositRepository creates a new deposit hold repository
+func NewDepositRepository(db *gorm.DB) *DepositRepository {
+	return &DepositRepository{db: db}
+}
+
+// Create saves a hold, failing with ErrHoldExists if the bidder already has
+// an active hold on the auction
+func (r *DepositRepository) Create(ctx context.Context, h *deposit.Hold) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the auction so concurrent requests cannot both place a hold
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&Auction{}, "id = ?", h.AuctionID).Error; err != nil {
+			return err
+		}
+
+		var active int64
+		err := tx.Model(&BidderHoldModel{}).
+			Where("auction_id = ? AND user_id = ? AND status = ?", h.AuctionID, h.UserID, string(deposit.StatusHeld)).
+			Count(&active).Error
+		if err != nil {
+			return err
+		}
+		if active > 0 {
+			return deposit.ErrHoldExists
+		}
+
+		return tx.Create(toBidderHoldModel(h)).Error
+	})
+}
+
+// Update updates a hold's status
+func (r *DepositRepository) Update(ctx context.Context, h *deposit.Hold) error {
+	result := r.db.WithContext(ctx).Model(&BidderHoldModel{}).
+		Where("id = ?", h.ID).
+		Select("status", "released_at").
+		Updates(toBidderHoldModel(h))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return deposit.ErrHoldNotFound
+	}
+	return nil
+}
+
+// GetActive retrieves a bidder's active hold on an auction
+func (r *DepositRepository) GetActive(ctx context.Context, auctionID, userID uuid.UUID) (*deposit.Hold, error) {
+	var model BidderHoldModel
+	err := r.db.WithContext(ctx).
+		Where("auction_id = ? AND user_id = ? AND status = ?", auctionID, userID, string(deposit.StatusHeld)).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, deposit.ErrHoldNotFound
+		}
+		return nil, err
+	}
+	return toBidderHoldDomain(&model), nil
+}
+
+// ListActiveByAuction retrieves all active holds on an auction
+func (r *DepositRepository) ListActiveByAuction(ctx context.Context, auctionID uuid.UUID) ([]*deposit.Hold, error) {
+	var models []BidderHoldModel
+	err := r.db.WithContext(ctx).
+		Where("auction_id = ? AND status = ?", auctionID, string(deposit.StatusHeld)).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	holds := make([]*deposit.Hold, len(models))
+	for i := range models {
+		holds[i] = toBidderHoldDomain(&models[i])
+	}
+	return holds, nil
+}
+
+// AutoMigrateDeposit runs migrations for the bidder hold table
+func AutoMigrateDeposit(db *gorm.DB) error {
+	return db.AutoMigrate(&BidderHoldModel{})
+}
+
+// Helper functions
+func toBidderHoldModel(h *deposit.Hold) *BidderHoldModel {
+	return &BidderHoldModel{
+		ID:               h.ID,
+		AuctionID:        h.AuctionID,
+		UserID:           h.UserID,
+		Amount:           h.Amount,
+		Currency:         h.Currency,
+		Provider:         h.Provider,
+		MethodID:         h.MethodID,
+		GatewayReference: h.GatewayReference,
+		Status:           string(h.Status),
+		CreatedAt:        h.CreatedAt,
+		ReleasedAt:       h.ReleasedAt,
+	}
+}
+
+func toBidderHoldDomain(m *BidderHoldModel) *deposit.Hold {
+	return &deposit.Hold{
+		ID:               m.ID,
+		AuctionID:        m.AuctionID,
+		UserID:           m.UserID,
+		Amount:           m.Amount,
+		Currency:         m.Currency,
+		Provider:         m.Provider,
+		MethodID:         m.MethodID,
+		GatewayReference: m.GatewayReference,
+		Status:           deposit.Status(m.Status),
+		CreatedAt:        m.CreatedAt,
+		ReleasedAt:       m.ReleasedAt,
+	}
+}
@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/dispute"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DisputeModel represents the dispute database model
+type DisputeModel struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrderID         uuid.UUID `gorm:"type:uuid;not null;index"`
+	BuyerID         uuid.UUID `gorm:"type:uuid;not null;index"`
+	SellerID        uuid.UUID `gorm:"type:uuid;not null;index"`
+	Reason          string    `gorm:"not null"`
+	Description     string    `gorm:"type:text"`
+	RequestedAmount float64   `gorm:"type:decimal(12,2);not null;default:0"`
+	Status          string    `gorm:"not null;index:idx_disputes_deadline"`
+	SellerResponse  string    `gorm:"type:text"`
+	OfferedAmount   float64   `gorm:"type:decimal(12,2);not null;default:0"`
+	RespondedAt     *time.Time
+	DeadlineAt      *time.Time `gorm:"index:idx_disputes_deadline"`
+	EscalatedAt     *time.Time
+	Outcome         string
+	RefundAmount    float64    `gorm:"type:decimal(12,2);not null;default:0"`
+	RefundID        *uuid.UUID `gorm:"type:uuid"`
+	ResolvedBy      *uuid.UUID `gorm:"type:uuid"`
+	ResolutionNote  string     `gorm:"type:text"`
+	ResolvedAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (DisputeModel) TableName() string {
+	return "disputes"
+}
+
+// DisputeEvidenceModel represents the dispute evidence database model
+type DisputeEvidenceModel struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DisputeID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	AuthorID    uuid.UUID `gorm:"type:uuid;not null"`
+	Note        string    `gorm:"type:text"`
+	FileURL     string
+	FileKey     string
+	ContentType string
+	CreatedAt   time.Time
+}
+
+func (DisputeEvidenceModel) TableName() string {
+	return "dispute_evidence"
+}
+
+// DisputeRepository implements dispute.Repository
+type DisputeRepository struct {
+	db *gorm.DB
+}
+
+// NewDisputeRepository creates a new dispute repository
+func NewDisputeRepository(db *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// Create creates a dispute, failing with ErrDisputeExists if the order
+// already has an unresolved one
+func (r *DisputeRepository) Create(ctx context.Context, d *dispute.Dispute) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the order so concurrent requests cannot both open a dispute
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&Order{}, "id = ?", d.OrderID).Error; err != nil {
+			return err
+		}
+
+		var open int64
+		err := tx.Model(&DisputeModel{}).
+			Where("order_id = ? AND status <> ?", d.OrderID, string(dispute.StatusResolved)).
+			Count(&open).Error
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return dispute.ErrDisputeExists
+		}
+
+		return tx.Create(toDisputeModel(d)).Error
+	})
+}
+
+// TransitionStatus updates a dispute's status, response, deadlines and
+// resolution if the stored dispute is still in status from
+func (r *DisputeRepository) TransitionStatus(ctx context.Context, d *dispute.Dispute, from dispute.Status) error {
+	result := r.db.WithContext(ctx).Model(&DisputeModel{}).
+		Where("id = ? AND status = ?", d.ID, string(from)).
+		Select("status", "seller_response", "offered_amount", "responded_at", "deadline_at", "escalated_at",
+			"outcome", "refund_amount", "refund_id", "resolved_by", "resolution_note", "resolved_at", "updated_at").
+		Updates(toDisputeModel(d))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dispute.ErrStatusConflict
+	}
+	return nil
+}
+
+// GetByID retrieves a dispute by ID
+func (r *DisputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*dispute.Dispute, error) {
+	var model DisputeModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dispute.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return toDisputeDomain(&model), nil
+}
+
+// ListByOrder retrieves an order's disputes, newest first
+func (r *DisputeRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*dispute.Dispute, error) {
+	var models []DisputeModel
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDisputeDomains(models), nil
+}
+
+// List retrieves disputes matching the filter, newest first
+func (r *DisputeRepository) List(ctx context.Context, filter dispute.Filter) ([]*dispute.Dispute, int64, error) {
+	var models []DisputeModel
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&DisputeModel{})
+
+	if filter.BuyerID != nil {
+		query = query.Where("buyer_id = ?", *filter.BuyerID)
+	}
+	if filter.SellerID != nil {
+		query = query.Where("seller_id = ?", *filter.SellerID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", string(*filter.Status))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return toDisputeDomains(models), total, nil
+}
+
+// ListOverdue retrieves unresolved disputes whose deadline passed before now
+func (r *DisputeRepository) ListOverdue(ctx context.Context, now time.Time, limit int) ([]*dispute.Dispute, error) {
+	var models []DisputeModel
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND deadline_at < ?",
+			[]string{string(dispute.StatusOpened), string(dispute.StatusSellerResponded)}, now).
+		Order("deadline_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toDisputeDomains(models), nil
+}
+
+// AddEvidence adds evidence to a dispute
+func (r *DisputeRepository) AddEvidence(ctx context.Context, e *dispute.Evidence) error {
+	model := &DisputeEvidenceModel{
+		ID:          e.ID,
+		DisputeID:   e.DisputeID,
+		AuthorID:    e.AuthorID,
+		Note:        e.Note,
+		FileURL:     e.FileURL,
+		FileKey:     e.FileKey,
+		ContentType: e.ContentType,
+		CreatedAt:   e.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	e.ID = model.ID
+	e.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// ListEvidence retrieves a dispute's evidence, oldest first
+func (r *DisputeRepository) ListEvidence(ctx context.Context, disputeID uuid.UUID) ([]*dispute.Evidence, error) {
+	var models []DisputeEvidenceModel
+	err := r.db.WithContext(ctx).
+		Where("dispute_id = ?", disputeID).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	evidence := make([]*dispute.Evidence, len(models))
+	for i, m := range models {
+		evidence[i] = &dispute.Evidence{
+			ID:          m.ID,
+			DisputeID:   m.DisputeID,
+			AuthorID:    m.AuthorID,
+			Note:        m.Note,
+			FileURL:     m.FileURL,
+			FileKey:     m.FileKey,
+			ContentType: m.ContentType,
+			CreatedAt:   m.CreatedAt,
+		}
+	}
+	return evidence, nil
+}
+
+// AutoMigrateDispute runs migrations for dispute tables
+func AutoMigrateDispute(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&DisputeModel{},
+		&DisputeEvidenceModel{},
+	)
+}
+
+// Helper functions
+func toDisputeModel(d *dispute.Dispute) *DisputeModel {
+	model := &DisputeModel{
+		ID:              d.ID,
+		OrderID:         d.OrderID,
+		BuyerID:         d.BuyerID,
+		SellerID:        d.SellerID,
+		Reason:          string(d.Reason),
+		Description:     d.Description,
+		RequestedAmount: d.RequestedAmount,
+		Status:          string(d.Status),
+		SellerResponse:  d.SellerResponse,
+		OfferedAmount:   d.OfferedAmount,
+		RespondedAt:     d.RespondedAt,
+		DeadlineAt:      d.DeadlineAt,
+		EscalatedAt:     d.EscalatedAt,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+	if res := d.Resolution; res != nil {
+		resolvedBy := res.ResolvedBy
+		resolvedAt := res.ResolvedAt
+		model.Outcome = string(res.Outcome)
+		model.RefundAmount = res.RefundAmount
+		model.RefundID = res.RefundID
+		model.ResolvedBy = &resolvedBy
+		model.ResolutionNote = res.Note
+		model.ResolvedAt = &resolvedAt
+	}
+	return model
+}
+
+func toDisputeDomain(m *DisputeModel) *dispute.Dispute {
+	d := &dispute.Dispute{
+		ID:              m.ID,
+		OrderID:         m.OrderID,
+		BuyerID:         m.BuyerID,
+		SellerID:        m.SellerID,
+		Reason:          dispute.Reason(m.Reason),
+		Description:     m.Description,
+		RequestedAmount: m.RequestedAmount,
+		Status:          dispute.Status(m.Status),
+		SellerResponse:  m.SellerResponse,
+		OfferedAmount:   m.OfferedAmount,
+		RespondedAt:     m.RespondedAt,
+		DeadlineAt:      m.DeadlineAt,
+		EscalatedAt:     m.EscalatedAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+	if m.ResolvedAt != nil {
+		d.Resolution = &dispute.Resolution{
+			Outcome:      dispute.Outcome(m.Outcome),
+			RefundAmount: m.RefundAmount,
+			RefundID:     m.RefundID,
+			Note:         m.ResolutionNote,
+			ResolvedAt:   *m.ResolvedAt,
+		}
+		if m.ResolvedBy != nil {
+			d.Resolution.ResolvedBy = *m.ResolvedBy
+		}
+	}
+	return d
+}
+
+func toDisputeDomains(models []DisputeModel) []*dispute.Dispute {
+	disputes := make([]*dispute.Dispute, len(models))
+	for i := range models {
+		disputes[i] = toDisputeDomain(&models[i])
+	}
+	return disputes
+}
@@ -0,0 +1,365 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/flashsale"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FlashSaleModel represents the flash sale database model
+type FlashSaleModel struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	SellerID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	SalePrice    float64   `gorm:"type:decimal(12,2);not null"`
+	Quantity     int       `gorm:"not null"`
+	PerUserLimit *int
+	SoldCount    int       `gorm:"not null;default:0"`
+	StartsAt     time.Time `gorm:"not null;index:idx_flash_sales_window"`
+	EndsAt       time.Time `gorm:"not null;index:idx_flash_sales_window"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (FlashSaleModel) TableName() string {
+	return "flash_sales"
+}
+
+// FlashSaleClaimModel represents the flash sale claim database model
+type FlashSaleClaimModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SaleID    uuid.UUID `gorm:"type:uuid;not null;index:idx_flash_sale_claims_user"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_flash_sale_claims_user"`
+	OrderID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	Quantity  int       `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (FlashSaleClaimModel) TableName() string {
+	return "flash_sale_claims"
+}
+
+// FlashSaleRepository implements flashsale.Repository
+type FlashSaleRepository struct {
+	db *gorm.DB
+}
+
+// NewFlashSaleRepository creates a new flash sale repository
+func NewFlashSaleRepository(db *gorm.DB) *FlashSaleRepository {
+	return &FlashSaleRepository{db: db}
+}
+
+// Create creates a sale, failing with ErrSaleOverlaps if the product already
+// has a sale in an overlapping window
+func (r *FlashSaleRepository) Create(ctx context.Context, s *flashsale.Sale) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSaleOverlap(tx, s); err != nil {
+			return err
+		}
+
+		model := toFlashSaleModel(s)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		s.ID = model.ID
+		s.CreatedAt = model.CreatedAt
+		s.UpdatedAt = model.UpdatedAt
+		return nil
+	})
+}
+
+// Update updates a sale's price, quantities and window
+func (r *FlashSaleRepository) Update(ctx context.Context, s *flashsale.Sale) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSaleOverlap(tx, s); err != nil {
+			return err
+		}
+
+		result := tx.Model(&FlashSaleModel{}).
+			Where("id = ?", s.ID).
+			Select("sale_price", "quantity", "per_user_limit", "starts_at", "ends_at", "updated_at").
+			Updates(toFlashSaleModel(s))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return flashsale.ErrSaleNotFound
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a sale by ID
+func (r *FlashSaleRepository) GetByID(ctx context.Context, id uuid.UUID) (*flashsale.Sale, error) {
+	var model FlashSaleModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, flashsale.ErrSaleNotFound
+		}
+		return nil, err
+	}
+	return toFlashSaleDomain(&model), nil
+}
+
+// Delete deletes a sale
+func (r *FlashSaleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&FlashSaleModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return flashsale.ErrSaleNotFound
+	}
+	return nil
+}
+
+// ListBySeller retrieves a seller's sales, newest first
+func (r *FlashSaleRepository) ListBySeller(ctx context.Context, sellerID uuid.UUID) ([]*flashsale.Sale, error) {
+	var models []FlashSaleModel
+	err := r.db.WithContext(ctx).
+		Where("seller_id = ?", sellerID).
+		Order("starts_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toFlashSaleDomains(models), nil
+}
+
+// ListActive retrieves sales running at now that are not sold out, ending soonest first
+func (r *FlashSaleRepository) ListActive(ctx context.Context, now time.Time) ([]*flashsale.Sale, error) {
+	var models []FlashSaleModel
+	err := r.activeAt(r.db.WithContext(ctx), now).
+		Order("ends_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toFlashSaleDomains(models), nil
+}
+
+// ActiveForProducts returns the sale running at now for each of the products that has one
+func (r *FlashSaleRepository) ActiveForProducts(ctx context.Context, productIDs []uuid.UUID, now time.Time) (map[uuid.UUID]*flashsale.Sale, error) {
+	sales := make(map[uuid.UUID]*flashsale.Sale)
+	if len(productIDs) == 0 {
+		return sales, nil
+	}
+
+	var models []FlashSaleModel
+	err := r.activeAt(r.db.WithContext(ctx), now).
+		Where("product_id IN ?", productIDs).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range models {
+		sales[models[i].ProductID] = toFlashSaleDomain(&models[i])
+	}
+	return sales, nil
+}
+
+// Claim atomically records claims and adds them to the sales' sold counts,
+// failing with ErrSoldOut or ErrUserLimitReached instead of overselling
+func (r *FlashSaleRepository) Claim(ctx context.Context, claims []*flashsale.Claim) error {
+	if len(claims) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, claim := range claims {
+			var sale FlashSaleModel
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sale, "id = ?", claim.SaleID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return flashsale.ErrSaleNotFound
+				}
+				return err
+			}
+
+			if sale.SoldCount+claim.Quantity > sale.Quantity {
+				return flashsale.ErrSoldOut
+			}
+			if sale.PerUserLimit != nil {
+				var bought int64
+				err := tx.Model(&FlashSaleClaimModel{}).
+					Where("sale_id = ? AND user_id = ?", claim.SaleID, claim.UserID).
+					Select("COALESCE(SUM(quantity), 0)").
+					Scan(&bought).Error
+				if err != nil {
+					return err
+				}
+				if int(bought)+claim.Quantity > *sale.PerUserLimit {
+					return flashsale.ErrUserLimitReached
+				}
+			}
+
+			m := toFlashSaleClaimModel(claim)
+			if err := tx.Create(m).Error; err != nil {
+				return fmt.Errorf("failed to create flash sale claim: %w", err)
+			}
+			claim.ID = m.ID
+			claim.CreatedAt = m.CreatedAt
+
+			err := tx.Model(&FlashSaleModel{}).Where("id = ?", claim.SaleID).
+				Updates(map[string]interface{}{
+					"sold_count": gorm.Expr("sold_count + ?", claim.Quantity),
+					"updated_at": time.Now(),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReleaseOrder removes an order's claims and gives their units back,
+// returning the claims released
+func (r *FlashSaleRepository) ReleaseOrder(ctx context.Context, orderID uuid.UUID) ([]*flashsale.Claim, error) {
+	var released []*flashsale.Claim
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var models []FlashSaleClaimModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_id = ?", orderID).Find(&models).Error; err != nil {
+			return err
+		}
+
+		for i := range models {
+			if err := tx.Delete(&FlashSaleClaimModel{}, "id = ?", models[i].ID).Error; err != nil {
+				return err
+			}
+			err := tx.Model(&FlashSaleModel{}).Where("id = ?", models[i].SaleID).
+				Updates(map[string]interface{}{
+					"sold_count": gorm.Expr("GREATEST(sold_count - ?, 0)", models[i].Quantity),
+					"updated_at": time.Now(),
+				}).Error
+			if err != nil {
+				return err
+			}
+			released = append(released, toFlashSaleClaimDomain(&models[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// ClaimedByBuyer sums the units of a sale each buyer has claimed
+func (r *FlashSaleRepository) ClaimedByBuyer(ctx context.Context, saleID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		UserID   uuid.UUID
+		Quantity int
+	}
+	err := r.db.WithContext(ctx).Model(&FlashSaleClaimModel{}).
+		Select("user_id, SUM(quantity) AS quantity").
+		Where("sale_id = ?", saleID).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		claimed[row.UserID] = row.Quantity
+	}
+	return claimed, nil
+}
+
+// AutoMigrateFlashSale runs auto migration for flash sale models
+func AutoMigrateFlashSale(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&FlashSaleModel{},
+		&FlashSaleClaimModel{},
+	)
+}
+
+func (r *FlashSaleRepository) activeAt(db *gorm.DB, now time.Time) *gorm.DB {
+	return db.Where("starts_at <= ? AND ends_at > ? AND sold_count < quantity", now, now)
+}
+
+// checkSaleOverlap fails with ErrSaleOverlaps if another sale of the product
+// shares part of the window
+func checkSaleOverlap(tx *gorm.DB, s *flashsale.Sale) error {
+	var overlapping int64
+	err := tx.Model(&FlashSaleModel{}).
+		Where("product_id = ? AND id <> ? AND starts_at < ? AND ends_at > ?", s.ProductID, s.ID, s.EndsAt, s.StartsAt).
+		Count(&overlapping).Error
+	if err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return flashsale.ErrSaleOverlaps
+	}
+	return nil
+}
+
+// Helper functions
+func toFlashSaleModel(s *flashsale.Sale) *FlashSaleModel {
+	return &FlashSaleModel{
+		ID:           s.ID,
+		ProductID:    s.ProductID,
+		SellerID:     s.SellerID,
+		SalePrice:    s.SalePrice,
+		Quantity:     s.Quantity,
+		PerUserLimit: s.PerUserLimit,
+		SoldCount:    s.SoldCount,
+		StartsAt:     s.StartsAt,
+		EndsAt:       s.EndsAt,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func toFlashSaleDomain(m *FlashSaleModel) *flashsale.Sale {
+	return &flashsale.Sale{
+		ID:           m.ID,
+		ProductID:    m.ProductID,
+		SellerID:     m.SellerID,
+		SalePrice:    m.SalePrice,
+		Quantity:     m.Quantity,
+		PerUserLimit: m.PerUserLimit,
+		SoldCount:    m.SoldCount,
+		StartsAt:     m.StartsAt,
+		EndsAt:       m.EndsAt,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+func toFlashSaleDomains(models []FlashSaleModel) []*flashsale.Sale {
+	sales := make([]*flashsale.Sale, len(models))
+	for i := range models {
+		sales[i] = toFlashSaleDomain(&models[i])
+	}
+	return sales
+}
+
+func toFlashSaleClaimModel(c *flashsale.Claim) *FlashSaleClaimModel {
+	return &FlashSaleClaimModel{
+		ID:        c.ID,
+		SaleID:    c.SaleID,
+		UserID:    c.UserID,
+		OrderID:   c.OrderID,
+		Quantity:  c.Quantity,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func toFlashSaleClaimDomain(m *FlashSaleClaimModel) *flashsale.Claim {
+	return &flashsale.Claim{
+		ID:        m.ID,
+		SaleID:    m.SaleID,
+		UserID:    m.UserID,
+		OrderID:   m.OrderID,
+		Quantity:  m.Quantity,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/invoice"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvoiceModel represents the invoice database model
+type InvoiceModel struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Number       string          `gorm:"not null;uniqueIndex"`
+	SellerID     uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_invoices_seller_sequence"`
+	Sequence     int             `gorm:"not null;uniqueIndex:idx_invoices_seller_sequence"`
+	BuyerID      uuid.UUID       `gorm:"type:uuid;not null;index"`
+	OrderID      uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex"`
+	Seller       json.RawMessage `gorm:"type:jsonb"`
+	Buyer        json.RawMessage `gorm:"type:jsonb"`
+	Lines        json.RawMessage `gorm:"type:jsonb"`
+	Subtotal     float64         `gorm:"type:decimal(12,2);not null"`
+	Discount     float64         `gorm:"type:decimal(12,2);not null;default:0"`
+	ShippingCost float64         `gorm:"type:decimal(12,2);not null;default:0"`
+	TaxAmount    float64         `gorm:"type:decimal(12,2);not null;default:0"`
+	TaxInclusive bool            `gorm:"not null;default:false"`
+	Total        float64         `gorm:"type:decimal(12,2);not null"`
+	CouponCode   string
+	PaidAt       time.Time `gorm:"not null"`
+	IssuedAt     time.Time `gorm:"not null;index"`
+	DocumentKey  string
+}
+
+func (InvoiceModel) TableName() string {
+	return "invoices"
+}
+
+// InvoiceSequenceModel holds the last invoice number issued by each seller
+type InvoiceSequenceModel struct {
+	SellerID   uuid.UUID `gorm:"type:uuid;primary_key"`
+	LastNumber int       `gorm:"not null;default:0"`
+}
+
+func (InvoiceSequenceModel) TableName() string {
+	return "invoice_sequences"
+}
+
+// InvoiceRepository implements invoice.Repository
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// Create stores an invoice under the seller's next number, failing with
+// ErrInvoiceExists if the order is already invoiced. Numbers are taken in
+// the same transaction, so a failed insert leaves no gap.
+func (r *InvoiceRepository) Create(ctx context.Context, inv *invoice.Invoice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&InvoiceModel{}).Where("order_id = ?", inv.OrderID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return invoice.ErrInvoiceExists
+		}
+
+		var sequence int
+		err := tx.Raw(`
+		INSERT INTO invoice_sequences (seller_id, last_number) VALUES (?, 1)
+		ON CONFLICT (seller_id) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+		`, inv.Seller.ID).Scan(&sequence).Error
+		if err != nil {
+			return err
+		}
+		inv.AssignNumber(sequence)
+
+		model, err := toInvoiceModel(inv)
+		if err != nil {
+			return err
+		}
+		return tx.Create(model).Error
+	})
+}
+
+// GetByOrderID retrieves an order's invoice
+func (r *InvoiceRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) (*invoice.Invoice, error) {
+	var model InvoiceModel
+	if err := r.db.WithContext(ctx).First(&model, "order_id = ?", orderID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invoice.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return toInvoiceDomain(&model), nil
+}
+
+// ListBySeller retrieves a seller's invoices issued in [from, to), in number order
+func (r *InvoiceRepository) ListBySeller(ctx context.Context, sellerID uuid.UUID, from, to time.Time) ([]*invoice.Invoice, error) {
+	var models []InvoiceModel
+	err := r.db.WithContext(ctx).
+		Where("seller_id = ? AND issued_at >= ? AND issued_at < ?", sellerID, from, to).
+		Order("sequence ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*invoice.Invoice, len(models))
+	for i := range models {
+		invoices[i] = toInvoiceDomain(&models[i])
+	}
+	return invoices, nil
+}
+
+// SetDocument records where an invoice's rendered document is stored
+func (r *InvoiceRepository) SetDocument(ctx context.Context, id uuid.UUID, key string) error {
+	result := r.db.WithContext(ctx).Model(&InvoiceModel{}).
+		Where("id = ?", id).
+		Update("document_key", key)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return invoice.ErrInvoiceNotFound
+	}
+	return nil
+}
+
+// AutoMigrateInvoice runs auto migration for invoice models
+func AutoMigrateInvoice(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&InvoiceModel{},
+		&InvoiceSequenceModel{},
+	)
+}
+
+// Helper functions
+func toInvoiceModel(inv *invoice.Invoice) (*InvoiceModel, error) {
+	seller, err := json.Marshal(inv.Seller)
+	if err != nil {
+		return nil, err
+	}
+	buyer, err := json.Marshal(inv.Buyer)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := json.Marshal(inv.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvoiceModel{
+		ID:           inv.ID,
+		Number:       inv.Number,
+		SellerID:     inv.Seller.ID,
+		Sequence:     inv.Sequence,
+		BuyerID:      inv.Buyer.ID,
+		OrderID:      inv.OrderID,
+		Seller:       seller,
+		Buyer:        buyer,
+		Lines:        lines,
+		Subtotal:     inv.Subtotal,
+		Discount:     inv.Discount,
+		ShippingCost: inv.ShippingCost,
+		TaxAmount:    inv.TaxAmount,
+		TaxInclusive: inv.TaxInclusive,
+		Total:        inv.Total,
+		CouponCode:   inv.CouponCode,
+		PaidAt:       inv.PaidAt,
+		IssuedAt:     inv.IssuedAt,
+		DocumentKey:  inv.DocumentKey,
+	}, nil
+}
+
+func toInvoiceDomain(m *InvoiceModel) *invoice.Invoice {
+	inv := &invoice.Invoice{
+		ID:           m.ID,
+		Number:       m.Number,
+		Sequence:     m.Sequence,
+		OrderID:      m.OrderID,
+		Subtotal:     m.Subtotal,
+		Discount:     m.Discount,
+		ShippingCost: m.ShippingCost,
+		TaxAmount:    m.TaxAmount,
+		TaxInclusive: m.TaxInclusive,
+		Total:        m.Total,
+		CouponCode:   m.CouponCode,
+		PaidAt:       m.PaidAt,
+		IssuedAt:     m.IssuedAt,
+		DocumentKey:  m.DocumentKey,
+	}
+	json.Unmarshal(m.Seller, &inv.Seller)
+	json.Unmarshal(m.Buyer, &inv.Buyer)
+	json.Unmarshal(m.Lines, &inv.Lines)
+	inv.Seller.ID = m.SellerID
+	inv.Buyer.ID = m.BuyerID
+	return inv
+}
@@ -0,0 +1,516 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/ledger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LedgerAccountModel represents the ledger account database model.
+// Platform accounts use the nil UUID as owner so (type, owner) stays unique.
+type LedgerAccountModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Type      string    `gorm:"not null;uniqueIndex:idx_ledger_accounts_owner"`
+	OwnerID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_ledger_accounts_owner"`
+	Currency  string    `gorm:"not null;default:'USD'"`
+	Balance   int64     `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (LedgerAccountModel) TableName() string {
+	return "ledger_accounts"
+}
+
+// LedgerTransactionModel represents the ledger transaction database model
+type LedgerTransactionModel struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Kind        string     `gorm:"not null;uniqueIndex:idx_ledger_transactions_ref"`
+	Reference   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_ledger_transactions_ref"`
+	OrderID     *uuid.UUID `gorm:"type:uuid;index"`
+	Description string
+	CreatedAt   time.Time
+}
+
+func (LedgerTransactionModel) TableName() string {
+	return "ledger_transactions"
+}
+
+// LedgerEntryModel represents the ledger entry database model
+type LedgerEntryModel struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TransactionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	AccountID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Amount        int64     `gorm:"not null"`
+	CreatedAt     time.Time
+}
+
+func (LedgerEntryModel) TableName() string {
+	return "ledger_entries"
+}
+
+// PayoutModel represents the payout database model
+type PayoutModel struct {
+	ID                uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SellerID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	Amount            int64     `gorm:"not null"`
+	Currency          string    `gorm:"not null;default:'USD'"`
+	Status            string    `gorm:"not null;default:'pending';index"`
+	Provider          string
+	ProviderReference string
+	FailureReason     string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (PayoutModel) TableName() string {
+	return "payouts"
+}
+
+// FeeRuleModel represents the fee rule database model
+type FeeRuleModel struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CategoryID  *uuid.UUID `gorm:"type:uuid;index"`
+	SellerTier  string
+	PercentBps  int   `gorm:"not null;default:0"`
+	FixedAmount int64 `gorm:"not null;default:0"`
+	CreatedAt   time.Time
+}
+
+func (FeeRuleModel) TableName() string {
+	return "fee_rules"
+}
+
+// LedgerRepository implements ledger.Repository
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+// NewLedgerRepository creates a new ledger repository
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Post stores a balanced transaction and updates account balances atomically
+func (r *LedgerRepository) Post(ctx context.Context, t *ledger.Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.post(tx, t)
+	})
+}
+
+// GetTransaction retrieves a posted transaction by kind and reference
+func (r *LedgerRepository) GetTransaction(ctx context.Context, kind ledger.Kind, reference uuid.UUID) (*ledger.Transaction, error) {
+	var model LedgerTransactionModel
+	err := r.db.WithContext(ctx).
+		First(&model, "kind = ? AND reference = ?", string(kind), reference).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ledger.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+
+	var rows []struct {
+		LedgerEntryModel
+		Type    string
+		OwnerID uuid.UUID
+	}
+	err = r.db.WithContext(ctx).
+		Table("ledger_entries").
+		Select("ledger_entries.*, ledger_accounts.type, ledger_accounts.owner_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_entries.transaction_id = ?", model.ID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	t := toLedgerTransactionDomain(&model)
+	for _, row := range rows {
+		t.Entries = append(t.Entries, ledger.Entry{
+			ID:            row.ID,
+			TransactionID: row.TransactionID,
+			AccountID:     row.AccountID,
+			Account:       toAccountKey(row.Type, row.OwnerID),
+			Amount:        row.Amount,
+		})
+	}
+	return t, nil
+}
+
+// GetAccount retrieves an account by key
+func (r *LedgerRepository) GetAccount(ctx context.Context, key ledger.AccountKey) (*ledger.Account, error) {
+	var model LedgerAccountModel
+	err := r.db.WithContext(ctx).
+		First(&model, "type = ? AND owner_id = ?", string(key.Type), ownerOf(key)).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ledger.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return toLedgerAccountDomain(&model), nil
+}
+
+// OrderBalance sums the entries an order's transactions made on an account
+func (r *LedgerRepository) OrderBalance(ctx context.Context, orderID uuid.UUID, key ledger.AccountKey) (int64, error) {
+	var sum int64
+	err := r.db.WithContext(ctx).
+		Table("ledger_entries").
+		Select("COALESCE(SUM(ledger_entries.amount), 0)").
+		Joins("JOIN ledger_transactions ON ledger_transactions.id = ledger_entries.transaction_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_transactions.order_id = ? AND ledger_accounts.type = ? AND ledger_accounts.owner_id = ?",
+			orderID, string(key.Type), ownerOf(key)).
+		Scan(&sum).Error
+	return sum, err
+}
+
+// ListStatement retrieves the entries of an owner's accounts, newest first
+func (r *LedgerRepository) ListStatement(ctx context.Context, ownerID uuid.UUID, page, pageSize int) ([]*ledger.StatementLine, int64, error) {
+	query := r.db.WithContext(ctx).
+		Table("ledger_entries").
+		Joins("JOIN ledger_transactions ON ledger_transactions.id = ledger_entries.transaction_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = ledger_entries.account_id").
+		Where("ledger_accounts.owner_id = ?", ownerID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		EntryID     uuid.UUID
+		Kind        string
+		Reference   uuid.UUID
+		OrderID     *uuid.UUID
+		Description string
+		Type        string
+		Amount      int64
+		CreatedAt   time.Time
+	}
+	err := query.
+		Select("ledger_entries.id AS entry_id, ledger_transactions.kind, ledger_transactions.reference, " +
+			"ledger_transactions.order_id, ledger_transactions.description, ledger_accounts.type, " +
+			"ledger_entries.amount, ledger_entries.created_at").
+		Order("ledger_entries.created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lines := make([]*ledger.StatementLine, len(rows))
+	for i, row := range rows {
+		lines[i] = &ledger.StatementLine{
+			EntryID:     row.EntryID,
+			Kind:        ledger.Kind(row.Kind),
+			Reference:   row.Reference,
+			OrderID:     row.OrderID,
+			Description: row.Description,
+			Account:     ledger.AccountType(row.Type),
+			Amount:      row.Amount,
+			CreatedAt:   row.CreatedAt,
+		}
+	}
+	return lines, total, nil
+}
+
+// ListPayableAccounts retrieves seller available accounts with at least minBalance
+func (r *LedgerRepository) ListPayableAccounts(ctx context.Context, minBalance int64, limit int) ([]*ledger.Account, error) {
+	var models []LedgerAccountModel
+	err := r.db.WithContext(ctx).
+		Where("type = ? AND balance >= ? AND balance > 0", string(ledger.AccountSellerAvailable), minBalance).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*ledger.Account, len(models))
+	for i := range models {
+		accounts[i] = toLedgerAccountDomain(&models[i])
+	}
+	return accounts, nil
+}
+
+// CreatePayout stores a payout and posts its transaction after checking the seller's balance
+func (r *LedgerRepository) CreatePayout(ctx context.Context, p *ledger.Payout, t *ledger.Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		account, err := r.lockAccount(tx, ledger.SellerAccount(ledger.AccountSellerAvailable, p.SellerID))
+		if err != nil {
+			return err
+		}
+		if account.Balance < p.Amount {
+			return ledger.ErrInsufficientBalance
+		}
+
+		if err := tx.Create(toPayoutModel(p)).Error; err != nil {
+			return fmt.Errorf("failed to create payout: %w", err)
+		}
+		return r.post(tx, t)
+	})
+}
+
+// UpdatePayout updates a payout
+func (r *LedgerRepository) UpdatePayout(ctx context.Context, p *ledger.Payout) error {
+	return r.db.WithContext(ctx).Save(toPayoutModel(p)).Error
+}
+
+// ListPayouts retrieves a seller's payouts, newest first
+func (r *LedgerRepository) ListPayouts(ctx context.Context, sellerID uuid.UUID, page, pageSize int) ([]*ledger.Payout, int64, error) {
+	query := r.db.WithContext(ctx).Model(&PayoutModel{}).Where("seller_id = ?", sellerID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []PayoutModel
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	payouts := make([]*ledger.Payout, len(models))
+	for i := range models {
+		payouts[i] = toPayoutDomain(&models[i])
+	}
+	return payouts, total, nil
+}
+
+// ListFeeRules retrieves all fee rules
+func (r *LedgerRepository) ListFeeRules(ctx context.Context) ([]*ledger.FeeRule, error) {
+	var models []FeeRuleModel
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make([]*ledger.FeeRule, len(models))
+	for i := range models {
+		rules[i] = toFeeRuleDomain(&models[i])
+	}
+	return rules, nil
+}
+
+// CreateFeeRule creates a fee rule
+func (r *LedgerRepository) CreateFeeRule(ctx context.Context, rule *ledger.FeeRule) error {
+	model := toFeeRuleModel(rule)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	rule.ID = model.ID
+	rule.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// DeleteFeeRule deletes a fee rule
+func (r *LedgerRepository) DeleteFeeRule(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&FeeRuleModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ledger.ErrFeeRuleNotFound
+	}
+	return nil
+}
+
+// post writes a transaction inside an open database transaction
+func (r *LedgerRepository) post(tx *gorm.DB, t *ledger.Transaction) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	var existing int64
+	err := tx.Model(&LedgerTransactionModel{}).
+		Where("kind = ? AND reference = ?", string(t.Kind), t.Reference).
+		Count(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return ledger.ErrDuplicateTransaction
+	}
+
+	if err := tx.Create(toLedgerTransactionModel(t)).Error; err != nil {
+		return fmt.Errorf("failed to create ledger transaction: %w", err)
+	}
+
+	for i := range t.Entries {
+		e := &t.Entries[i]
+		account, err := r.lockAccount(tx, e.Account)
+		if err != nil {
+			return err
+		}
+		e.AccountID = account.ID
+
+		err = tx.Create(&LedgerEntryModel{
+			ID:            e.ID,
+			TransactionID: t.ID,
+			AccountID:     account.ID,
+			Amount:        e.Amount,
+			CreatedAt:     t.CreatedAt,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to create ledger entry: %w", err)
+		}
+
+		err = tx.Model(&LedgerAccountModel{}).
+			Where("id = ?", account.ID).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", e.Amount),
+				"updated_at": time.Now(),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update account balance: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// lockAccount creates the account if needed and locks its row
+func (r *LedgerRepository) lockAccount(tx *gorm.DB, key ledger.AccountKey) (*LedgerAccountModel, error) {
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&LedgerAccountModel{
+		ID:      uuid.New(),
+		Type:    string(key.Type),
+		OwnerID: ownerOf(key),
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ledger account: %w", err)
+	}
+
+	var model LedgerAccountModel
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&model, "type = ? AND owner_id = ?", string(key.Type), ownerOf(key)).Error
+	if err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// AutoMigrateLedger creates ledger tables
+func AutoMigrateLedger(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&LedgerAccountModel{},
+		&LedgerTransactionModel{},
+		&LedgerEntryModel{},
+		&PayoutModel{},
+		&FeeRuleModel{},
+	)
+}
+
+// Helper functions
+func ownerOf(key ledger.AccountKey) uuid.UUID {
+	if key.OwnerID == nil {
+		return uuid.Nil
+	}
+	return *key.OwnerID
+}
+
+func toAccountKey(accountType string, ownerID uuid.UUID) ledger.AccountKey {
+	key := ledger.AccountKey{Type: ledger.AccountType(accountType)}
+	if ownerID != uuid.Nil {
+		key.OwnerID = &ownerID
+	}
+	return key
+}
+
+func toLedgerAccountDomain(m *LedgerAccountModel) *ledger.Account {
+	key := toAccountKey(m.Type, m.OwnerID)
+	return &ledger.Account{
+		ID:        m.ID,
+		Type:      key.Type,
+		OwnerID:   key.OwnerID,
+		Currency:  m.Currency,
+		Balance:   m.Balance,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func toLedgerTransactionModel(t *ledger.Transaction) *LedgerTransactionModel {
+	return &LedgerTransactionModel{
+		ID:          t.ID,
+		Kind:        string(t.Kind),
+		Reference:   t.Reference,
+		OrderID:     t.OrderID,
+		Description: t.Description,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+func toLedgerTransactionDomain(m *LedgerTransactionModel) *ledger.Transaction {
+	return &ledger.Transaction{
+		ID:          m.ID,
+		Kind:        ledger.Kind(m.Kind),
+		Reference:   m.Reference,
+		OrderID:     m.OrderID,
+		Description: m.Description,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func toPayoutModel(p *ledger.Payout) *PayoutModel {
+	return &PayoutModel{
+		ID:                p.ID,
+		SellerID:          p.SellerID,
+		Amount:            p.Amount,
+		Currency:          p.Currency,
+		Status:            string(p.Status),
+		Provider:          p.Provider,
+		ProviderReference: p.ProviderReference,
+		FailureReason:     p.FailureReason,
+		CreatedAt:         p.CreatedAt,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
+
+func toPayoutDomain(m *PayoutModel) *ledger.Payout {
+	return &ledger.Payout{
+		ID:                m.ID,
+		SellerID:          m.SellerID,
+		Amount:            m.Amount,
+		Currency:          m.Currency,
+		Status:            ledger.PayoutStatus(m.Status),
+		Provider:          m.Provider,
+		ProviderReference: m.ProviderReference,
+		FailureReason:     m.FailureReason,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
+
+func toFeeRuleModel(r *ledger.FeeRule) *FeeRuleModel {
+	return &FeeRuleModel{
+		ID:          r.ID,
+		CategoryID:  r.CategoryID,
+		SellerTier:  r.SellerTier,
+		PercentBps:  r.PercentBps,
+		FixedAmount: r.FixedAmount,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+func toFeeRuleDomain(m *FeeRuleModel) *ledger.FeeRule {
+	return &ledger.FeeRule{
+		ID:          m.ID,
+		CategoryID:  m.CategoryID,
+		SellerTier:  m.SellerTier,
+		PercentBps:  m.PercentBps,
+		FixedAmount: m.FixedAmount,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -67,15 +67,19 @@ func (a StringArray) Value() (driver.Value, error) {
 
 type User struct {
 	BaseModel
-	Email         string    `gorm:"uniqueIndex;not null" json:"email"`
-	PasswordHash  string    `gorm:"not null" json:"-"`
-	Role          string    `gorm:"not null;default:'buyer'" json:"role"`
-	FirstName     string    `json:"first_name"`
-	LastName      string    `json:"last_name"`
-	AvatarURL     string    `json:"avatar_url"`
-	Phone         string    `json:"phone"`
-	EmailVerified bool      `gorm:"default:false" json:"email_verified"`
-	LastLoginAt   *time.Time `json:"last_login_at"`
+	Email            string     `gorm:"uniqueIndex;not null" json:"email"`
+	PasswordHash     string     `gorm:"not null" json:"-"`
+	Role             string     `gorm:"not null;default:'buyer'" json:"role"`
+	FirstName        string     `json:"first_name"`
+	LastName         string     `json:"last_name"`
+	AvatarURL        string     `json:"avatar_url"`
+	Phone            string     `json:"phone"`
+	EmailVerified    bool       `gorm:"default:false" json:"email_verified"`
+	SellerTier       string     `gorm:"not null;default:'standard'" json:"seller_tier"`
+	TOTPSecret       string     `json:"-"`
+	TwoFactorEnabled bool       `gorm:"default:false" json:"two_factor_enabled"`
+	TOTPLastStep     int64      `gorm:"default:0" json:"-"`
+	LastLoginAt      *time.Time `json:"last_login_at"`
 }
 
 type Category struct {
@@ -115,24 +119,28 @@ type Product struct {
 
 type Auction struct {
 	BaseModel
-	ProductID    uuid.UUID  `gorm:"not null;index" json:"product_id"`
-	SellerID     uuid.UUID  `gorm:"not null;index" json:"seller_id"`
-	Title        string     `gorm:"not null" json:"title"`
-	Description  string     `json:"description"`
-	StartTime    time.Time  `gorm:"not null" json:"start_time"`
-	EndTime      time.Time  `gorm:"not null" json:"end_time"`
-	Status       string     `gorm:"default:'scheduled'" json:"status"`
-	StartPrice   float64    `gorm:"not null" json:"start_price"`
-	ReservePrice *float64   `json:"reserve_price"`
-	BuyNowPrice  *float64   `json:"buy_now_price"`
-	CurrentBidID *uuid.UUID `gorm:"index" json:"-"`
-	BidCount     int        `gorm:"default:0" json:"bid_count"`
-	WinnerID     *uuid.UUID `gorm:"index" json:"winner_id"`
-	LiveKitRoom  string     `gorm:"not null;uniqueIndex" json:"livekit_room"`
-	StreamKey    string     `json:"stream_key"`
-	AutoExtend   bool       `gorm:"default:true" json:"auto_extend"`
-	ExtendTime   int        `gorm:"default:300" json:"extend_time"`
-	IsFeatured   bool       `gorm:"default:false" json:"is_featured"`
+	ProductID         uuid.UUID  `gorm:"not null;index" json:"product_id"`
+	SellerID          uuid.UUID  `gorm:"not null;index" json:"seller_id"`
+	Title             string     `gorm:"not null" json:"title"`
+	Description       string     `json:"description"`
+	StartTime         time.Time  `gorm:"not null" json:"start_time"`
+	EndTime           time.Time  `gorm:"not null" json:"end_time"`
+	Status            string     `gorm:"default:'scheduled'" json:"status"`
+	StartPrice        float64    `gorm:"not null" json:"start_price"`
+	ReservePrice      *float64   `json:"reserve_price"`
+	BuyNowPrice       *float64   `json:"buy_now_price"`
+	BidderRequirement string     `json:"bidder_requirement"`
+	DepositAmount     float64    `gorm:"default:0" json:"deposit_amount"`
+	CurrentBidID      *uuid.UUID `gorm:"index" json:"-"`
+	BidCount          int        `gorm:"default:0" json:"bid_count"`
+	WinnerID          *uuid.UUID `gorm:"index" json:"winner_id"`
+	LiveKitRoom       string     `gorm:"not null;uniqueIndex" json:"livekit_room"`
+	StreamKey         string     `json:"stream_key"`
+	AutoExtend        bool       `gorm:"default:true" json:"auto_extend"`
+	ExtendTime        int        `gorm:"default:300" json:"extend_time"`
+	IsFeatured        bool       `gorm:"default:false" json:"is_featured"`
+	RelistedFrom      *uuid.UUID `gorm:"uniqueIndex" json:"relisted_from"`
+	RelistedAs        *uuid.UUID `json:"relisted_as"`
 }
 
 type Bid struct {
@@ -159,18 +167,28 @@ type AutoBid struct {
 type Order struct {
 	BaseModel
 	UserID          uuid.UUID `gorm:"not null;index" json:"user_id"`
-	AuctionID       *uuid.UUID `gorm:"index" json:"auction_id"`
+	SellerID        uuid.UUID `gorm:"type:uuid;index" json:"seller_id"`
+	AuctionID       *uuid.UUID `gorm:"uniqueIndex:idx_orders_auction_unique" json:"auction_id"`
 	Status          string    `gorm:"default:'pending'" json:"status"`
 	TotalAmount     float64   `gorm:"not null" json:"total_amount"`
 	Subtotal        float64   `gorm:"not null" json:"subtotal"`
 	TaxAmount       float64   `gorm:"default:0" json:"tax_amount"`
+	TaxInclusive    bool      `gorm:"default:false" json:"tax_inclusive"`
 	ShippingCost    float64   `gorm:"default:0" json:"shipping_cost"`
 	DiscountAmount  float64   `gorm:"default:0" json:"discount_amount"`
+	CouponCode      string    `json:"coupon_code"`
 	ShippingAddress JSONMap   `gorm:"type:jsonb" json:"shipping_address"`
 	BillingAddress  JSONMap   `gorm:"type:jsonb" json:"billing_address"`
 	PaymentID       *uuid.UUID `gorm:"index" json:"payment_id"`
+	Carrier         string    `json:"carrier"`
 	TrackingNumber  string    `json:"tracking_number"`
 	Notes           string    `json:"notes"`
+	CancelReason    string    `json:"cancel_reason"`
+	PaymentDueAt    *time.Time `gorm:"index" json:"payment_due_at"`
+	PaidAt          *time.Time `json:"paid_at"`
+	ShippedAt       *time.Time `json:"shipped_at"`
+	DeliveredAt     *time.Time `json:"delivered_at"`
+	CancelledAt     *time.Time `json:"cancelled_at"`
 }
 
 type OrderItem struct {
@@ -180,12 +198,18 @@ type OrderItem struct {
 	Quantity  int       `gorm:"not null" json:"quantity"`
 	UnitPrice float64   `gorm:"not null" json:"unit_price"`
 	Total     float64   `gorm:"not null" json:"total"`
+
+	DiscountAmount  float64 `gorm:"default:0" json:"discount_amount"`
+	TaxJurisdiction string  `json:"tax_jurisdiction"`
+	TaxRateBps      int     `gorm:"default:0" json:"tax_rate_bps"`
+	TaxAmount       float64 `gorm:"default:0" json:"tax_amount"`
 }
 
 type Cart struct {
 	BaseModel
-	UserID    *uuid.UUID `gorm:"index" json:"user_id"`
+	UserID    *uuid.UUID `gorm:"uniqueIndex:idx_carts_user_unique" json:"user_id"`
 	Token     string     `gorm:"uniqueIndex;not null" json:"token"`
+	CouponCode string    `json:"coupon_code"`
 	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
 }
 
@@ -194,6 +218,7 @@ type CartItem struct {
 	CartID    uuid.UUID `gorm:"not null;index" json:"cart_id"`
 	ProductID uuid.UUID `gorm:"not null" json:"product_id"`
 	Quantity  int       `gorm:"not null" json:"quantity"`
+	UnitPrice float64   `gorm:"not null;default:0" json:"unit_price"`
 	AddedAt   time.Time `gorm:"autoCreateTime" json:"added_at"`
 }
 
@@ -204,9 +229,10 @@ type Payment struct {
 	Amount            float64    `gorm:"not null" json:"amount"`
 	Currency          string     `gorm:"default:'USD'" json:"currency"`
 	Status            string     `gorm:"default:'pending'" json:"status"`
+	Provider          string     `json:"provider"`
 	Method            string     `json:"method"`
 	TransactionID     string     `gorm:"uniqueIndex" json:"transaction_id"`
-	GatewayReference  string     `json:"gateway_reference"`
+	GatewayReference  string     `gorm:"index" json:"gateway_reference"`
 	FailureReason     string     `json:"failure_reason"`
 	RefundedAmount    float64    `gorm:"default:0" json:"refunded_amount"`
 	RefundedAt        *time.Time `json:"refunded_at"`
@@ -219,6 +245,7 @@ type PaymentMethod struct {
 	Type       string     `gorm:"not null" json:"type"`
 	Provider   string     `gorm:"not null" json:"provider"`
 	MethodRef  string     `gorm:"not null" json:"method_ref"`
+	CustomerRef string    `json:"customer_ref"`
 	IsDefault  bool       `gorm:"default:false" json:"is_default"`
 	Last4      string     `json:"last4"`
 	ExpiryDate *time.Time `json:"expiry_date"`
@@ -250,6 +277,54 @@ func AutoMigrate(db *gorm.DB) error {
 		return err
 	}
 	
+	if err := AutoMigrateInventory(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigratePayment(db); err != nil {
+		return err
+	}
+	
+	if err := CreatePaymentMethodIndexes(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateLedger(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateShipping(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigratePromotion(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateFlashSale(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateInvoice(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateDispute(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateStrike(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateDeposit(db); err != nil {
+		return err
+	}
+	
+	if err := AutoMigrateTwoFactor(db); err != nil {
+		return err
+	}
+	
 	// Seed default categories
 	if err := SeedCategories(db); err != nil {
 		return err
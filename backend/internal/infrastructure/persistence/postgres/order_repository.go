@@ -0,0 +1,346 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderRepository implements order.Repository
+type OrderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db *gorm.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// Create creates an order with its items
+func (r *OrderRepository) Create(ctx context.Context, o *order.Order) error {
+	model := toOrderModel(o)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		o.ID = model.ID
+		o.CreatedAt = model.CreatedAt
+		o.UpdatedAt = model.UpdatedAt
+
+		for i := range o.Items {
+			o.Items[i].OrderID = o.ID
+			item := toOrderItemModel(&o.Items[i])
+			if err := tx.Create(item).Error; err != nil {
+				return fmt.Errorf("failed to create order item: %w", err)
+			}
+			o.Items[i].ID = item.ID
+		}
+
+		return nil
+	})
+}
+
+// TransitionStatus saves an order's fields (items are immutable) if the
+// stored order is still in status from
+func (r *OrderRepository) TransitionStatus(ctx context.Context, o *order.Order, from order.Status) error {
+	return updateOrderFrom(r.db.WithContext(ctx), o, from)
+}
+
+// Reprice updates an order's fields and its items' tax breakdown
+func (r *OrderRepository) Reprice(ctx context.Context, o *order.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateOrderFrom(tx, o, o.Status); err != nil {
+			return err
+		}
+
+		for i := range o.Items {
+			item := &o.Items[i]
+			err := tx.Model(&OrderItem{}).
+				Where("id = ? AND order_id = ?", item.ID, o.ID).
+				Updates(map[string]interface{}{
+					"tax_jurisdiction": item.TaxJurisdiction,
+					"tax_rate_bps":     item.TaxRateBps,
+					"tax_amount":       item.TaxAmount,
+				}).Error
+			if err != nil {
+				return fmt.Errorf("failed to update order item: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetByID retrieves an order with its items
+func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*order.Order, error) {
+	var model Order
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return r.withItems(ctx, &model)
+}
+
+// GetByAuctionID retrieves the order created for an auction
+func (r *OrderRepository) GetByAuctionID(ctx context.Context, auctionID uuid.UUID) (*order.Order, error) {
+	var model Order
+	if err := r.db.WithContext(ctx).First(&model, "auction_id = ?", auctionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return r.withItems(ctx, &model)
+}
+
+// List retrieves orders with filtering
+func (r *OrderRepository) List(ctx context.Context, filter order.Filter) ([]*order.Order, int64, error) {
+	var models []Order
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&Order{})
+
+	if filter.BuyerID != nil {
+		query = query.Where("user_id = ?", *filter.BuyerID)
+	}
+	if filter.SellerID != nil {
+		query = query.Where("seller_id = ?", *filter.SellerID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", string(*filter.Status))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orders, err := r.withItemsBatch(ctx, models)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// ListOverdueAuctionOrders retrieves pending auction orders whose payment
+// deadline passed before the given time, oldest deadline first
+func (r *OrderRepository) ListOverdueAuctionOrders(ctx context.Context, before time.Time, limit int) ([]*order.Order, error) {
+	var models []Order
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND auction_id IS NOT NULL AND payment_due_at < ?", string(order.StatusPending), before).
+		Order("payment_due_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.withItemsBatch(ctx, models)
+}
+
+func (r *OrderRepository) withItems(ctx context.Context, model *Order) (*order.Order, error) {
+	var items []OrderItem
+	if err := r.db.WithContext(ctx).Where("order_id = ?", model.ID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	o := toOrderDomain(model)
+	for i := range items {
+		o.Items = append(o.Items, toOrderItemDomain(&items[i]))
+	}
+	return o, nil
+}
+
+func (r *OrderRepository) withItemsBatch(ctx context.Context, models []Order) ([]*order.Order, error) {
+	orders := make([]*order.Order, len(models))
+	ids := make([]uuid.UUID, len(models))
+	byID := make(map[uuid.UUID]*order.Order, len(models))
+	for i := range models {
+		orders[i] = toOrderDomain(&models[i])
+		ids[i] = models[i].ID
+		byID[models[i].ID] = orders[i]
+	}
+
+	if len(ids) > 0 {
+		var items []OrderItem
+		if err := r.db.WithContext(ctx).Where("order_id IN ?", ids).Find(&items).Error; err != nil {
+			return nil, err
+		}
+		for i := range items {
+			if o, ok := byID[items[i].OrderID]; ok {
+				o.Items = append(o.Items, toOrderItemDomain(&items[i]))
+			}
+		}
+	}
+	return orders, nil
+}
+
+// updateOrderFrom writes every mutable column of an order, guarded by its
+// stored status so a concurrent transition is never overwritten
+func updateOrderFrom(db *gorm.DB, o *order.Order, from order.Status) error {
+	result := db.Model(&Order{}).
+		Where("id = ? AND status = ?", o.ID, string(from)).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(toOrderModel(o))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update order: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return order.ErrStatusConflict
+	}
+	return nil
+}
+
+// Helper functions
+func toOrderModel(o *order.Order) *Order {
+	return &Order{
+		BaseModel: BaseModel{
+			ID:        o.ID,
+			CreatedAt: o.CreatedAt,
+			UpdatedAt: o.UpdatedAt,
+		},
+		UserID:          o.BuyerID,
+		SellerID:        o.SellerID,
+		AuctionID:       o.AuctionID,
+		Status:          string(o.Status),
+		TotalAmount:     o.TotalAmount,
+		Subtotal:        o.Subtotal,
+		TaxAmount:       o.TaxAmount,
+		TaxInclusive:    o.TaxInclusive,
+		ShippingCost:    o.ShippingCost,
+		DiscountAmount:  o.DiscountAmount,
+		CouponCode:      o.CouponCode,
+		ShippingAddress: addressToJSON(o.ShippingAddress),
+		BillingAddress:  addressToJSON(o.BillingAddress),
+		PaymentID:       o.PaymentID,
+		Carrier:         o.Carrier,
+		TrackingNumber:  o.TrackingNumber,
+		Notes:           o.Notes,
+		CancelReason:    o.CancelReason,
+		PaymentDueAt:    o.PaymentDueAt,
+		PaidAt:          o.PaidAt,
+		ShippedAt:       o.ShippedAt,
+		DeliveredAt:     o.DeliveredAt,
+		CancelledAt:     o.CancelledAt,
+	}
+}
+
+func toOrderDomain(m *Order) *order.Order {
+	return &order.Order{
+		ID:              m.ID,
+		BuyerID:         m.UserID,
+		SellerID:        m.SellerID,
+		AuctionID:       m.AuctionID,
+		Status:          order.Status(m.Status),
+		Subtotal:        m.Subtotal,
+		TaxAmount:       m.TaxAmount,
+		TaxInclusive:    m.TaxInclusive,
+		ShippingCost:    m.ShippingCost,
+		DiscountAmount:  m.DiscountAmount,
+		CouponCode:      m.CouponCode,
+		TotalAmount:     m.TotalAmount,
+		ShippingAddress: addressFromJSON(m.ShippingAddress),
+		BillingAddress:  addressFromJSON(m.BillingAddress),
+		PaymentID:       m.PaymentID,
+		Carrier:         m.Carrier,
+		TrackingNumber:  m.TrackingNumber,
+		Notes:           m.Notes,
+		CancelReason:    m.CancelReason,
+		PaymentDueAt:    m.PaymentDueAt,
+		PaidAt:          m.PaidAt,
+		ShippedAt:       m.ShippedAt,
+		DeliveredAt:     m.DeliveredAt,
+		CancelledAt:     m.CancelledAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
+
+func toOrderItemModel(i *order.Item) *OrderItem {
+	return &OrderItem{
+		BaseModel: BaseModel{
+			ID: i.ID,
+		},
+		OrderID:   i.OrderID,
+		ProductID: i.ProductID,
+		Quantity:  i.Quantity,
+		UnitPrice: i.UnitPrice,
+		Total:     i.Total,
+
+		DiscountAmount:  i.DiscountAmount,
+		TaxJurisdiction: i.TaxJurisdiction,
+		TaxRateBps:      i.TaxRateBps,
+		TaxAmount:       i.TaxAmount,
+	}
+}
+
+func toOrderItemDomain(m *OrderItem) order.Item {
+	return order.Item{
+		ID:        m.ID,
+		OrderID:   m.OrderID,
+		ProductID: m.ProductID,
+		Quantity:  m.Quantity,
+		UnitPrice: m.UnitPrice,
+		Total:     m.Total,
+
+		DiscountAmount:  m.DiscountAmount,
+		TaxJurisdiction: m.TaxJurisdiction,
+		TaxRateBps:      m.TaxRateBps,
+		TaxAmount:       m.TaxAmount,
+	}
+}
+
+func addressToJSON(a *order.Address) JSONMap {
+	if a == nil {
+		return nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil
+	}
+	var m JSONMap
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func addressFromJSON(m JSONMap) *order.Address {
+	if len(m) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	var a order.Address
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil
+	}
+	return &a
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/payment"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PaymentMethodRepository implements payment.MethodRepository
+type PaymentMethodRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentMethodRepository creates a new payment method repository
+func NewPaymentMethodRepository(db *gorm.DB) *PaymentMethodRepository {
+	return &PaymentMethodRepository{db: db}
+}
+
+// Create saves a method, clearing the user's other default if it is the default
+func (r *PaymentMethodRepository) Create(ctx context.Context, m *payment.Method) error {
+	model := toPaymentMethodModel(m)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if m.IsDefault {
+			if err := clearDefaultMethod(tx, m.UserID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		m.ID = model.ID
+		m.CreatedAt = model.CreatedAt
+		m.UpdatedAt = model.UpdatedAt
+		return nil
+	})
+}
+
+// GetByID retrieves a method by ID
+func (r *PaymentMethodRepository) GetByID(ctx context.Context, id uuid.UUID) (*payment.Method, error) {
+	var model PaymentMethod
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payment.ErrMethodNotFound
+		}
+		return nil, err
+	}
+	return toPaymentMethodDomain(&model), nil
+}
+
+// ListByUser retrieves a user's methods, default first then newest
+func (r *PaymentMethodRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*payment.Method, error) {
+	var models []PaymentMethod
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]*payment.Method, len(models))
+	for i := range models {
+		methods[i] = toPaymentMethodDomain(&models[i])
+	}
+	return methods, nil
+}
+
+// SetDefault makes a method the user's only default
+func (r *PaymentMethodRepository) SetDefault(ctx context.Context, userID, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultMethod(tx, userID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&PaymentMethod{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Updates(map[string]interface{}{"is_default": true, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return payment.ErrMethodNotFound
+		}
+		return nil
+	})
+}
+
+// Delete removes a method, promoting the newest remaining one if it was the default.
+// Methods are hard deleted so no token outlives its removal.
+func (r *PaymentMethodRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model PaymentMethod
+		if err := tx.First(&model, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return payment.ErrMethodNotFound
+			}
+			return err
+		}
+
+		if err := tx.Unscoped().Delete(&PaymentMethod{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if !model.IsDefault {
+			return nil
+		}
+
+		var next PaymentMethod
+		err := tx.Where("user_id = ?", model.UserID).Order("created_at DESC").First(&next).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		return tx.Model(&next).Updates(map[string]interface{}{"is_default": true, "updated_at": time.Now()}).Error
+	})
+}
+
+func clearDefaultMethod(tx *gorm.DB, userID uuid.UUID) error {
+	return tx.Model(&PaymentMethod{}).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		Updates(map[string]interface{}{"is_default": false, "updated_at": time.Now()}).Error
+}
+
+// CreatePaymentMethodIndexes enforces a single default method per user
+func CreatePaymentMethodIndexes(db *gorm.DB) error {
+	return db.Exec(`
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_methods_user_default
+	ON payment_methods (user_id) WHERE is_default;
+	`).Error
+}
+
+// Helper functions
+func toPaymentMethodModel(m *payment.Method) *PaymentMethod {
+	return &PaymentMethod{
+		BaseModel: BaseModel{
+			ID:        m.ID,
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+		},
+		UserID:      m.UserID,
+		Type:        m.Type,
+		Provider:    m.Provider,
+		MethodRef:   m.MethodRef,
+		CustomerRef: m.CustomerRef,
+		IsDefault:   m.IsDefault,
+		Last4:       m.Last4,
+		ExpiryDate:  m.ExpiresAt,
+	}
+}
+
+func toPaymentMethodDomain(m *PaymentMethod) *payment.Method {
+	return &payment.Method{
+		ID:          m.ID,
+		UserID:      m.UserID,
+		Type:        m.Type,
+		Provider:    m.Provider,
+		MethodRef:   m.MethodRef,
+		CustomerRef: m.CustomerRef,
+		Last4:       m.Last4,
+		ExpiresAt:   m.ExpiryDate,
+		IsDefault:   m.IsDefault,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
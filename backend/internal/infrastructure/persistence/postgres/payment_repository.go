@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/payment"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefundModel represents the refund database model
+type RefundModel struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PaymentID        uuid.UUID `gorm:"type:uuid;not null;index"`
+	OrderID          uuid.UUID `gorm:"type:uuid;not null;index"`
+	Amount           float64   `gorm:"not null"`
+	Reason           string
+	InitiatedBy      uuid.UUID `gorm:"type:uuid;not null"`
+	IdempotencyKey   string    `gorm:"uniqueIndex;not null"`
+	GatewayReference string
+	Status           string          `gorm:"not null;default:'succeeded'"`
+	Restocked        json.RawMessage `gorm:"type:jsonb;default:'[]'"`
+	CreatedAt        time.Time
+}
+
+func (RefundModel) TableName() string {
+	return "refunds"
+}
+
+// PaymentRepository implements payment.Repository
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new payment repository
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// Create creates a payment
+func (r *PaymentRepository) Create(ctx context.Context, p *payment.Payment) error {
+	model := toPaymentModel(p)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	p.ID = model.ID
+	p.CreatedAt = model.CreatedAt
+	p.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Update updates a payment
+func (r *PaymentRepository) Update(ctx context.Context, p *payment.Payment) error {
+	model := toPaymentModel(p)
+	return r.db.WithContext(ctx).Save(model).Error
+}
+
+// GetByID retrieves a payment by ID
+func (r *PaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*payment.Payment, error) {
+	return r.getBy(ctx, "id = ?", id)
+}
+
+// GetByTransactionID retrieves a payment by its idempotency key
+func (r *PaymentRepository) GetByTransactionID(ctx context.Context, transactionID string) (*payment.Payment, error) {
+	return r.getBy(ctx, "transaction_id = ?", transactionID)
+}
+
+// GetByReference retrieves a payment by its provider reference
+func (r *PaymentRepository) GetByReference(ctx context.Context, reference string) (*payment.Payment, error) {
+	return r.getBy(ctx, "gateway_reference = ?", reference)
+}
+
+// ListByOrder retrieves all payments of an order, newest first
+func (r *PaymentRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*payment.Payment, error) {
+	var models []Payment
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]*payment.Payment, len(models))
+	for i := range models {
+		payments[i] = toPaymentDomain(&models[i])
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) getBy(ctx context.Context, query string, arg interface{}) (*payment.Payment, error) {
+	var model Payment
+	if err := r.db.WithContext(ctx).First(&model, query, arg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payment.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return toPaymentDomain(&model), nil
+}
+
+// ReserveRefund records a pending refund and atomically adds its amount to the
+// payment's refunded total. It fails with payment.ErrRefundExceedsCaptured,
+// recording nothing, if a concurrent refund left less than the amount to refund.
+func (r *PaymentRepository) ReserveRefund(ctx context.Context, refund *payment.Refund) (*payment.Payment, error) {
+	model, err := toRefundModel(refund)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated Payment
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		// Amounts are compared in cents to avoid float rounding leaving a fraction unrefunded
+		result := tx.Model(&Payment{}).
+			Where("id = ? AND status IN ? AND ROUND((refunded_amount + ?) * 100) <= ROUND(amount * 100)",
+				refund.PaymentID, []string{string(payment.StatusCaptured), string(payment.StatusPartiallyRefunded)}, refund.Amount).
+			Updates(map[string]interface{}{
+				"refunded_amount": gorm.Expr("refunded_amount + ?", refund.Amount),
+				"refunded_at":     model.CreatedAt,
+				"status": gorm.Expr("CASE WHEN ROUND((refunded_amount + ?) * 100) >= ROUND(amount * 100) THEN ? ELSE ? END",
+					refund.Amount, string(payment.StatusRefunded), string(payment.StatusPartiallyRefunded)),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return payment.ErrRefundExceedsCaptured
+		}
+		return tx.First(&updated, "id = ?", refund.PaymentID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund.ID = model.ID
+	refund.CreatedAt = model.CreatedAt
+	return toPaymentDomain(&updated), nil
+}
+
+// CompleteRefund marks a reserved refund succeeded
+func (r *PaymentRepository) CompleteRefund(ctx context.Context, refund *payment.Refund) error {
+	return r.db.WithContext(ctx).Model(&RefundModel{}).
+		Where("id = ?", refund.ID).
+		Updates(map[string]interface{}{
+			"status":            string(refund.Status),
+			"gateway_reference": refund.GatewayReference,
+		}).Error
+}
+
+// ReleaseRefund deletes a pending refund and takes its amount back off the
+// payment's refunded total. Releasing a refund twice is a no-op.
+func (r *PaymentRepository) ReleaseRefund(ctx context.Context, refund *payment.Refund) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND status = ?", refund.ID, string(payment.RefundPending)).Delete(&RefundModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		return tx.Model(&Payment{}).
+			Where("id = ?", refund.PaymentID).
+			Updates(map[string]interface{}{
+				"refunded_amount": gorm.Expr("refunded_amount - ?", refund.Amount),
+				"refunded_at": gorm.Expr("CASE WHEN ROUND((refunded_amount - ?) * 100) <= 0 THEN NULL ELSE refunded_at END",
+					refund.Amount),
+				"status": gorm.Expr("CASE WHEN ROUND((refunded_amount - ?) * 100) <= 0 THEN ? ELSE ? END",
+					refund.Amount, string(payment.StatusCaptured), string(payment.StatusPartiallyRefunded)),
+			}).Error
+	})
+}
+
+// GetRefundByIdempotencyKey retrieves a refund by its idempotency key
+func (r *PaymentRepository) GetRefundByIdempotencyKey(ctx context.Context, key string) (*payment.Refund, error) {
+	var model RefundModel
+	if err := r.db.WithContext(ctx).First(&model, "idempotency_key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payment.ErrRefundNotFound
+		}
+		return nil, err
+	}
+	return toRefundDomain(&model), nil
+}
+
+// ListRefundsByOrder retrieves all refunds of an order, oldest first
+func (r *PaymentRepository) ListRefundsByOrder(ctx context.Context, orderID uuid.UUID) ([]*payment.Refund, error) {
+	var models []RefundModel
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*payment.Refund, len(models))
+	for i := range models {
+		refunds[i] = toRefundDomain(&models[i])
+	}
+	return refunds, nil
+}
+
+// AutoMigratePayment creates payment tables not covered by the legacy models
+func AutoMigratePayment(db *gorm.DB) error {
+	return db.AutoMigrate(&RefundModel{})
+}
+
+// Helper functions
+func toPaymentModel(p *payment.Payment) *Payment {
+	metadata := JSONMap{}
+	for k, v := range p.Metadata {
+		metadata[k] = v
+	}
+
+	return &Payment{
+		BaseModel: BaseModel{
+			ID:        p.ID,
+			CreatedAt: p.CreatedAt,
+			UpdatedAt: p.UpdatedAt,
+		},
+		OrderID:          p.OrderID,
+		UserID:           p.UserID,
+		Amount:           p.Amount,
+		Currency:         p.Currency,
+		Status:           string(p.Status),
+		Provider:         p.Provider,
+		Method:           p.Method,
+		TransactionID:    p.TransactionID,
+		GatewayReference: p.GatewayReference,
+		FailureReason:    p.FailureReason,
+		RefundedAmount:   p.RefundedAmount,
+		RefundedAt:       p.RefundedAt,
+		Metadata:         metadata,
+	}
+}
+
+func toPaymentDomain(m *Payment) *payment.Payment {
+	metadata := make(map[string]string, len(m.Metadata))
+	for k, v := range m.Metadata {
+		if s, ok := v.(string); ok {
+			metadata[k] = s
+		}
+	}
+
+	return &payment.Payment{
+		ID:               m.ID,
+		OrderID:          m.OrderID,
+		UserID:           m.UserID,
+		Amount:           m.Amount,
+		Currency:         m.Currency,
+		Status:           payment.Status(m.Status),
+		Provider:         m.Provider,
+		Method:           m.Method,
+		TransactionID:    m.TransactionID,
+		GatewayReference: m.GatewayReference,
+		FailureReason:    m.FailureReason,
+		RefundedAmount:   m.RefundedAmount,
+		RefundedAt:       m.RefundedAt,
+		Metadata:         metadata,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
+
+func toRefundModel(r *payment.Refund) (*RefundModel, error) {
+	restocked, err := json.Marshal(r.Restocked)
+	if err != nil {
+		return nil, err
+	}
+	if r.Restocked == nil {
+		restocked = []byte("[]")
+	}
+
+	return &RefundModel{
+		ID:               r.ID,
+		PaymentID:        r.PaymentID,
+		OrderID:          r.OrderID,
+		Amount:           r.Amount,
+		Reason:           r.Reason,
+		InitiatedBy:      r.InitiatedBy,
+		IdempotencyKey:   r.IdempotencyKey,
+		GatewayReference: r.GatewayReference,
+		Status:           string(r.Status),
+		Restocked:        restocked,
+		CreatedAt:        r.CreatedAt,
+	}, nil
+}
+
+func toRefundDomain(m *RefundModel) *payment.Refund {
+	var restocked []payment.RestockItem
+	if len(m.Restocked) > 0 {
+		json.Unmarshal(m.Restocked, &restocked)
+	}
+
+	return &payment.Refund{
+		ID:               m.ID,
+		PaymentID:        m.PaymentID,
+		OrderID:          m.OrderID,
+		Amount:           m.Amount,
+		Reason:           m.Reason,
+		InitiatedBy:      m.InitiatedBy,
+		IdempotencyKey:   m.IdempotencyKey,
+		GatewayReference: m.GatewayReference,
+		Status:           payment.RefundStatus(m.Status),
+		Restocked:        restocked,
+		CreatedAt:        m.CreatedAt,
+	}
+}
@@ -4,13 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/blytz/live/backend/internal/domain/product"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 // ProductModel represents the product database model
@@ -399,9 +397,7 @@ func (r *ProductRepository) SearchProducts(ctx context.Context, query string, pa
 	var models []ProductModel
 	var total int64
 	
-	// Use PostgreSQL full-text search
-	searchQuery := strings.Join(strings.Fields(query), " & ")
-	
+	// Use PostgreSQL full-text search; plainto_tsquery parses the raw query
 	sql := `
 		SELECT *, ts_rank(to_tsvector('english', name || ' ' || COALESCE(description, '')), 
 		plainto_tsquery('english', ?)) as rank
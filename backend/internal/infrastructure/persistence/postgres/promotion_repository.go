@@ -0,0 +1,375 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/promotion"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CouponModel represents the coupon database model
+type CouponModel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Code           string    `gorm:"not null;uniqueIndex"`
+	Description    string
+	SellerID       *uuid.UUID  `gorm:"type:uuid;index"`
+	Type           string      `gorm:"not null"`
+	PercentBps     int         `gorm:"not null;default:0"`
+	Amount         float64     `gorm:"type:decimal(12,2);not null;default:0"`
+	MaxDiscount    *float64    `gorm:"type:decimal(12,2)"`
+	MinOrderAmount float64     `gorm:"type:decimal(12,2);not null;default:0"`
+	ProductIDs     StringArray `gorm:"type:jsonb"`
+	CategoryIDs    StringArray `gorm:"type:jsonb"`
+	UsageLimit     *int
+	PerUserLimit   *int
+	UsedCount      int `gorm:"not null;default:0"`
+	StartsAt       *time.Time
+	ExpiresAt      *time.Time
+	Active         bool      `gorm:"not null;default:true"`
+	CreatedBy      uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (CouponModel) TableName() string {
+	return "coupons"
+}
+
+// CouponRedemptionModel represents the coupon redemption database model
+type CouponRedemptionModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	CouponID  uuid.UUID `gorm:"type:uuid;not null;index:idx_coupon_redemptions_user"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_coupon_redemptions_user"`
+	OrderID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	Reference uuid.UUID `gorm:"type:uuid;not null;index"`
+	Amount    float64   `gorm:"type:decimal(12,2);not null"`
+	CreatedAt time.Time
+}
+
+func (CouponRedemptionModel) TableName() string {
+	return "coupon_redemptions"
+}
+
+// PromotionRepository implements promotion.Repository
+type PromotionRepository struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository creates a new promotion repository
+func NewPromotionRepository(db *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+// Create creates a coupon, failing with ErrDuplicateCode if the code is taken
+func (r *PromotionRepository) Create(ctx context.Context, c *promotion.Coupon) error {
+	var existing int64
+	if err := r.db.WithContext(ctx).Model(&CouponModel{}).Where("code = ?", c.Code).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return promotion.ErrDuplicateCode
+	}
+
+	model := toCouponModel(c)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	c.ID = model.ID
+	c.CreatedAt = model.CreatedAt
+	c.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Update updates a coupon's settings (not its usage count)
+func (r *PromotionRepository) Update(ctx context.Context, c *promotion.Coupon) error {
+	model := toCouponModel(c)
+	result := r.db.WithContext(ctx).Model(&CouponModel{}).
+		Where("id = ?", c.ID).
+		Select("description", "type", "percent_bps", "amount", "max_discount", "min_order_amount",
+			"product_ids", "category_ids", "usage_limit", "per_user_limit", "starts_at", "expires_at",
+			"active", "updated_at").
+		Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return promotion.ErrCouponNotFound
+	}
+	return nil
+}
+
+// GetByID retrieves a coupon by ID
+func (r *PromotionRepository) GetByID(ctx context.Context, id uuid.UUID) (*promotion.Coupon, error) {
+	return r.get(r.db.WithContext(ctx), "id = ?", id)
+}
+
+// GetByCode retrieves a coupon by its normalized code
+func (r *PromotionRepository) GetByCode(ctx context.Context, code string) (*promotion.Coupon, error) {
+	return r.get(r.db.WithContext(ctx), "code = ?", code)
+}
+
+// List retrieves coupons with filtering
+func (r *PromotionRepository) List(ctx context.Context, filter promotion.Filter) ([]*promotion.Coupon, int64, error) {
+	query := r.db.WithContext(ctx).Model(&CouponModel{})
+	if filter.SellerID != nil {
+		query = query.Where("seller_id = ?", *filter.SellerID)
+	}
+	if filter.PlatformOnly {
+		query = query.Where("seller_id IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+
+	var models []CouponModel
+	err := query.Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	coupons := make([]*promotion.Coupon, len(models))
+	for i := range models {
+		coupons[i] = toCouponDomain(&models[i])
+	}
+	return coupons, total, nil
+}
+
+// Delete deletes a coupon
+func (r *PromotionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&CouponModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return promotion.ErrCouponNotFound
+	}
+	return nil
+}
+
+// CountUserUses counts the checkouts a user has used a coupon in
+func (r *PromotionRepository) CountUserUses(ctx context.Context, couponID, userID uuid.UUID) (int, error) {
+	return countUserUses(r.db.WithContext(ctx), couponID, userID)
+}
+
+// Redeem atomically records one use of a coupon across the given orders,
+// enforcing the global and per-user limits
+func (r *PromotionRepository) Redeem(ctx context.Context, c *promotion.Coupon, redemptions []*promotion.Redemption) error {
+	if len(redemptions) == 0 {
+		return nil
+	}
+	userID := redemptions[0].UserID
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model CouponModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, "id = ?", c.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return promotion.ErrCouponNotFound
+			}
+			return err
+		}
+
+		if model.UsageLimit != nil && model.UsedCount >= *model.UsageLimit {
+			return promotion.ErrUsageLimitReached
+		}
+		if model.PerUserLimit != nil {
+			uses, err := countUserUses(tx, c.ID, userID)
+			if err != nil {
+				return err
+			}
+			if uses >= *model.PerUserLimit {
+				return promotion.ErrUserLimitReached
+			}
+		}
+
+		for _, red := range redemptions {
+			m := toCouponRedemptionModel(red)
+			if err := tx.Create(m).Error; err != nil {
+				return fmt.Errorf("failed to create coupon redemption: %w", err)
+			}
+			red.ID = m.ID
+			red.CreatedAt = m.CreatedAt
+		}
+
+		err := tx.Model(&CouponModel{}).Where("id = ?", c.ID).
+			Updates(map[string]interface{}{
+				"used_count": gorm.Expr("used_count + 1"),
+				"updated_at": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		c.UsedCount = model.UsedCount + 1
+		return nil
+	})
+}
+
+// ReleaseOrder removes a cancelled order's redemption. The use is given back
+// once no order of the same checkout still holds it.
+func (r *PromotionRepository) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var red CouponRedemptionModel
+		if err := tx.First(&red, "order_id = ?", orderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		// Lock the coupon so concurrent releases of one checkout agree on the last one
+		var coupon CouponModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, "id = ?", red.CouponID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return tx.Delete(&CouponRedemptionModel{}, "id = ?", red.ID).Error
+			}
+			return err
+		}
+
+		if err := tx.Delete(&CouponRedemptionModel{}, "id = ?", red.ID).Error; err != nil {
+			return err
+		}
+
+		var remaining int64
+		err := tx.Model(&CouponRedemptionModel{}).Where("reference = ?", red.Reference).Count(&remaining).Error
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return nil
+		}
+
+		return tx.Model(&CouponModel{}).Where("id = ?", red.CouponID).
+			Updates(map[string]interface{}{
+				"used_count": gorm.Expr("GREATEST(used_count - 1, 0)"),
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+// AutoMigratePromotion runs auto migration for promotion models
+func AutoMigratePromotion(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&CouponModel{},
+		&CouponRedemptionModel{},
+	)
+}
+
+func (r *PromotionRepository) get(db *gorm.DB, query string, args ...interface{}) (*promotion.Coupon, error) {
+	var model CouponModel
+	if err := db.Where(query, args...).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, promotion.ErrCouponNotFound
+		}
+		return nil, err
+	}
+	return toCouponDomain(&model), nil
+}
+
+func countUserUses(db *gorm.DB, couponID, userID uuid.UUID) (int, error) {
+	var uses int64
+	err := db.Model(&CouponRedemptionModel{}).
+		Where("coupon_id = ? AND user_id = ?", couponID, userID).
+		Distinct("reference").
+		Count(&uses).Error
+	return int(uses), err
+}
+
+// Helper functions
+func toCouponModel(c *promotion.Coupon) *CouponModel {
+	return &CouponModel{
+		ID:             c.ID,
+		Code:           c.Code,
+		Description:    c.Description,
+		SellerID:       c.SellerID,
+		Type:           string(c.Type),
+		PercentBps:     c.PercentBps,
+		Amount:         c.Amount,
+		MaxDiscount:    c.MaxDiscount,
+		MinOrderAmount: c.MinOrderAmount,
+		ProductIDs:     uuidsToStrings(c.ProductIDs),
+		CategoryIDs:    uuidsToStrings(c.CategoryIDs),
+		UsageLimit:     c.UsageLimit,
+		PerUserLimit:   c.PerUserLimit,
+		UsedCount:      c.UsedCount,
+		StartsAt:       c.StartsAt,
+		ExpiresAt:      c.ExpiresAt,
+		Active:         c.Active,
+		CreatedBy:      c.CreatedBy,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+func toCouponDomain(m *CouponModel) *promotion.Coupon {
+	return &promotion.Coupon{
+		ID:             m.ID,
+		Code:           m.Code,
+		Description:    m.Description,
+		SellerID:       m.SellerID,
+		Type:           promotion.DiscountType(m.Type),
+		PercentBps:     m.PercentBps,
+		Amount:         m.Amount,
+		MaxDiscount:    m.MaxDiscount,
+		MinOrderAmount: m.MinOrderAmount,
+		ProductIDs:     stringsToUUIDs(m.ProductIDs),
+		CategoryIDs:    stringsToUUIDs(m.CategoryIDs),
+		UsageLimit:     m.UsageLimit,
+		PerUserLimit:   m.PerUserLimit,
+		UsedCount:      m.UsedCount,
+		StartsAt:       m.StartsAt,
+		ExpiresAt:      m.ExpiresAt,
+		Active:         m.Active,
+		CreatedBy:      m.CreatedBy,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func toCouponRedemptionModel(r *promotion.Redemption) *CouponRedemptionModel {
+	return &CouponRedemptionModel{
+		ID:        r.ID,
+		CouponID:  r.CouponID,
+		UserID:    r.UserID,
+		OrderID:   r.OrderID,
+		Reference: r.Reference,
+		Amount:    r.Amount,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func uuidsToStrings(ids []uuid.UUID) StringArray {
+	if len(ids) == 0 {
+		return StringArray{}
+	}
+	out := make(StringArray, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
+
+func stringsToUUIDs(ss StringArray) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(ss))
+	for _, s := range ss {
+		if id, err := uuid.Parse(s); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
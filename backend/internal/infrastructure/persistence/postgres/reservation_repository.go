@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/inventory"
+	"github.com/blytz/live/backend/internal/domain/product"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReservationModel represents the stock reservation database model
+type ReservationModel struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	OrderID   *uuid.UUID `gorm:"type:uuid;index"`
+	AuctionID *uuid.UUID `gorm:"type:uuid;index"`
+	Quantity  int        `gorm:"not null"`
+	Status    string     `gorm:"not null;default:'active';index"`
+	Reason    string
+	ExpiresAt *time.Time `gorm:"index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (ReservationModel) TableName() string {
+	return "stock_reservations"
+}
+
+// ReservationRepository implements inventory.Repository
+type ReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewReservationRepository creates a new reservation repository
+func NewReservationRepository(db *gorm.DB) *ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+// Reserve deducts stock with a conditional update and stores the reservation
+func (r *ReservationRepository) Reserve(ctx context.Context, res *inventory.Reservation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&ProductModel{}).
+			Where("id = ? AND status = ? AND stock_quantity >= ?", res.ProductID, string(product.StatusActive), res.Quantity).
+			UpdateColumn("stock_quantity", gorm.Expr("stock_quantity - ?", res.Quantity))
+		if result.Error != nil {
+			return fmt.Errorf("failed to deduct stock: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return inventory.ErrInsufficientStock
+		}
+
+		model := toReservationModel(res)
+		if err := tx.Create(model).Error; err != nil {
+			return fmt.Errorf("failed to create reservation: %w", err)
+		}
+		res.ID = model.ID
+		res.CreatedAt = model.CreatedAt
+		res.UpdatedAt = model.UpdatedAt
+		return nil
+	})
+}
+
+// Commit marks an active reservation as sold, marking the product sold once no stock is left
+func (r *ReservationRepository) Commit(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		model, err := r.lockActive(tx, id)
+		if err != nil || model == nil {
+			return err
+		}
+
+		if err := tx.Model(model).Updates(map[string]interface{}{
+			"status":     string(inventory.StatusCommitted),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&ProductModel{}).
+			Where("id = ? AND stock_quantity = 0 AND status = ?", model.ProductID, string(product.StatusActive)).
+			Update("status", string(product.StatusSold)).Error
+	})
+}
+
+// Release returns an active reservation's units to available stock
+func (r *ReservationRepository) Release(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		model, err := r.lockActive(tx, id)
+		if err != nil || model == nil {
+			return err
+		}
+
+		if err := tx.Model(model).Updates(map[string]interface{}{
+			"status":     string(inventory.StatusReleased),
+			"reason":     reason,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return restock(tx, model.ProductID, model.Quantity)
+	})
+}
+
+// Assign moves an active reservation to an order with a new expiry
+func (r *ReservationRepository) Assign(ctx context.Context, id uuid.UUID, orderID uuid.UUID, expiresAt *time.Time) error {
+	result := r.db.WithContext(ctx).Model(&ReservationModel{}).
+		Where("id = ? AND status = ?", id, string(inventory.StatusActive)).
+		Updates(map[string]interface{}{
+			"order_id":   orderID,
+			"expires_at": expiresAt,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return inventory.ErrReservationNotFound
+	}
+	return nil
+}
+
+// ListActiveByOrder retrieves the active reservations of an order
+func (r *ReservationRepository) ListActiveByOrder(ctx context.Context, orderID uuid.UUID) ([]*inventory.Reservation, error) {
+	var models []ReservationModel
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status = ?", orderID, string(inventory.StatusActive)).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toReservationDomains(models), nil
+}
+
+// GetActiveByAuction retrieves the active reservation of an auction
+func (r *ReservationRepository) GetActiveByAuction(ctx context.Context, auctionID uuid.UUID) (*inventory.Reservation, error) {
+	var model ReservationModel
+	err := r.db.WithContext(ctx).
+		Where("auction_id = ? AND status = ?", auctionID, string(inventory.StatusActive)).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, inventory.ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return toReservationDomain(&model), nil
+}
+
+// ListExpired retrieves active reservations that expired before the given time
+func (r *ReservationRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*inventory.Reservation, error) {
+	var models []ReservationModel
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at < ?", string(inventory.StatusActive), before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toReservationDomains(models), nil
+}
+
+// Restock returns sold units of a product to available stock
+func (r *ReservationRepository) Restock(ctx context.Context, productID uuid.UUID, quantity int) error {
+	return restock(r.db.WithContext(ctx), productID, quantity)
+}
+
+// restock adds units back to a product, making a sold-out product available again
+func restock(tx *gorm.DB, productID uuid.UUID, quantity int) error {
+	return tx.Model(&ProductModel{}).
+		Where("id = ?", productID).
+		Updates(map[string]interface{}{
+			"stock_quantity": gorm.Expr("stock_quantity + ?", quantity),
+			"status": gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END",
+				string(product.StatusSold), string(product.StatusActive)),
+		}).Error
+}
+
+// lockActive locks a reservation row; it returns nil if the reservation is no longer active
+func (r *ReservationRepository) lockActive(tx *gorm.DB, id uuid.UUID) (*ReservationModel, error) {
+	var model ReservationModel
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, inventory.ErrReservationNotFound
+		}
+		return nil, err
+	}
+	if model.Status != string(inventory.StatusActive) {
+		return nil, nil
+	}
+	return &model, nil
+}
+
+// AutoMigrateInventory creates inventory tables
+func AutoMigrateInventory(db *gorm.DB) error {
+	return db.AutoMigrate(&ReservationModel{})
+}
+
+// Helper functions
+func toReservationModel(r *inventory.Reservation) *ReservationModel {
+	return &ReservationModel{
+		ID:        r.ID,
+		ProductID: r.ProductID,
+		UserID:    r.UserID,
+		OrderID:   r.OrderID,
+		AuctionID: r.AuctionID,
+		Quantity:  r.Quantity,
+		Status:    string(r.Status),
+		Reason:    r.Reason,
+		ExpiresAt: r.ExpiresAt,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func toReservationDomain(m *ReservationModel) *inventory.Reservation {
+	return &inventory.Reservation{
+		ID:        m.ID,
+		ProductID: m.ProductID,
+		UserID:    m.UserID,
+		OrderID:   m.OrderID,
+		AuctionID: m.AuctionID,
+		Quantity:  m.Quantity,
+		Status:    inventory.Status(m.Status),
+		Reason:    m.Reason,
+		ExpiresAt: m.ExpiresAt,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func toReservationDomains(models []ReservationModel) []*inventory.Reservation {
+	reservations := make([]*inventory.Reservation, len(models))
+	for i := range models {
+		reservations[i] = toReservationDomain(&models[i])
+	}
+	return reservations
+}
@@ -0,0 +1,364 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/blytz/live/backend/internal/domain/shipping"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShippingProfileModel represents the shipping profile database model
+type ShippingProfileModel struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SellerID       uuid.UUID       `gorm:"type:uuid;not null;index"`
+	Name           string          `gorm:"not null"`
+	Countries      StringArray     `gorm:"type:jsonb"`
+	RateType       string          `gorm:"not null"`
+	FlatRate       float64         `gorm:"not null;default:0"`
+	Tiers          json.RawMessage `gorm:"type:jsonb"`
+	FreeOverAmount *float64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (ShippingProfileModel) TableName() string {
+	return "shipping_profiles"
+}
+
+// AddressModel represents the address book database model
+type AddressModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Label      string
+	Name       string `gorm:"not null"`
+	Line1      string `gorm:"not null"`
+	Line2      string
+	City       string `gorm:"not null"`
+	State      string
+	PostalCode string `gorm:"not null"`
+	Country    string `gorm:"size:2;not null"`
+	Phone      string
+	IsDefault  bool `gorm:"not null;default:false"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (AddressModel) TableName() string {
+	return "addresses"
+}
+
+// ShippingProfileRepository implements shipping.ProfileRepository
+type ShippingProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewShippingProfileRepository creates a new shipping profile repository
+func NewShippingProfileRepository(db *gorm.DB) *ShippingProfileRepository {
+	return &ShippingProfileRepository{db: db}
+}
+
+// Create creates a profile
+func (r *ShippingProfileRepository) Create(ctx context.Context, p *shipping.Profile) error {
+	model, err := toShippingProfileModel(p)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	p.ID = model.ID
+	p.CreatedAt = model.CreatedAt
+	p.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+// Update updates a profile
+func (r *ShippingProfileRepository) Update(ctx context.Context, p *shipping.Profile) error {
+	model, err := toShippingProfileModel(p)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Save(model).Error
+}
+
+// GetByID retrieves a profile by ID
+func (r *ShippingProfileRepository) GetByID(ctx context.Context, id uuid.UUID) (*shipping.Profile, error) {
+	var model ShippingProfileModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shipping.ErrProfileNotFound
+		}
+		return nil, err
+	}
+	return toShippingProfileDomain(&model), nil
+}
+
+// ListBySeller retrieves a seller's profiles, oldest first
+func (r *ShippingProfileRepository) ListBySeller(ctx context.Context, sellerID uuid.UUID) ([]*shipping.Profile, error) {
+	var models []ShippingProfileModel
+	err := r.db.WithContext(ctx).
+		Where("seller_id = ?", sellerID).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]*shipping.Profile, len(models))
+	for i := range models {
+		profiles[i] = toShippingProfileDomain(&models[i])
+	}
+	return profiles, nil
+}
+
+// Delete deletes a profile
+func (r *ShippingProfileRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&ShippingProfileModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shipping.ErrProfileNotFound
+	}
+	return nil
+}
+
+// AddressRepository implements shipping.AddressRepository
+type AddressRepository struct {
+	db *gorm.DB
+}
+
+// NewAddressRepository creates a new address book repository
+func NewAddressRepository(db *gorm.DB) *AddressRepository {
+	return &AddressRepository{db: db}
+}
+
+// Create saves an address, clearing the user's other default if it is the default
+func (r *AddressRepository) Create(ctx context.Context, a *shipping.SavedAddress) error {
+	model := toAddressModel(a)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if a.IsDefault {
+			if err := clearDefaultAddress(tx, a.UserID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		a.ID = model.ID
+		a.CreatedAt = model.CreatedAt
+		a.UpdatedAt = model.UpdatedAt
+		return nil
+	})
+}
+
+// Update updates an address's label and fields
+func (r *AddressRepository) Update(ctx context.Context, a *shipping.SavedAddress) error {
+	result := r.db.WithContext(ctx).Model(&AddressModel{}).
+		Where("id = ?", a.ID).
+		Updates(map[string]interface{}{
+			"label":       a.Label,
+			"name":        a.Address.Name,
+			"line1":       a.Address.Line1,
+			"line2":       a.Address.Line2,
+			"city":        a.Address.City,
+			"state":       a.Address.State,
+			"postal_code": a.Address.PostalCode,
+			"country":     a.Address.Country,
+			"phone":       a.Address.Phone,
+			"updated_at":  a.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shipping.ErrAddressNotFound
+	}
+	return nil
+}
+
+// GetByID retrieves an address by ID
+func (r *AddressRepository) GetByID(ctx context.Context, id uuid.UUID) (*shipping.SavedAddress, error) {
+	var model AddressModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, shipping.ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return toAddressDomain(&model), nil
+}
+
+// ListByUser retrieves a user's addresses, default first then newest
+func (r *AddressRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*shipping.SavedAddress, error) {
+	var models []AddressModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*shipping.SavedAddress, len(models))
+	for i := range models {
+		addresses[i] = toAddressDomain(&models[i])
+	}
+	return addresses, nil
+}
+
+// SetDefault makes an address the user's only default
+func (r *AddressRepository) SetDefault(ctx context.Context, userID, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultAddress(tx, userID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&AddressModel{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Updates(map[string]interface{}{"is_default": true, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return shipping.ErrAddressNotFound
+		}
+		return nil
+	})
+}
+
+// Delete removes an address, promoting the newest remaining one if it was the default
+func (r *AddressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model AddressModel
+		if err := tx.First(&model, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return shipping.ErrAddressNotFound
+			}
+			return err
+		}
+
+		if err := tx.Delete(&AddressModel{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if !model.IsDefault {
+			return nil
+		}
+
+		var next AddressModel
+		err := tx.Where("user_id = ?", model.UserID).Order("created_at DESC").First(&next).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		return tx.Model(&next).Updates(map[string]interface{}{"is_default": true, "updated_at": time.Now()}).Error
+	})
+}
+
+func clearDefaultAddress(tx *gorm.DB, userID uuid.UUID) error {
+	return tx.Model(&AddressModel{}).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		Updates(map[string]interface{}{"is_default": false, "updated_at": time.Now()}).Error
+}
+
+// AutoMigrateShipping runs auto migration for shipping models
+func AutoMigrateShipping(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&ShippingProfileModel{},
+		&AddressModel{},
+	); err != nil {
+		return err
+	}
+
+	// Enforce a single default address per user
+	return db.Exec(`
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_user_default
+	ON addresses (user_id) WHERE is_default;
+	`).Error
+}
+
+// Helper functions
+func toShippingProfileModel(p *shipping.Profile) (*ShippingProfileModel, error) {
+	tiers, err := json.Marshal(p.Tiers)
+	if err != nil {
+		return nil, err
+	}
+	return &ShippingProfileModel{
+		ID:             p.ID,
+		SellerID:       p.SellerID,
+		Name:           p.Name,
+		Countries:      StringArray(p.Countries),
+		RateType:       string(p.RateType),
+		FlatRate:       p.FlatRate,
+		Tiers:          tiers,
+		FreeOverAmount: p.FreeOverAmount,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}, nil
+}
+
+func toShippingProfileDomain(m *ShippingProfileModel) *shipping.Profile {
+	p := &shipping.Profile{
+		ID:             m.ID,
+		SellerID:       m.SellerID,
+		Name:           m.Name,
+		Countries:      []string(m.Countries),
+		RateType:       shipping.RateType(m.RateType),
+		FlatRate:       m.FlatRate,
+		FreeOverAmount: m.FreeOverAmount,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+	if len(m.Tiers) > 0 && string(m.Tiers) != "null" {
+		json.Unmarshal(m.Tiers, &p.Tiers)
+	}
+	return p
+}
+
+func toAddressModel(a *shipping.SavedAddress) *AddressModel {
+	return &AddressModel{
+		ID:         a.ID,
+		UserID:     a.UserID,
+		Label:      a.Label,
+		Name:       a.Address.Name,
+		Line1:      a.Address.Line1,
+		Line2:      a.Address.Line2,
+		City:       a.Address.City,
+		State:      a.Address.State,
+		PostalCode: a.Address.PostalCode,
+		Country:    a.Address.Country,
+		Phone:      a.Address.Phone,
+		IsDefault:  a.IsDefault,
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+	}
+}
+
+func toAddressDomain(m *AddressModel) *shipping.SavedAddress {
+	return &shipping.SavedAddress{
+		ID:     m.ID,
+		UserID: m.UserID,
+		Label:  m.Label,
+		Address: order.Address{
+			Name:       m.Name,
+			Line1:      m.Line1,
+			Line2:      m.Line2,
+			City:       m.City,
+			State:      m.State,
+			PostalCode: m.PostalCode,
+			Country:    m.Country,
+			Phone:      m.Phone,
+		},
+		IsDefault: m.IsDefault,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
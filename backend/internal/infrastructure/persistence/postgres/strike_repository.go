@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserStrikeModel represents the user strike database model
+type UserStrikeModel struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_user_strikes_user_created"`
+	Reason    string     `gorm:"not null"`
+	OrderID   *uuid.UUID `gorm:"type:uuid;uniqueIndex"`
+	AuctionID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time  `gorm:"index:idx_user_strikes_user_created"`
+}
+
+func (UserStrikeModel) TableName() string {
+	return "user_strikes"
+}
+
+// StrikeRepository implements user.StrikeRepository
+type StrikeRepository struct {
+	db *gorm.DB
+}
+
+// NewStrikeRepository creates a new strike repository
+func NewStrikeRepository(db *gorm.DB) *StrikeRepository {
+	return &StrikeRepository{db: db}
+}
+
+// Create records a strike. Recording a second strike for the same order is a no-op.
+func (r *StrikeRepository) Create(ctx context.Context, s *user.Strike) error {
+	model := &UserStrikeModel{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		Reason:    string(s.Reason),
+		OrderID:   s.OrderID,
+		AuctionID: s.AuctionID,
+		CreatedAt: s.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(model).Error
+}
+
+// CountSince counts a user's strikes recorded at or after since
+func (r *StrikeRepository) CountSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&UserStrikeModel{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+// AutoMigrateStrike runs migrations for the strike table
+func AutoMigrateStrike(db *gorm.DB) error {
+	return db.AutoMigrate(&UserStrikeModel{})
+}
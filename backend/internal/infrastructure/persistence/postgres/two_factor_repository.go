@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecoveryCodeModel represents the recovery code database model
+type RecoveryCodeModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash  string    `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (RecoveryCodeModel) TableName() string {
+	return "user_recovery_codes"
+}
+
+// TwoFactorPolicyModel represents a role's two-factor requirement
+type TwoFactorPolicyModel struct {
+	Role      string     `gorm:"primary_key"`
+	Required  bool       `gorm:"not null;default:false"`
+	UpdatedBy *uuid.UUID `gorm:"type:uuid"`
+	UpdatedAt time.Time
+}
+
+func (TwoFactorPolicyModel) TableName() string {
+	return "two_factor_policies"
+}
+
+// RecoveryCodeRepository implements user.RecoveryCodeRepository
+type RecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeRepository creates a new recovery code repository
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: db}
+}
+
+// Replace discards a user's recovery codes and saves new ones
+func (r *RecoveryCodeRepository) Replace(ctx context.Context, userID uuid.UUID, codes []*user.RecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+
+		models := make([]*RecoveryCodeModel, len(codes))
+		for i, c := range codes {
+			models[i] = &RecoveryCodeModel{
+				ID:        c.ID,
+				UserID:    userID,
+				CodeHash:  c.CodeHash,
+				UsedAt:    c.UsedAt,
+				CreatedAt: c.CreatedAt,
+			}
+		}
+		return tx.Create(&models).Error
+	})
+}
+
+// ListUnused retrieves a user's recovery codes that have not been used
+func (r *RecoveryCodeRepository) ListUnused(ctx context.Context, userID uuid.UUID) ([]*user.RecoveryCode, error) {
+	var models []RecoveryCodeModel
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND used_at IS NULL", userID).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]*user.RecoveryCode, len(models))
+	for i, m := range models {
+		codes[i] = &user.RecoveryCode{
+			ID:        m.ID,
+			UserID:    m.UserID,
+			CodeHash:  m.CodeHash,
+			UsedAt:    m.UsedAt,
+			CreatedAt: m.CreatedAt,
+		}
+	}
+	return codes, nil
+}
+
+// MarkUsed marks a recovery code as used. The update only matches an unused
+// code, so a code cannot be spent twice concurrently.
+func (r *RecoveryCodeRepository) MarkUsed(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&RecoveryCodeModel{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnused counts a user's remaining recovery codes
+func (r *RecoveryCodeRepository) CountUnused(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&RecoveryCodeModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return int(count), err
+}
+
+// DeleteByUserID discards all of a user's recovery codes
+func (r *RecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error
+}
+
+// TwoFactorPolicyRepository implements user.TwoFactorPolicyRepository
+type TwoFactorPolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorPolicyRepository creates a new two-factor policy repository
+func NewTwoFactorPolicyRepository(db *gorm.DB) *TwoFactorPolicyRepository {
+	return &TwoFactorPolicyRepository{db: db}
+}
+
+// RequiredRoles lists the roles that must use two-factor authentication
+func (r *TwoFactorPolicyRepository) RequiredRoles(ctx context.Context) ([]user.Role, error) {
+	var models []TwoFactorPolicyModel
+	if err := r.db.WithContext(ctx).Where("required = ?", true).Order("role").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	roles := make([]user.Role, len(models))
+	for i, m := range models {
+		roles[i] = user.Role(m.Role)
+	}
+	return roles, nil
+}
+
+// SetRequired records whether role must use two-factor authentication
+func (r *TwoFactorPolicyRepository) SetRequired(ctx context.Context, role user.Role, required bool, updatedBy uuid.UUID) error {
+	model := &TwoFactorPolicyModel{
+		Role:      string(role),
+		Required:  required,
+		UpdatedBy: &updatedBy,
+		UpdatedAt: time.Now(),
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}},
+		DoUpdates: clause.AssignmentColumns([]string{"required", "updated_by", "updated_at"}),
+	}).Create(model).Error
+}
+
+// AutoMigrateTwoFactor runs migrations for recovery codes and two-factor policies
+func AutoMigrateTwoFactor(db *gorm.DB) error {
+	return db.AutoMigrate(&RecoveryCodeModel{}, &TwoFactorPolicyModel{})
+}
@@ -70,6 +70,18 @@ func (r *UserRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 	return count > 0, nil
 }
 
+// AdvanceTOTPStep records step as the user's last used TOTP step if it is later
+// than the stored one
+func (r *UserRepository) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // Helper functions
 func toUserModel(u *user.User) *User {
 	return &User{
@@ -78,31 +90,39 @@ func toUserModel(u *user.User) *User {
 			CreatedAt: u.CreatedAt,
 			UpdatedAt: u.UpdatedAt,
 		},
-		Email:         u.Email,
-		PasswordHash:  u.PasswordHash,
-		Role:          string(u.Role),
-		FirstName:     u.FirstName,
-		LastName:      u.LastName,
-		AvatarURL:     u.AvatarURL,
-		Phone:         u.Phone,
-		EmailVerified: u.EmailVerified,
-		LastLoginAt:   u.LastLoginAt,
+		Email:            u.Email,
+		PasswordHash:     u.PasswordHash,
+		Role:             string(u.Role),
+		FirstName:        u.FirstName,
+		LastName:         u.LastName,
+		AvatarURL:        u.AvatarURL,
+		Phone:            u.Phone,
+		EmailVerified:    u.EmailVerified,
+		SellerTier:       u.SellerTier,
+		TOTPSecret:       u.TOTPSecret,
+		TwoFactorEnabled: u.TwoFactorEnabled,
+		TOTPLastStep:     u.TOTPLastStep,
+		LastLoginAt:      u.LastLoginAt,
 	}
 }
 
 func toUserDomain(m *User) *user.User {
 	return &user.User{
-		ID:            m.ID,
-		Email:         m.Email,
-		PasswordHash:  m.PasswordHash,
-		Role:          user.Role(m.Role),
-		FirstName:     m.FirstName,
-		LastName:      m.LastName,
-		AvatarURL:     m.AvatarURL,
-		Phone:         m.Phone,
-		EmailVerified: m.EmailVerified,
-		LastLoginAt:   m.LastLoginAt,
-		CreatedAt:     m.CreatedAt,
-		UpdatedAt:     m.UpdatedAt,
+		ID:               m.ID,
+		Email:            m.Email,
+		PasswordHash:     m.PasswordHash,
+		Role:             user.Role(m.Role),
+		FirstName:        m.FirstName,
+		LastName:         m.LastName,
+		AvatarURL:        m.AvatarURL,
+		Phone:            m.Phone,
+		EmailVerified:    m.EmailVerified,
+		SellerTier:       m.SellerTier,
+		TOTPSecret:       m.TOTPSecret,
+		TwoFactorEnabled: m.TwoFactorEnabled,
+		TOTPLastStep:     m.TOTPLastStep,
+		LastLoginAt:      m.LastLoginAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
 	}
 }
\ No newline at end of file
@@ -197,7 +197,7 @@ func (c *Client) ListObjects(ctx context.Context, prefix string, maxKeys int32)
 	result, err := c.s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
 		Bucket:  aws.String(c.bucket),
 		Prefix:  aws.String(prefix),
-		MaxKeys: maxKeys,
+		MaxKeys: &maxKeys,
 	})
 
 	if err != nil {
@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+// File is the on-disk tax rate table
+type File struct {
+	Jurisdictions []Jurisdiction `json:"jurisdictions"`
+}
+
+// Jurisdiction is a taxing region: a whole country, or one state of it.
+// State-level entries take precedence over the country entry.
+type Jurisdiction struct {
+	Name             string         `json:"name"`
+	Country          string         `json:"country"`
+	State            string         `json:"state,omitempty"`
+	RateBps          int            `json:"rate_bps"`
+	PricesIncludeTax bool           `json:"prices_include_tax"`
+	CategoryRates    []CategoryRate `json:"category_rates,omitempty"`
+}
+
+// CategoryRate overrides a jurisdiction's rate for one product category
+type CategoryRate struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	RateBps    int       `json:"rate_bps"`
+}
+
+// Calculator is an order.TaxCalculator backed by a static rate table
+type Calculator struct {
+	jurisdictions map[string]*Jurisdiction // keyed by COUNTRY or COUNTRY/STATE
+}
+
+// NewCalculator creates a calculator from a rate table
+func NewCalculator(f *File) (*Calculator, error) {
+	c := &Calculator{jurisdictions: make(map[string]*Jurisdiction)}
+	for i := range f.Jurisdictions {
+		j := &f.Jurisdictions[i]
+		if j.Country == "" {
+			return nil, fmt.Errorf("jurisdiction %q has no country", j.Name)
+		}
+		if j.RateBps < 0 || j.RateBps > 10000 {
+			return nil, fmt.Errorf("jurisdiction %q has invalid rate %d", j.Name, j.RateBps)
+		}
+		for _, cr := range j.CategoryRates {
+			if cr.RateBps < 0 || cr.RateBps > 10000 {
+				return nil, fmt.Errorf("jurisdiction %q has invalid rate %d for category %s", j.Name, cr.RateBps, cr.CategoryID)
+			}
+		}
+
+		key := jurisdictionKey(j.Country, j.State)
+		if _, ok := c.jurisdictions[key]; ok {
+			return nil, fmt.Errorf("duplicate jurisdiction %s", key)
+		}
+		c.jurisdictions[key] = j
+	}
+	return c, nil
+}
+
+// LoadFile creates a calculator from a JSON rate table on disk
+func LoadFile(path string) (*Calculator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse tax rates %s: %w", path, err)
+	}
+	return NewCalculator(&f)
+}
+
+// Calculate quotes tax for each line at the destination's rate
+func (c *Calculator) Calculate(ctx context.Context, req order.TaxRequest) (*order.TaxQuote, error) {
+	quote := &order.TaxQuote{Lines: make([]order.LineTax, len(req.Lines))}
+
+	j := c.match(req.Destination)
+	if j == nil {
+		return quote, nil
+	}
+
+	quote.PricesIncludeTax = j.PricesIncludeTax
+	for i, line := range req.Lines {
+		rate := j.rateFor(line.CategoryID)
+		quote.Lines[i] = order.LineTax{
+			Jurisdiction: j.Name,
+			RateBps:      rate,
+			Amount:       lineTax(line.Amount, rate, j.PricesIncludeTax),
+		}
+	}
+	return quote, nil
+}
+
+// match finds the most specific jurisdiction for a destination
+func (c *Calculator) match(dest *order.Address) *Jurisdiction {
+	if dest == nil || dest.Country == "" {
+		return nil
+	}
+	if dest.State != "" {
+		if j, ok := c.jurisdictions[jurisdictionKey(dest.Country, dest.State)]; ok {
+			return j
+		}
+	}
+	return c.jurisdictions[jurisdictionKey(dest.Country, "")]
+}
+
+func (j *Jurisdiction) rateFor(categoryID *uuid.UUID) int {
+	if categoryID != nil {
+		for _, cr := range j.CategoryRates {
+			if cr.CategoryID == *categoryID {
+				return cr.RateBps
+			}
+		}
+	}
+	return j.RateBps
+}
+
+// lineTax computes the tax on a line rounded to the cent. For tax-inclusive
+// prices the tax is the portion of the amount above its net price.
+func lineTax(amount float64, rateBps int, inclusive bool) float64 {
+	if rateBps == 0 || amount <= 0 {
+		return 0
+	}
+
+	rate := float64(rateBps) / 10000
+	var tax float64
+	if inclusive {
+		tax = amount - amount/(1+rate)
+	} else {
+		tax = amount * rate
+	}
+	return math.Round(tax*100) / 100
+}
+
+func jurisdictionKey(country, state string) string {
+	key := strings.ToUpper(strings.TrimSpace(country))
+	if state = strings.ToUpper(strings.TrimSpace(state)); state != "" {
+		key += "/" + state
+	}
+	return key
+}
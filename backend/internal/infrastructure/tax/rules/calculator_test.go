@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blytz/live/backend/internal/domain/order"
+	"github.com/google/uuid"
+)
+
+func TestLineTax(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    float64
+		rateBps   int
+		inclusive bool
+		want      float64
+	}{
+		{name: "exclusive", amount: 100, rateBps: 825, want: 8.25},
+		{name: "exclusive rounds to the cent", amount: 19.99, rateBps: 725, want: 1.45},
+		{name: "exclusive rounds half up", amount: 10.10, rateBps: 500, want: 0.51},
+		{name: "inclusive", amount: 120, rateBps: 2000, inclusive: true, want: 20},
+		{name: "inclusive rounds to the cent", amount: 9.99, rateBps: 1900, inclusive: true, want: 1.60},
+		{name: "zero rate", amount: 100, rateBps: 0, want: 0},
+		{name: "zero amount", amount: 0, rateBps: 2000, want: 0},
+		{name: "negative amount", amount: -5, rateBps: 2000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineTax(tt.amount, tt.rateBps, tt.inclusive); got != tt.want {
+				t.Errorf("lineTax(%v, %d, %v) = %v, want %v", tt.amount, tt.rateBps, tt.inclusive, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatorCalculate(t *testing.T) {
+	books := uuid.New()
+	c, err := NewCalculator(&File{Jurisdictions: []Jurisdiction{
+		{Name: "US", Country: "US", RateBps: 0},
+		{Name: "California", Country: "US", State: "CA", RateBps: 725},
+		{Name: "Germany", Country: "DE", RateBps: 1900, PricesIncludeTax: true,
+			CategoryRates: []CategoryRate{{CategoryID: books, RateBps: 700}}},
+	}})
+	if err != nil {
+		t.Fatalf("NewCalculator: %v", err)
+	}
+
+	lines := []order.TaxLine{
+		{ProductID: uuid.New(), Amount: 100},
+		{ProductID: uuid.New(), CategoryID: &books, Amount: 10.70},
+	}
+
+	tests := []struct {
+		name          string
+		destination   *order.Address
+		wantInclusive bool
+		want          []order.LineTax
+	}{
+		{
+			name:        "state rate beats country rate",
+			destination: &order.Address{Country: "us", State: "ca"},
+			want:        []order.LineTax{{Jurisdiction: "California", RateBps: 725, Amount: 7.25}, {Jurisdiction: "California", RateBps: 725, Amount: 0.78}},
+		},
+		{
+			name:        "country rate for unlisted state",
+			destination: &order.Address{Country: "US", State: "OR"},
+			want:        []order.LineTax{{Jurisdiction: "US"}, {Jurisdiction: "US"}},
+		},
+		{
+			name:          "inclusive prices with a category rate",
+			destination:   &order.Address{Country: "DE"},
+			wantInclusive: true,
+			want:          []order.LineTax{{Jurisdiction: "Germany", RateBps: 1900, Amount: 15.97}, {Jurisdiction: "Germany", RateBps: 700, Amount: 0.70}},
+		},
+		{
+			name:        "unknown country",
+			destination: &order.Address{Country: "FR"},
+			want:        []order.LineTax{{}, {}},
+		},
+		{
+			name: "no destination",
+			want: []order.LineTax{{}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := c.Calculate(context.Background(), order.TaxRequest{Destination: tt.destination, Lines: lines})
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if q.PricesIncludeTax != tt.wantInclusive {
+				t.Errorf("PricesIncludeTax = %v, want %v", q.PricesIncludeTax, tt.wantInclusive)
+			}
+			if len(q.Lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d", len(q.Lines), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if q.Lines[i] != want {
+					t.Errorf("line %d = %+v, want %+v", i, q.Lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCalculatorRejectsInvalidTables(t *testing.T) {
+	tests := []struct {
+		name string
+		file File
+	}{
+		{name: "missing country", file: File{Jurisdictions: []Jurisdiction{{Name: "Nowhere", RateBps: 500}}}},
+		{name: "negative rate", file: File{Jurisdictions: []Jurisdiction{{Name: "US", Country: "US", RateBps: -1}}}},
+		{name: "rate over 100%", file: File{Jurisdictions: []Jurisdiction{{Name: "US", Country: "US", RateBps: 10001}}}},
+		{name: "invalid category rate", file: File{Jurisdictions: []Jurisdiction{{Name: "US", Country: "US",
+			CategoryRates: []CategoryRate{{CategoryID: uuid.New(), RateBps: 20000}}}}}},
+		{name: "duplicate jurisdiction", file: File{Jurisdictions: []Jurisdiction{
+			{Name: "California", Country: "US", State: "CA"},
+			{Name: "California again", Country: "us", State: "ca"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCalculator(&tt.file); err == nil {
+				t.Error("NewCalculator() succeeded, want error")
+			}
+		})
+	}
+}
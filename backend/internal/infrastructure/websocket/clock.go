@@ -0,0 +1,174 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/blytz/live/backend/internal/domain/auction"
+	"github.com/google/uuid"
+)
+
+const (
+	// countdownWindow is how close to its end a live room starts receiving ticks
+	countdownWindow = time.Minute
+	// tickInterval is the spacing of countdown ticks
+	tickInterval = time.Second
+)
+
+// pingData is the payload of a client clock-sync ping
+type pingData struct {
+	ClientTime int64 `json:"client_time"` // unix milliseconds on the client clock
+}
+
+// runTicks broadcasts server-authoritative countdown ticks
+func (h *Hub) runTicks(ctx context.Context) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.broadcastTicks(now)
+		}
+	}
+}
+
+// broadcastTicks sends a tick to every live room in its final minute,
+// including one last tick at zero
+func (h *Hub) broadcastTicks(now time.Time) {
+	h.mu.RLock()
+	rooms := make([]*Room, 0, len(h.rooms))
+	for _, room := range h.rooms {
+		rooms = append(rooms, room)
+	}
+	h.mu.RUnlock()
+	
+	for _, room := range rooms {
+		status, endTime := room.countdown()
+		if status != auction.StatusLive || endTime.IsZero() {
+			continue
+		}
+		remaining := endTime.Sub(now)
+		if remaining > countdownWindow || remaining <= -tickInterval {
+			continue
+		}
+		
+		data, err := json.Marshal(Message{
+			Type:      "tick",
+			AuctionID: room.AuctionID,
+			Data:      clockData(now, status, endTime),
+			Timestamp: now,
+		})
+		if err != nil {
+			continue
+		}
+		room.broadcast(outbound{data: data})
+	}
+}
+
+// serverTimeMessage tells a newly connected client the server clock and countdown
+func (h *Hub) serverTimeMessage(room *Room, now time.Time) Message {
+	status, endTime := room.countdown()
+	return Message{
+		Type:      "server_time",
+		AuctionID: room.AuctionID,
+		Data:      clockData(now, status, endTime),
+		Timestamp: now,
+	}
+}
+
+// loadRoomState fills a room's countdown state from the cached auction state
+func (h *Hub) loadRoomState(ctx context.Context, room *Room) {
+	if h.cache == nil || room.hasState() {
+		return
+	}
+	
+	auctionID, err := uuid.Parse(room.AuctionID)
+	if err != nil {
+		return
+	}
+	
+	state, err := h.cache.GetAuctionState(ctx, auctionID)
+	if err != nil {
+		return
+	}
+	room.setState(state.Status, state.EndTime)
+}
+
+// handlePing answers a clock-sync ping. The client estimates its offset as
+// server_time - (client_time + receive time) / 2.
+func (c *Client) handlePing(raw json.RawMessage, received time.Time) {
+	var ping pingData
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &ping); err != nil {
+			return
+		}
+	}
+	
+	c.sendDirect(Message{
+		Type:      "pong",
+		AuctionID: c.auctionID,
+		Data: map[string]interface{}{
+			"client_time": ping.ClientTime,
+			"server_time": received.UnixMilli(),
+		},
+		Timestamp: received,
+	})
+}
+
+// clockData builds the clock payload shared by server_time and tick messages.
+// All times are unix milliseconds.
+func clockData(now time.Time, status auction.Status, endTime time.Time) map[string]interface{} {
+	data := map[string]interface{}{
+		"server_time": now.UnixMilli(),
+	}
+	if status != "" {
+		data["status"] = status
+	}
+	if !endTime.IsZero() {
+		remaining := endTime.Sub(now)
+		if remaining < 0 {
+			remaining = 0
+		}
+		data["end_time"] = endTime.UnixMilli()
+		data["remaining_ms"] = remaining.Milliseconds()
+	}
+	return data
+}
+
+// Room countdown methods
+
+func (r *Room) hasState() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.stateLoaded
+}
+
+func (r *Room) setState(status auction.Status, endTime time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stateLoaded = true
+	r.status = status
+	r.endTime = endTime
+}
+
+func (r *Room) setStatus(status auction.Status) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *Room) setEndTime(endTime time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endTime = endTime
+}
+
+func (r *Room) countdown() (auction.Status, time.Time) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status, r.endTime
+}
@@ -3,16 +3,49 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/blytz/live/backend/internal/domain/auction"
 	redisMessaging "github.com/blytz/live/backend/internal/infrastructure/messaging/redis"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	// presenceInterval is how often local viewers are flushed to Redis and
+	// the cluster-wide count is re-read; it also throttles viewer_count broadcasts
+	presenceInterval = 2 * time.Second
+	// presenceHeartbeat is how often an unchanged room's presence is refreshed
+	presenceHeartbeat = 10 * time.Second
+	// presenceTTL is how long an instance's viewers survive without a heartbeat
+	presenceTTL = 30 * time.Second
+
+	// sendBufferSize is the per-client outgoing queue length
+	sendBufferSize = 256
+	// maxBatchSize caps how many queued messages are coalesced into one frame
+	maxBatchSize = 32
+	// maxLag is how long a client may keep overflowing its queue before it is disconnected
+	maxLag = 15 * time.Second
+	// maxResyncs within resyncWindow disconnects a client that never catches up
+	maxResyncs   = 5
+	resyncWindow = time.Minute
+
+	// maxMessageSize is the largest message accepted from a client
+	maxMessageSize = 4096
+)
+
+var (
+	newline = []byte{'\n'}
+
+	errSendClosed = errors.New("send channel closed")
+)
+
 // Hub manages WebSocket connections across instances using Redis Pub/Sub
 type Hub struct {
 	// Local connections
@@ -24,30 +57,76 @@ type Hub struct {
 	eventBus    *redisMessaging.EventBus
 	subscription *redisMessaging.Subscription
 	
+	// Cluster-wide presence
+	cache      auction.Cache
+	instanceID string
+	
+	reactions *reactionAggregator
+	metrics   Metrics
+	
 	// WebSocket upgrader
 	upgrader websocket.Upgrader
 }
 
 // Room represents an auction room with connected clients
 type Room struct {
-	AuctionID   string
-	clients     map[*Client]bool
-	mu          sync.RWMutex
-	viewerCount int
+	AuctionID string
+	clients   map[*Client]bool
+	users     map[string]int // user_id -> local connection count
+	anonymous int
+	mu        sync.RWMutex
+	
+	// Presence bookkeeping
+	dirty        bool
+	lastFlush    time.Time // presence loop only
+	lastPresence auction.Presence
+	
+	// Countdown state, loaded from the auction cache and kept current by events
+	stateLoaded bool
+	status      auction.Status
+	endTime     time.Time
 }
 
-// Client represents a WebSocket client
+// Client represents a subscriber to a room, connected over WebSocket or,
+// when conn is nil, over Server-Sent Events
 type Client struct {
 	hub       *Hub
 	conn      *websocket.Conn
-	send      chan []byte
+	send      chan outbound
 	room      *Room
 	userID    string
 	auctionID string
+	
+	// Backpressure: resync is signalled when a message had to be dropped,
+	// lagSince holds the unix nanos of the first unrecovered drop
+	resync    chan struct{}
+	lagSince  atomic.Int64
+	resyncs   []time.Time // writer only
+	closeOnce sync.Once
+	done      chan struct{} // closed when the client is disconnected for lagging
+	
+	// Reaction rate limiting, reader only
+	reactionWindow time.Time
+	reactionCount  int
 }
 
-// Message represents a WebSocket message
+// outbound is a serialized message queued for a client
+type outbound struct {
+	id    string // event history ID, empty for ephemeral messages
+	data  []byte
+	lossy bool // may be dropped without a resync
+}
+
+// ClientMessage represents a message sent by a client
+type ClientMessage struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Message represents a WebSocket message. A single text frame may carry
+// several messages separated by newlines when the client's queue is coalesced.
 type Message struct {
+	ID        string                 `json:"id,omitempty"`
 	Type      string                 `json:"type"`
 	AuctionID string                 `json:"auction_id"`
 	Data      map[string]interface{} `json:"data"`
@@ -55,11 +134,14 @@ type Message struct {
 }
 
 // NewHub creates a new WebSocket hub
-func NewHub(redisClient *redis.Client, eventBus *redisMessaging.EventBus) *Hub {
+func NewHub(redisClient *redis.Client, eventBus *redisMessaging.EventBus, cache auction.Cache) *Hub {
 	return &Hub{
 		rooms:       make(map[string]*Room),
 		redisClient: redisClient,
 		eventBus:    eventBus,
+		cache:       cache,
+		instanceID:  uuid.New().String(),
+		reactions:   newReactionAggregator(),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				// Allow all origins in development
@@ -81,12 +163,32 @@ func (h *Hub) Start(ctx context.Context) error {
 	// Process incoming events from Redis
 	go h.processEvents(ctx)
 	
+	// Publish local viewers and broadcast cluster-wide counts
+	go h.runPresence(ctx)
+	
+	// Broadcast countdown ticks for rooms in their final minute
+	go h.runTicks(ctx)
+	
+	// Publish aggregated reactions
+	go h.runReactions(ctx)
+	
 	<-ctx.Done()
 	return nil
 }
 
 // Shutdown gracefully shuts down the hub
 func (h *Hub) Shutdown(ctx context.Context) error {
+	// Withdraw this instance's viewers instead of waiting for them to expire
+	if h.cache != nil {
+		h.mu.RLock()
+		for auctionID := range h.rooms {
+			if id, err := uuid.Parse(auctionID); err == nil {
+				h.cache.RemovePresence(ctx, id, h.instanceID)
+			}
+		}
+		h.mu.RUnlock()
+	}
+	
 	if h.subscription != nil {
 		return h.subscription.Close()
 	}
@@ -102,28 +204,34 @@ func (h *Hub) HandleConnection(w http.ResponseWriter, r *http.Request, auctionID
 		return
 	}
 	
-	// Get or create room
-	room := h.getOrCreateRoom(auctionID)
-	
 	// Create client
-	client := &Client{
-		hub:       h,
-		conn:      conn,
-		send:      make(chan []byte, 256),
-		room:      room,
-		userID:    userID,
-		auctionID: auctionID,
-	}
+	client := h.newClient(conn, auctionID, userID)
+	
+	// Register client; the viewer count goes out on the next presence tick
+	room := h.joinRoom(auctionID, client)
 	
-	// Register client
-	room.addClient(client)
-	h.broadcastViewerCount(auctionID)
+	// Let the client align its clock before the first countdown tick
+	h.loadRoomState(r.Context(), room)
+	client.sendDirect(h.serverTimeMessage(room, time.Now()))
 	
 	// Start goroutines
 	go client.writePump()
 	go client.readPump()
 }
 
+// newClient creates a client; conn is nil for SSE subscribers
+func (h *Hub) newClient(conn *websocket.Conn, auctionID, userID string) *Client {
+	return &Client{
+		hub:       h,
+		conn:      conn,
+		send:      make(chan outbound, sendBufferSize),
+		resync:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		userID:    userID,
+		auctionID: auctionID,
+	}
+}
+
 // processEvents processes events from Redis Pub/Sub
 func (h *Hub) processEvents(ctx context.Context) {
 	for {
@@ -144,39 +252,51 @@ func (h *Hub) processEvents(ctx context.Context) {
 
 // handleEvent handles a single event
 func (h *Hub) handleEvent(event redisMessaging.Event) {
+	if room := h.getRoom(event.AuctionID); room != nil {
+		switch event.Type {
+		case redisMessaging.EventAuctionStarted:
+			room.setStatus(auction.StatusLive)
+		case redisMessaging.EventAuctionEnded:
+			room.setStatus(auction.StatusEnded)
+		case redisMessaging.EventAuctionExtended:
+			if raw, ok := event.Payload["new_end_time"].(string); ok {
+				if endTime, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+					room.setEndTime(endTime)
+				}
+			}
+		}
+	}
+	
+	if msg, ok := eventMessage(event); ok {
+		h.broadcastToRoom(event.AuctionID, msg)
+	}
+}
+
+// eventMessage maps an event bus event to the message sent to clients
+func eventMessage(event redisMessaging.Event) (Message, bool) {
+	var msgType string
 	switch event.Type {
 	case redisMessaging.EventBidPlaced:
-		h.broadcastToRoom(event.AuctionID, Message{
-			Type:      "bid",
-			AuctionID: event.AuctionID,
-			Data:      event.Payload,
-			Timestamp: event.Timestamp,
-		})
-		
+		msgType = "bid"
 	case redisMessaging.EventAuctionStarted:
-		h.broadcastToRoom(event.AuctionID, Message{
-			Type:      "auction_started",
-			AuctionID: event.AuctionID,
-			Data:      event.Payload,
-			Timestamp: event.Timestamp,
-		})
-		
+		msgType = "auction_started"
 	case redisMessaging.EventAuctionEnded:
-		h.broadcastToRoom(event.AuctionID, Message{
-			Type:      "auction_ended",
-			AuctionID: event.AuctionID,
-			Data:      event.Payload,
-			Timestamp: event.Timestamp,
-		})
-		
+		msgType = "auction_ended"
 	case redisMessaging.EventAuctionExtended:
-		h.broadcastToRoom(event.AuctionID, Message{
-			Type:      "auction_extended",
-			AuctionID: event.AuctionID,
-			Data:      event.Payload,
-			Timestamp: event.Timestamp,
-		})
+		msgType = "auction_extended"
+	case redisMessaging.EventReactions:
+		msgType = "reactions"
+	default:
+		return Message{}, false
 	}
+	
+	return Message{
+		ID:        event.ID,
+		Type:      msgType,
+		AuctionID: event.AuctionID,
+		Data:      event.Payload,
+		Timestamp: event.Timestamp,
+	}, true
 }
 
 // broadcastToRoom broadcasts a message to all clients in a room
@@ -192,28 +312,143 @@ func (h *Hub) broadcastToRoom(auctionID string, msg Message) {
 		return
 	}
 	
-	room.broadcast(data)
+	room.broadcast(outbound{
+		id:    msg.ID,
+		data:  data,
+		lossy: msg.Type == "reactions",
+	})
 }
 
-// broadcastViewerCount broadcasts viewer count update
-func (h *Hub) broadcastViewerCount(auctionID string) {
-	room := h.getRoom(auctionID)
-	if room == nil {
-		return
+// runPresence periodically syncs presence with Redis for every local room
+func (h *Hub) runPresence(ctx context.Context) {
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.syncPresence(ctx)
+		}
+	}
+}
+
+// syncPresence flushes local viewers and broadcasts changed cluster-wide counts
+func (h *Hub) syncPresence(ctx context.Context) {
+	h.mu.Lock()
+	rooms := make([]*Room, 0, len(h.rooms))
+	var emptied []string
+	for auctionID, room := range h.rooms {
+		if room.isEmpty() {
+			delete(h.rooms, auctionID)
+			emptied = append(emptied, auctionID)
+			continue
+		}
+		rooms = append(rooms, room)
+	}
+	h.mu.Unlock()
+	
+	if h.cache != nil {
+		for _, auctionID := range emptied {
+			if id, err := uuid.Parse(auctionID); err == nil {
+				h.cache.RemovePresence(ctx, id, h.instanceID)
+			}
+		}
 	}
 	
-	count := room.getViewerCount()
+	now := time.Now()
+	for _, room := range rooms {
+		h.loadRoomState(ctx, room)
+		
+		presence := h.roomPresence(ctx, room, now)
+		if presence == nil || !room.updatePresence(*presence) {
+			continue
+		}
+		h.broadcastViewerCount(room, presence)
+	}
+}
+
+// roomPresence flushes a room's local viewers if needed and returns the
+// cluster-wide presence, falling back to local viewers without a cache
+func (h *Hub) roomPresence(ctx context.Context, room *Room, now time.Time) *auction.Presence {
+	local, dirty := room.snapshot()
+	if h.cache == nil {
+		return &auction.Presence{
+			Viewers:       len(local.UserIDs) + local.Anonymous,
+			Authenticated: len(local.UserIDs),
+			Anonymous:     local.Anonymous,
+		}
+	}
+	
+	auctionID, err := uuid.Parse(room.AuctionID)
+	if err != nil {
+		return nil
+	}
+	
+	if dirty || now.Sub(room.lastFlush) >= presenceHeartbeat {
+		if err := h.cache.SetPresence(ctx, auctionID, h.instanceID, local, presenceTTL); err != nil {
+			log.Printf("Failed to publish presence: %v", err)
+			room.markDirty()
+		} else {
+			room.lastFlush = now
+		}
+	}
+	
+	presence, err := h.cache.GetPresence(ctx, auctionID)
+	if err != nil {
+		log.Printf("Failed to read presence: %v", err)
+		return nil
+	}
+	return presence
+}
+
+// broadcastViewerCount broadcasts viewer count update
+func (h *Hub) broadcastViewerCount(room *Room, presence *auction.Presence) {
 	msg := Message{
 		Type:      "viewer_count",
-		AuctionID: auctionID,
+		AuctionID: room.AuctionID,
 		Data: map[string]interface{}{
-			"count": count,
+			"count":         presence.Viewers,
+			"authenticated": presence.Authenticated,
+			"anonymous":     presence.Anonymous,
 		},
 		Timestamp: time.Now(),
 	}
 	
 	data, _ := json.Marshal(msg)
-	room.broadcast(data)
+	room.broadcast(outbound{data: data})
+}
+
+// resyncMessage builds a fresh auction snapshot for a client that missed messages.
+// Without a cached state the client is expected to refetch the auction over REST.
+func (h *Hub) resyncMessage(room *Room) Message {
+	presence := room.currentPresence()
+	data := map[string]interface{}{
+		"viewer_count": presence.Viewers,
+	}
+	
+	if h.cache != nil {
+		if auctionID, err := uuid.Parse(room.AuctionID); err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			state, err := h.cache.GetAuctionState(ctx, auctionID)
+			cancel()
+			if err == nil {
+				state.ViewerCount = presence.Viewers
+				data["state"] = state
+			}
+		}
+	}
+	
+	now := time.Now()
+	data["server_time"] = now.UnixMilli()
+	
+	return Message{
+		Type:      "resync",
+		AuctionID: room.AuctionID,
+		Data:      data,
+		Timestamp: now,
+	}
 }
 
 // getRoom gets a room by auction ID
@@ -223,20 +458,24 @@ func (h *Hub) getRoom(auctionID string) *Room {
 	return h.rooms[auctionID]
 }
 
-// getOrCreateRoom gets or creates a room
-func (h *Hub) getOrCreateRoom(auctionID string) *Room {
+// joinRoom adds a client to its room, creating the room if needed. Holding the
+// hub lock keeps the presence loop from reaping the room mid-join.
+func (h *Hub) joinRoom(auctionID string, c *Client) *Room {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	
-	if room, ok := h.rooms[auctionID]; ok {
-		return room
+	room, ok := h.rooms[auctionID]
+	if !ok {
+		room = &Room{
+			AuctionID: auctionID,
+			clients:   make(map[*Client]bool),
+			users:     make(map[string]int),
+		}
+		h.rooms[auctionID] = room
 	}
 	
-	room := &Room{
-		AuctionID: auctionID,
-		clients:   make(map[*Client]bool),
-	}
-	h.rooms[auctionID] = room
+	c.room = room
+	room.addClient(c)
 	return room
 }
 
@@ -246,7 +485,12 @@ func (r *Room) addClient(c *Client) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.clients[c] = true
-	r.viewerCount++
+	if c.userID != "" {
+		r.users[c.userID]++
+	} else {
+		r.anonymous++
+	}
+	r.dirty = true
 }
 
 func (r *Room) removeClient(c *Client) {
@@ -254,18 +498,67 @@ func (r *Room) removeClient(c *Client) {
 	defer r.mu.Unlock()
 	if _, ok := r.clients[c]; ok {
 		delete(r.clients, c)
-		r.viewerCount--
+		if c.userID != "" {
+			r.users[c.userID]--
+			if r.users[c.userID] <= 0 {
+				delete(r.users, c.userID)
+			}
+		} else {
+			r.anonymous--
+		}
+		r.dirty = true
 		close(c.send)
 	}
 }
 
-func (r *Room) getViewerCount() int {
+func (r *Room) isEmpty() bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	return r.viewerCount
+	return len(r.clients) == 0
+}
+
+func (r *Room) markDirty() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dirty = true
+}
+
+// updatePresence stores the latest cluster-wide presence and reports whether it changed
+func (r *Room) updatePresence(p auction.Presence) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p == r.lastPresence {
+		return false
+	}
+	r.lastPresence = p
+	return true
+}
+
+func (r *Room) currentPresence() auction.Presence {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastPresence
+}
+
+// snapshot returns the room's local viewers and clears the dirty flag
+func (r *Room) snapshot() (*auction.InstancePresence, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	
+	userIDs := make([]string, 0, len(r.users))
+	for userID := range r.users {
+		userIDs = append(userIDs, userID)
+	}
+	dirty := r.dirty
+	r.dirty = false
+	
+	return &auction.InstancePresence{
+		UserIDs:   userIDs,
+		Anonymous: r.anonymous,
+	}, dirty
 }
 
-func (r *Room) broadcast(message []byte) {
+func (r *Room) broadcast(message outbound) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	
@@ -273,7 +566,12 @@ func (r *Room) broadcast(message []byte) {
 		select {
 		case client.send <- message:
 		default:
-			// Client send buffer full, skip
+			// Client send buffer full: drop and schedule a resync
+			// unless the message is one clients can live without
+			client.hub.metrics.dropped.Add(1)
+			if !message.lossy {
+				client.markLagging()
+			}
 		}
 	}
 }
@@ -284,9 +582,9 @@ func (c *Client) readPump() {
 	defer func() {
 		c.room.removeClient(c)
 		c.conn.Close()
-		c.hub.broadcastViewerCount(c.auctionID)
 	}()
 	
+	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -294,13 +592,50 @@ func (c *Client) readPump() {
 	})
 	
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
+		
+		c.handleMessage(data)
+	}
+}
+
+// handleMessage handles a message sent by the client
+func (c *Client) handleMessage(data []byte) {
+	received := time.Now()
+	
+	var msg ClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return
+	}
+	
+	switch msg.Type {
+	case "ping":
+		c.handlePing(msg.Data, received)
+	case "reaction":
+		c.handleReaction(msg.Data, received)
+	}
+}
+
+// sendDirect queues a message for this client only. The send channel is closed
+// when the client unregisters, so this must only be called before its reader
+// starts or from the reader itself.
+func (c *Client) sendDirect(msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return
+	}
+	
+	select {
+	case c.send <- outbound{id: msg.ID, data: data}:
+	default:
+		c.hub.metrics.dropped.Add(1)
+		c.markLagging()
 	}
 }
 
@@ -320,7 +655,23 @@ func (c *Client) writePump() {
 				return
 			}
 			
-			c.conn.WriteMessage(websocket.TextMessage, message)
+			if err := c.writeBatch(message.data); err != nil {
+				return
+			}
+			
+		case <-c.resync:
+			if !c.allowResync(time.Now()) {
+				c.disconnectSlow()
+				return
+			}
+			
+			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := c.writeResync(); err != nil {
+				if errors.Is(err, errSendClosed) {
+					c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				}
+				return
+			}
 			
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
@@ -328,5 +679,113 @@ func (c *Client) writePump() {
 				return
 			}
 		}
+		
+		// An empty queue means the client has caught up
+		if len(c.send) == 0 && len(c.resync) == 0 {
+			c.lagSince.Store(0)
+		}
 	}
-}
\ No newline at end of file
+}
+
+// writeBatch writes message and whatever else is already queued as a single
+// frame of newline-separated messages
+func (c *Client) writeBatch(message []byte) error {
+	w, err := c.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return err
+	}
+	w.Write(message)
+	
+	coalesced := 0
+	for coalesced < maxBatchSize-1 && len(c.send) > 0 {
+		next, ok := <-c.send
+		if !ok {
+			// Closed; the close frame goes out on the next receive
+			break
+		}
+		w.Write(newline)
+		w.Write(next.data)
+		coalesced++
+	}
+	if coalesced > 0 {
+		c.hub.metrics.coalesced.Add(int64(coalesced))
+	}
+	
+	return w.Close()
+}
+
+// writeResync discards the stale queue and sends a fresh snapshot in its place
+func (c *Client) writeResync() error {
+	data, err := c.prepareResync()
+	if err != nil {
+		return err
+	}
+	if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return err
+	}
+	c.hub.metrics.resyncs.Add(1)
+	return nil
+}
+
+// prepareResync drains messages superseded by a resync and serializes the snapshot
+func (c *Client) prepareResync() ([]byte, error) {
+	superseded := 0
+	for len(c.send) > 0 {
+		if _, ok := <-c.send; !ok {
+			return nil, errSendClosed
+		}
+		superseded++
+	}
+	if superseded > 0 {
+		c.hub.metrics.dropped.Add(int64(superseded))
+	}
+	
+	return json.Marshal(c.hub.resyncMessage(c.room))
+}
+
+// markLagging records a dropped message, scheduling a resync or disconnecting
+// a client that has been lagging for too long
+func (c *Client) markLagging() {
+	now := time.Now()
+	if !c.lagSince.CompareAndSwap(0, now.UnixNano()) {
+		if now.Sub(time.Unix(0, c.lagSince.Load())) > maxLag {
+			c.disconnectSlow()
+			return
+		}
+	}
+	
+	select {
+	case c.resync <- struct{}{}:
+	default:
+		// Resync already pending
+	}
+}
+
+// allowResync reports whether another resync fits within the rate limit
+func (c *Client) allowResync(now time.Time) bool {
+	recent := c.resyncs[:0]
+	for _, t := range c.resyncs {
+		if now.Sub(t) < resyncWindow {
+			recent = append(recent, t)
+		}
+	}
+	c.resyncs = append(recent, now)
+	return len(c.resyncs) <= maxResyncs
+}
+
+// disconnectSlow closes a client that cannot keep up; its reader then unregisters it
+func (c *Client) disconnectSlow() {
+	c.closeOnce.Do(func() {
+		c.hub.metrics.slowDisconnects.Add(1)
+		close(c.done)
+		if c.conn == nil {
+			return
+		}
+		c.conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"),
+			time.Now().Add(time.Second),
+		)
+		c.conn.Close()
+	})
+}
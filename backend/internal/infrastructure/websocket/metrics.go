@@ -0,0 +1,29 @@
+package websocket
+
+import "sync/atomic"
+
+// Metrics counts hub backpressure events
+type Metrics struct {
+	dropped         atomic.Int64
+	coalesced       atomic.Int64
+	resyncs         atomic.Int64
+	slowDisconnects atomic.Int64
+}
+
+// MetricsSnapshot is a point-in-time copy of the hub metrics
+type MetricsSnapshot struct {
+	MessagesDropped   int64 `json:"messages_dropped"`
+	MessagesCoalesced int64 `json:"messages_coalesced"`
+	Resyncs           int64 `json:"resyncs"`
+	SlowDisconnects   int64 `json:"slow_disconnects"`
+}
+
+// Metrics returns the current backpressure counters
+func (h *Hub) Metrics() MetricsSnapshot {
+	return MetricsSnapshot{
+		MessagesDropped:   h.metrics.dropped.Load(),
+		MessagesCoalesced: h.metrics.coalesced.Load(),
+		Resyncs:           h.metrics.resyncs.Load(),
+		SlowDisconnects:   h.metrics.slowDisconnects.Load(),
+	}
+}
@@ -0,0 +1,130 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// reactionWindow is how long reactions are aggregated before being published
+	reactionWindow = 250 * time.Millisecond
+	// maxReactionsPerSecond caps how fast a single client can react
+	maxReactionsPerSecond = 10
+)
+
+// allowedReactions is the set of reactions clients may send
+var allowedReactions = map[string]bool{
+	"heart": true,
+	"fire":  true,
+	"clap":  true,
+	"laugh": true,
+	"wow":   true,
+}
+
+// ErrInvalidReaction is returned for reactions outside the allowed set
+var ErrInvalidReaction = errors.New("invalid reaction")
+
+// reactionData is the payload of a client reaction
+type reactionData struct {
+	Reaction string `json:"reaction"`
+}
+
+// reactionAggregator counts reactions per auction until the next flush
+type reactionAggregator struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int // auction_id -> reaction -> count
+}
+
+func newReactionAggregator() *reactionAggregator {
+	return &reactionAggregator{
+		counts: make(map[string]map[string]int),
+	}
+}
+
+func (a *reactionAggregator) add(auctionID, reaction string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	
+	counts, ok := a.counts[auctionID]
+	if !ok {
+		counts = make(map[string]int)
+		a.counts[auctionID] = counts
+	}
+	counts[reaction]++
+}
+
+// drain returns the pending counts and starts a new window
+func (a *reactionAggregator) drain() map[string]map[string]int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	
+	pending := a.counts
+	a.counts = make(map[string]map[string]int, len(pending))
+	return pending
+}
+
+// AddReaction records a reaction for the current aggregation window. It is
+// used by WebSocket clients and by the REST endpoint for SSE clients.
+func (h *Hub) AddReaction(auctionID, reaction string) error {
+	if !allowedReactions[reaction] {
+		return ErrInvalidReaction
+	}
+	h.reactions.add(auctionID, reaction)
+	return nil
+}
+
+// runReactions publishes each window's reaction counts, so a room full of
+// reacting viewers costs one Redis publish per instance per window, not one
+// per reaction
+func (h *Hub) runReactions(ctx context.Context) {
+	ticker := time.NewTicker(reactionWindow)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.flushReactions(ctx)
+		}
+	}
+}
+
+// flushReactions publishes pending counts cluster-wide; failures are dropped
+func (h *Hub) flushReactions(ctx context.Context) {
+	for auctionID, counts := range h.reactions.drain() {
+		id, err := uuid.Parse(auctionID)
+		if err != nil {
+			continue
+		}
+		if err := h.eventBus.PublishReactions(ctx, id, counts, reactionWindow); err != nil {
+			log.Printf("Failed to publish reactions: %v", err)
+		}
+	}
+}
+
+// handleReaction records a reaction sent over the WebSocket, silently
+// dropping invalid ones and those over the client's rate limit
+func (c *Client) handleReaction(raw json.RawMessage, received time.Time) {
+	var reaction reactionData
+	if err := json.Unmarshal(raw, &reaction); err != nil {
+		return
+	}
+	
+	if received.Sub(c.reactionWindow) >= time.Second {
+		c.reactionWindow = received
+		c.reactionCount = 0
+	}
+	if c.reactionCount >= maxReactionsPerSecond {
+		return
+	}
+	c.reactionCount++
+	
+	c.hub.AddReaction(c.auctionID, reaction.Reaction)
+}
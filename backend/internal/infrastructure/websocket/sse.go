@@ -0,0 +1,217 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	redisMessaging "github.com/blytz/live/backend/internal/infrastructure/messaging/redis"
+	"github.com/google/uuid"
+)
+
+const (
+	// sseKeepAlive is how often an idle event stream sends a comment line
+	sseKeepAlive = 15 * time.Second
+	// sseRetry is the reconnection delay suggested to EventSource clients
+	sseRetry = 3 * time.Second
+	// sseReplayLimit caps how many missed events are replayed on resume
+	sseReplayLimit = 200
+)
+
+// sseStream writes messages in the text/event-stream format
+type sseStream struct {
+	w          http.ResponseWriter
+	flusher    http.Flusher
+	controller *http.ResponseController
+	lastID     string
+}
+
+// ServeEvents streams a room's messages as Server-Sent Events for clients that
+// cannot use WebSockets. Subscribers share the room fan-out, presence and
+// slow-consumer handling with WebSocket clients. A non-empty lastEventID
+// replays missed events from the auction's history, or sends a resync when
+// the history no longer reaches back that far.
+func (h *Hub) ServeEvents(w http.ResponseWriter, r *http.Request, auctionID, userID, lastEventID string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	
+	client := h.newClient(nil, auctionID, userID)
+	room := h.joinRoom(auctionID, client)
+	defer room.removeClient(client)
+	
+	ctx := r.Context()
+	h.loadRoomState(ctx, room)
+	
+	stream := &sseStream{
+		w:          w,
+		flusher:    flusher,
+		controller: http.NewResponseController(w),
+		lastID:     lastEventID,
+	}
+	stream.setWriteDeadline()
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds())
+	
+	if err := stream.writeMessage(h.serverTimeMessage(room, time.Now())); err != nil {
+		return
+	}
+	if lastEventID != "" {
+		if err := h.replayEvents(ctx, client, stream, lastEventID); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+	
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	
+	for {
+		select {
+		case <-ctx.Done():
+			return
+			
+		case <-client.done:
+			return
+			
+		case message, ok := <-client.send:
+			if !ok {
+				return
+			}
+			if err := stream.writeBatch(client, message); err != nil {
+				return
+			}
+			
+		case <-client.resync:
+			if !client.allowResync(time.Now()) {
+				client.disconnectSlow()
+				return
+			}
+			if err := stream.writeResync(client); err != nil {
+				return
+			}
+			
+		case <-keepAlive.C:
+			stream.setWriteDeadline()
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+		
+		// An empty queue means the client has caught up
+		if len(client.send) == 0 && len(client.resync) == 0 {
+			client.lagSince.Store(0)
+		}
+	}
+}
+
+// replayEvents writes the events a resuming client missed, falling back to a
+// resync when they cannot be replayed
+func (h *Hub) replayEvents(ctx context.Context, client *Client, stream *sseStream, lastEventID string) error {
+	auctionID, err := uuid.Parse(client.auctionID)
+	if err != nil {
+		return err
+	}
+	
+	events, err := h.eventBus.Replay(ctx, auctionID, lastEventID, sseReplayLimit)
+	if err != nil {
+		if !errors.Is(err, redisMessaging.ErrHistoryGap) {
+			log.Printf("Failed to replay events: %v", err)
+		}
+		return stream.writeResync(client)
+	}
+	
+	for _, event := range events {
+		if msg, ok := eventMessage(event); ok {
+			if err := stream.writeMessage(msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeMessage serializes and writes a single message without flushing
+func (s *sseStream) writeMessage(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.write(outbound{id: msg.ID, data: data})
+}
+
+// write writes one event. Events at or before the last sent ID were already
+// delivered by replay and are skipped.
+func (s *sseStream) write(message outbound) error {
+	if message.id != "" {
+		if s.lastID != "" && redisMessaging.CompareEventIDs(message.id, s.lastID) <= 0 {
+			return nil
+		}
+		if _, err := fmt.Fprintf(s.w, "id: %s\n", message.id); err != nil {
+			return err
+		}
+		s.lastID = message.id
+	}
+	_, err := fmt.Fprintf(s.w, "data: %s\n\n", message.data)
+	return err
+}
+
+// writeBatch writes message and whatever else is already queued, then flushes once
+func (s *sseStream) writeBatch(client *Client, message outbound) error {
+	s.setWriteDeadline()
+	if err := s.write(message); err != nil {
+		return err
+	}
+	
+	coalesced := 0
+	for coalesced < maxBatchSize-1 && len(client.send) > 0 {
+		next, ok := <-client.send
+		if !ok {
+			break
+		}
+		if err := s.write(next); err != nil {
+			return err
+		}
+		coalesced++
+	}
+	if coalesced > 0 {
+		client.hub.metrics.coalesced.Add(int64(coalesced))
+	}
+	
+	s.flusher.Flush()
+	return nil
+}
+
+// writeResync discards the stale queue and sends a fresh snapshot in its place
+func (s *sseStream) writeResync(client *Client) error {
+	data, err := client.prepareResync()
+	if err != nil {
+		return err
+	}
+	
+	s.setWriteDeadline()
+	if err := s.write(outbound{data: data}); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	client.hub.metrics.resyncs.Add(1)
+	return nil
+}
+
+// setWriteDeadline bounds each write so a stalled reader cannot block the
+// stream forever; writers that do not support deadlines are left as is
+func (s *sseStream) setWriteDeadline() {
+	s.controller.SetWriteDeadline(time.Now().Add(10 * time.Second))
+}
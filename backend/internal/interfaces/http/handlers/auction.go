@@ -32,9 +32,17 @@ type CreateAuctionRequest struct {
 	StartPrice   float64 `json:"start_price" binding:"required,gt=0"`
 	ReservePrice *float64 `json:"reserve_price"`
 	BuyNowPrice  *float64 `json:"buy_now_price"`
+	BidderRequirement string  `json:"bidder_requirement"` // "", "payment_method" or "deposit_hold"
+	DepositAmount     float64 `json:"deposit_amount"`
 	IsFeatured   bool    `json:"is_featured"`
 }
 
+// RelistAuctionRequest represents the schedule of a relisted auction
+type RelistAuctionRequest struct {
+	StartTime string `json:"start_time" binding:"required"` // RFC3339
+	EndTime   string `json:"end_time" binding:"required"`
+}
+
 // PlaceBidRequest represents bid placement request
 type PlaceBidRequest struct {
 	Amount float64 `json:"amount" binding:"required,gt=0"`
@@ -57,8 +65,11 @@ type AuctionResponse struct {
 	EndTime      time.Time  `json:"end_time"`
 	Status       string     `json:"status"`
 	StartPrice   float64    `json:"start_price"`
+	BidderRequirement string  `json:"bidder_requirement,omitempty"`
+	DepositAmount     float64 `json:"deposit_amount,omitempty"`
 	CurrentBid   *BidResponse `json:"current_bid,omitempty"`
 	BidCount     int        `json:"bid_count"`
+	ViewerCount  int        `json:"viewer_count"`
 	LiveKitRoom  string     `json:"livekit_room"`
 	IsFeatured   bool       `json:"is_featured"`
 	CreatedAt    time.Time  `json:"created_at"`
@@ -113,6 +124,8 @@ func (h *AuctionHandler) CreateAuction(c *gin.Context) {
 		StartPrice:   req.StartPrice,
 		ReservePrice: req.ReservePrice,
 		BuyNowPrice:  req.BuyNowPrice,
+		BidderRequirement: auctionDomain.BidderRequirement(req.BidderRequirement),
+		DepositAmount:     req.DepositAmount,
 		IsFeatured:   req.IsFeatured,
 	})
 	if err != nil {
@@ -217,6 +230,40 @@ func (h *AuctionHandler) EndAuction(c *gin.Context) {
 	respondJSON(c, http.StatusOK, gin.H{"message": "auction ended"})
 }
 
+// RelistAuction reopens the item of an unsold or unpaid auction as a new auction
+func (h *AuctionHandler) RelistAuction(c *gin.Context) {
+	sellerID, auctionID, ok := userAndIDParams(c, "invalid auction id")
+	if !ok {
+		return
+	}
+
+	var req RelistAuctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid start_time format"))
+		return
+	}
+
+	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid end_time format"))
+		return
+	}
+
+	a, err := h.service.RelistAuction(c.Request.Context(), auctionID, sellerID, startTime, endTime)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toAuctionResponse(a))
+}
+
 // Helper functions
 func toAuctionResponse(a *auctionDomain.Auction) *AuctionResponse {
 	resp := &AuctionResponse{
@@ -229,7 +276,10 @@ func toAuctionResponse(a *auctionDomain.Auction) *AuctionResponse {
 		EndTime:     a.EndTime,
 		Status:      string(a.Status),
 		StartPrice:  a.StartPrice,
+		BidderRequirement: string(a.BidderRequirement),
+		DepositAmount:     a.DepositAmount,
 		BidCount:    a.BidCount,
+		ViewerCount: a.ViewerCount,
 		LiveKitRoom: a.LiveKitRoom,
 		IsFeatured:  a.IsFeatured,
 		CreatedAt:   a.CreatedAt,
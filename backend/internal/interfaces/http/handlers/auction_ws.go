@@ -3,19 +3,23 @@ package handlers
 import (
 	"net/http"
 
+	auctionApp "github.com/blytz/live/backend/internal/application/auction"
+	auctionDomain "github.com/blytz/live/backend/internal/domain/auction"
 	"github.com/blytz/live/backend/internal/infrastructure/websocket"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 // AuctionWSHandler handles WebSocket connections for auctions
 type AuctionWSHandler struct {
-	hub *websocket.Hub
+	hub      *websocket.Hub
+	auctions *auctionApp.Service
 }
 
 // NewAuctionWSHandler creates a new auction WebSocket handler
-func NewAuctionWSHandler(hub *websocket.Hub) *AuctionWSHandler {
-	return &AuctionWSHandler{hub: hub}
+func NewAuctionWSHandler(hub *websocket.Hub, auctions *auctionApp.Service) *AuctionWSHandler {
+	return &AuctionWSHandler{hub: hub, auctions: auctions}
 }
 
 // HandleWebSocket handles WebSocket upgrade for auction room
@@ -28,13 +32,82 @@ func (h *AuctionWSHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 	
-	// Get user ID from context (may be empty for anonymous viewers)
-	userID, _ := c.Get("user_id")
-	userIDStr := ""
-	if userID != nil {
-		userIDStr = userID.(uuid.UUID).String()
+	// Upgrade to WebSocket; user ID may be empty for anonymous viewers
+	h.hub.HandleConnection(c.Writer, c.Request, auctionID, viewerID(c))
+}
+
+// StreamEvents streams auction updates as Server-Sent Events for clients
+// whose networks block WebSockets
+func (h *AuctionWSHandler) StreamEvents(c *gin.Context) {
+	auctionID := c.Param("id")
+	
+	// Validate auction ID
+	if _, err := uuid.Parse(auctionID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid auction id"})
+		return
+	}
+	
+	// EventSource sends Last-Event-ID on reconnect; the query parameter lets
+	// a fresh page load resume too
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	
+	h.hub.ServeEvents(c.Writer, c.Request, auctionID, viewerID(c), lastEventID)
+}
+
+// Metrics returns WebSocket backpressure metrics for this instance
+func (h *AuctionWSHandler) Metrics(c *gin.Context) {
+	respondJSON(c, http.StatusOK, h.hub.Metrics())
+}
+
+// SendReactionRequest represents a reaction sent over REST
+type SendReactionRequest struct {
+	Reaction string `json:"reaction" binding:"required"`
+}
+
+// SendReaction records a reaction for clients on the SSE fallback, which
+// cannot send messages over their event stream. Only live auctions take
+// reactions.
+func (h *AuctionWSHandler) SendReaction(c *gin.Context) {
+	auctionID := c.Param("id")
+	id, err := uuid.Parse(auctionID)
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid auction id"))
+		return
 	}
 	
-	// Upgrade to WebSocket
-	h.hub.HandleConnection(c.Writer, c.Request, auctionID, userIDStr)
+	var req SendReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+	
+	a, err := h.auctions.GetAuction(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if a.Status != auctionDomain.StatusLive {
+		respondError(c, appErrors.New(appErrors.ErrAuctionNotLive, "auction is not live"))
+		return
+	}
+	
+	if err := h.hub.AddReaction(auctionID, req.Reaction); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+	
+	c.Status(http.StatusAccepted)
+}
+
+// viewerID returns the authenticated user ID, or empty for anonymous viewers
+func viewerID(c *gin.Context) string {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return ""
+	}
+	userIDStr, _ := userID.(string)
+	return userIDStr
 }
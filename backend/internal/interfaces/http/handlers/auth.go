@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/blytz/live/backend/internal/application/auth"
 	"github.com/blytz/live/backend/internal/domain/user"
@@ -47,23 +48,51 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password" binding:"required,min=8"`
 }
 
+// TokenRequest represents a request carrying an emailed action token
+type TokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// PasswordResetRequest represents password reset link request
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest represents password reset confirmation request
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	User         UserDTO `json:"user"`
-	AccessToken  string  `json:"access_token"`
-	RefreshToken string  `json:"refresh_token"`
-	ExpiresIn    int     `json:"expires_in"`
+	User                   UserDTO `json:"user"`
+	AccessToken            string  `json:"access_token"`
+	RefreshToken           string  `json:"refresh_token"`
+	ExpiresIn              int     `json:"expires_in"`
+	TwoFactorSetupRequired bool    `json:"two_factor_setup_required,omitempty"`
+}
+
+// SessionDTO represents a signed-in session
+type SessionDTO struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	Current   bool      `json:"current"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // UserDTO represents user data transfer object
 type UserDTO struct {
-	ID            string `json:"id"`
-	Email         string `json:"email"`
-	FirstName     string `json:"first_name"`
-	LastName      string `json:"last_name"`
-	AvatarURL     string `json:"avatar_url"`
-	Role          string `json:"role"`
-	EmailVerified bool   `json:"email_verified"`
+	ID               string `json:"id"`
+	Email            string `json:"email"`
+	FirstName        string `json:"first_name"`
+	LastName         string `json:"last_name"`
+	AvatarURL        string `json:"avatar_url"`
+	Role             string `json:"role"`
+	EmailVerified    bool   `json:"email_verified"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
 }
 
 // Register handles user registration
@@ -80,6 +109,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Phone:     req.Phone,
+		Client:    clientInfo(c),
 	})
 	if err != nil {
 		respondError(c, err)
@@ -100,12 +130,22 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	resp, err := h.service.Login(c.Request.Context(), &auth.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   clientInfo(c),
 	})
 	if err != nil {
 		respondError(c, err)
 		return
 	}
 
+	if resp.ChallengeToken != "" {
+		respondJSON(c, http.StatusOK, &TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    resp.ChallengeToken,
+			ExpiresIn:         resp.ExpiresIn,
+		})
+		return
+	}
+
 	respondJSON(c, http.StatusOK, toAuthResponse(resp))
 }
 
@@ -177,30 +217,181 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	respondJSON(c, http.StatusOK, gin.H{"message": "password changed successfully"})
 }
 
-// Logout handles user logout
+// Logout handles user logout, revoking the current session
 func (h *AuthHandler) Logout(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		respondError(c, appErrors.ErrUnauthorizedAccess)
+		return
+	}
+
+	if err := h.service.Logout(c.Request.Context(), sessionID); err != nil {
+		respondError(c, err)
+		return
+	}
+
 	respondJSON(c, http.StatusOK, gin.H{"message": "logged out successfully"})
 }
 
+// LogoutAll revokes every session of the current user
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.service.LogoutAll(c.Request.Context(), userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "logged out of all sessions"})
+}
+
+// ListSessions lists the current user's sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	sessions, err := h.service.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	currentID := c.GetString("session_id")
+	responses := make([]SessionDTO, len(sessions))
+	for i, s := range sessions {
+		responses[i] = toSessionDTO(s, currentID)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// RevokeSession signs one of the current user's sessions out
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, sessionID, ok := userAndIDParams(c, "invalid session id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "session revoked"})
+}
+
+// RequestEmailVerification emails the current user a new verification link
+func (h *AuthHandler) RequestEmailVerification(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.service.RequestEmailVerification(c.Request.Context(), userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusAccepted, gin.H{"message": "verification email sent"})
+}
+
+// ConfirmEmail verifies an email address with a token from a verification link
+func (h *AuthHandler) ConfirmEmail(c *gin.Context) {
+	var req TokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	u, err := h.service.ConfirmEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toUserDTO(u))
+}
+
+// RequestPasswordReset emails a password reset link. The response is the
+// same whether or not the email is registered.
+func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
+	var req PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusAccepted, gin.H{"message": "if the email is registered, a reset link has been sent"})
+}
+
+// ResetPassword sets a new password with a token from a reset link
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "password reset successfully"})
+}
+
 // Helper functions
 func toAuthResponse(resp *auth.AuthResponse) *AuthResponse {
 	return &AuthResponse{
-		User:         toUserDTO(resp.User),
-		AccessToken:  resp.AccessToken,
-		RefreshToken: resp.RefreshToken,
-		ExpiresIn:    resp.ExpiresIn,
+		User:                   toUserDTO(resp.User),
+		AccessToken:            resp.AccessToken,
+		RefreshToken:           resp.RefreshToken,
+		ExpiresIn:              resp.ExpiresIn,
+		TwoFactorSetupRequired: resp.TwoFactorSetupRequired,
+	}
+}
+
+func toSessionDTO(s *user.Session, currentID string) SessionDTO {
+	return SessionDTO{
+		ID:        s.ID.String(),
+		UserAgent: s.UserAgent,
+		IPAddress: s.IPAddress,
+		Current:   s.ID.String() == currentID,
+		CreatedAt: s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+	}
+}
+
+func clientInfo(c *gin.Context) auth.ClientInfo {
+	return auth.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
 	}
 }
 
 func toUserDTO(u *user.User) UserDTO {
 	return UserDTO{
-		ID:            u.ID.String(),
-		Email:         u.Email,
-		FirstName:     u.FirstName,
-		LastName:      u.LastName,
-		AvatarURL:     u.AvatarURL,
-		Role:          string(u.Role),
-		EmailVerified: u.EmailVerified,
+		ID:               u.ID.String(),
+		Email:            u.Email,
+		FirstName:        u.FirstName,
+		LastName:         u.LastName,
+		AvatarURL:        u.AvatarURL,
+		Role:             string(u.Role),
+		EmailVerified:    u.EmailVerified,
+		TwoFactorEnabled: u.TwoFactorEnabled,
 	}
 }
 
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	cartApp "github.com/blytz/live/backend/internal/application/cart"
+	orderDomain "github.com/blytz/live/backend/internal/domain/order"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CartTokenHeader carries the guest cart token
+const CartTokenHeader = "X-Cart-Token"
+
+// CartHandler handles shopping cart HTTP requests
+type CartHandler struct {
+	service *cartApp.Service
+}
+
+// NewCartHandler creates a new cart handler
+func NewCartHandler(service *cartApp.Service) *CartHandler {
+	return &CartHandler{service: service}
+}
+
+// AddCartItemRequest represents add-to-cart request
+type AddCartItemRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// UpdateCartItemRequest represents cart quantity update request
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+}
+
+// ApplyCouponRequest represents cart coupon request
+type ApplyCouponRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// CheckoutRequest represents cart checkout request. With neither
+// address_id nor shipping_address the user's default address is used.
+type CheckoutRequest struct {
+	AddressID       string               `json:"address_id"`
+	ShippingAddress *orderDomain.Address `json:"shipping_address"`
+}
+
+// CartResponse represents cart response
+type CartResponse struct {
+	ID          string             `json:"id"`
+	Token       string             `json:"token"`
+	Items       []CartItemResponse `json:"items"`
+	ItemCount   int                `json:"item_count"`
+	Subtotal    float64            `json:"subtotal"`
+	CouponCode  string             `json:"coupon_code,omitempty"`
+	CouponError string             `json:"coupon_error,omitempty"`
+	Discount    float64            `json:"discount"`
+	Total       float64            `json:"total"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+}
+
+// CartItemResponse represents cart item response
+type CartItemResponse struct {
+	ProductID       string     `json:"product_id"`
+	Name            string     `json:"name,omitempty"`
+	ImageURL        string     `json:"image_url,omitempty"`
+	Quantity        int        `json:"quantity"`
+	UnitPrice       float64    `json:"unit_price"`
+	CurrentPrice    *float64   `json:"current_price,omitempty"`
+	FlashSaleEndsAt *time.Time `json:"flash_sale_ends_at,omitempty"`
+	Total           float64    `json:"total"`
+	Discount        float64    `json:"discount"`
+	Available       bool       `json:"available"`
+	PriceChanged    bool       `json:"price_changed"`
+}
+
+// GetCart gets the current cart
+func (h *CartHandler) GetCart(c *gin.Context) {
+	v, err := h.service.GetCart(c.Request.Context(), cartOwner(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// AddItem adds a product to the cart
+func (h *CartHandler) AddItem(c *gin.Context) {
+	var req AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid product_id"))
+		return
+	}
+
+	v, err := h.service.AddItem(c.Request.Context(), cartOwner(c), productID, req.Quantity)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// UpdateItem changes the quantity of a cart item
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("productId"))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid product id"))
+		return
+	}
+
+	var req UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	v, err := h.service.UpdateItem(c.Request.Context(), cartOwner(c), productID, req.Quantity)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// RemoveItem removes a product from the cart
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("productId"))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid product id"))
+		return
+	}
+
+	v, err := h.service.RemoveItem(c.Request.Context(), cartOwner(c), productID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// ClearCart removes all items from the cart
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	v, err := h.service.ClearCart(c.Request.Context(), cartOwner(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// ApplyCoupon applies a coupon code to the cart
+func (h *CartHandler) ApplyCoupon(c *gin.Context) {
+	var req ApplyCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	v, err := h.service.ApplyCoupon(c.Request.Context(), cartOwner(c), req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// RemoveCoupon removes the coupon from the cart
+func (h *CartHandler) RemoveCoupon(c *gin.Context) {
+	v, err := h.service.RemoveCoupon(c.Request.Context(), cartOwner(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// MergeCart merges the guest cart from the cart token header into the user's cart
+func (h *CartHandler) MergeCart(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	token := c.GetHeader(CartTokenHeader)
+	if token == "" {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "missing cart token"))
+		return
+	}
+
+	v, err := h.service.MergeGuestCart(c.Request.Context(), userID, token)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondCart(c, http.StatusOK, v)
+}
+
+// Checkout turns the cart into pending orders, one per seller
+func (h *CartHandler) Checkout(c *gin.Context) {
+	var req CheckoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+			return
+		}
+	}
+
+	dest, err := destinationOf(req.AddressID, req.ShippingAddress)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	orders, err := h.service.Checkout(c.Request.Context(), cartOwner(c), dest)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp := make([]*OrderResponse, len(orders))
+	for i, o := range orders {
+		resp[i] = toOrderResponse(o)
+	}
+
+	respondJSON(c, http.StatusCreated, gin.H{"orders": resp})
+}
+
+// Helper functions
+func cartOwner(c *gin.Context) cartApp.Owner {
+	owner := cartApp.Owner{Token: c.GetHeader(CartTokenHeader)}
+	if userID, err := uuid.Parse(viewerID(c)); err == nil {
+		owner.UserID = &userID
+	}
+	return owner
+}
+
+func respondCart(c *gin.Context, status int, v *cartApp.View) {
+	c.Header(CartTokenHeader, v.Cart.Token)
+	respondJSON(c, status, toCartResponse(v))
+}
+
+func toCartResponse(v *cartApp.View) *CartResponse {
+	resp := &CartResponse{
+		ID:          v.Cart.ID.String(),
+		Token:       v.Cart.Token,
+		Items:       make([]CartItemResponse, len(v.Lines)),
+		ItemCount:   v.Cart.ItemCount(),
+		Subtotal:    v.Subtotal,
+		CouponCode:  v.Cart.CouponCode,
+		CouponError: v.CouponError,
+		Total:       v.Total,
+		ExpiresAt:   v.Cart.ExpiresAt,
+	}
+	if v.Discount != nil {
+		resp.Discount = v.Discount.Amount
+	}
+
+	for i, line := range v.Lines {
+		item := CartItemResponse{
+			ProductID:    line.Item.ProductID.String(),
+			Quantity:     line.Item.Quantity,
+			UnitPrice:    line.Item.UnitPrice,
+			Total:        line.Item.UnitPrice * float64(line.Item.Quantity),
+			Available:    line.Available,
+			PriceChanged: line.PriceChanged,
+		}
+		if line.FlashSale != nil {
+			endsAt := line.FlashSale.EndsAt
+			item.FlashSaleEndsAt = &endsAt
+		}
+		if v.Discount != nil {
+			item.Discount = v.Discount.ForProduct(line.Item.ProductID)
+		}
+		if line.Product != nil {
+			price := line.Price
+			item.Name = line.Product.Name
+			item.CurrentPrice = &price
+			if img := line.Product.GetPrimaryImage(); img != nil {
+				item.ImageURL = img.URL
+			}
+		}
+		resp.Items[i] = item
+	}
+
+	return resp
+}
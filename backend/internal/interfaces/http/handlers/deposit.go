@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	depositApp "github.com/blytz/live/backend/internal/application/deposit"
+	depositDomain "github.com/blytz/live/backend/internal/domain/deposit"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DepositHandler handles bidder deposit HTTP requests
+type DepositHandler struct {
+	service *depositApp.Service
+}
+
+// NewDepositHandler creates a new deposit handler
+func NewDepositHandler(service *depositApp.Service) *DepositHandler {
+	return &DepositHandler{service: service}
+}
+
+// PlaceDepositRequest represents deposit hold request. Without a
+// payment_method_id the bidder's default saved method is used.
+type PlaceDepositRequest struct {
+	PaymentMethodID string `json:"payment_method_id"`
+}
+
+// DepositResponse represents deposit hold response
+type DepositResponse struct {
+	ID         string     `json:"id"`
+	AuctionID  string     `json:"auction_id"`
+	Amount     float64    `json:"amount"`
+	Currency   string     `json:"currency"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+}
+
+// PlaceDeposit pre-authorizes the auction's deposit so the user can bid
+func (h *DepositHandler) PlaceDeposit(c *gin.Context) {
+	userID, auctionID, ok := userAndIDParams(c, "invalid auction id")
+	if !ok {
+		return
+	}
+
+	var req PlaceDepositRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+			return
+		}
+	}
+
+	var methodID *uuid.UUID
+	if req.PaymentMethodID != "" {
+		id, err := uuid.Parse(req.PaymentMethodID)
+		if err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, "invalid payment_method_id"))
+			return
+		}
+		methodID = &id
+	}
+
+	hold, err := h.service.PlaceHold(c.Request.Context(), auctionID, userID, methodID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toDepositResponse(hold))
+}
+
+// GetDeposit gets the user's active deposit on an auction
+func (h *DepositHandler) GetDeposit(c *gin.Context) {
+	userID, auctionID, ok := userAndIDParams(c, "invalid auction id")
+	if !ok {
+		return
+	}
+
+	hold, err := h.service.GetHold(c.Request.Context(), auctionID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toDepositResponse(hold))
+}
+
+// Helper functions
+func toDepositResponse(h *depositDomain.Hold) *DepositResponse {
+	return &DepositResponse{
+		ID:         h.ID.String(),
+		AuctionID:  h.AuctionID.String(),
+		Amount:     h.Amount,
+		Currency:   h.Currency,
+		Status:     string(h.Status),
+		CreatedAt:  h.CreatedAt,
+		ReleasedAt: h.ReleasedAt,
+	}
+}
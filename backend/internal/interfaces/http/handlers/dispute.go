@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	disputeApp "github.com/blytz/live/backend/internal/application/dispute"
+	disputeDomain "github.com/blytz/live/backend/internal/domain/dispute"
+	userDomain "github.com/blytz/live/backend/internal/domain/user"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DisputeHandler handles dispute HTTP requests
+type DisputeHandler struct {
+	service *disputeApp.Service
+}
+
+// NewDisputeHandler creates a new dispute handler
+func NewDisputeHandler(service *disputeApp.Service) *DisputeHandler {
+	return &DisputeHandler{service: service}
+}
+
+// OpenDisputeRequest represents open dispute request
+type OpenDisputeRequest struct {
+	Reason      string  `json:"reason" binding:"required,oneof=not_as_described damaged not_received other"`
+	Description string  `json:"description" binding:"required"`
+	Amount      float64 `json:"amount"`
+}
+
+// RespondDisputeRequest represents the seller's response to a dispute
+type RespondDisputeRequest struct {
+	Response      string  `json:"response" binding:"required"`
+	OfferedAmount float64 `json:"offered_amount"`
+}
+
+// ResolveDisputeRequest represents resolve dispute request
+type ResolveDisputeRequest struct {
+	Outcome string  `json:"outcome" binding:"required,oneof=refund seller_favor"`
+	Amount  float64 `json:"amount"`
+	Note    string  `json:"note"`
+}
+
+// DisputeResponse represents dispute response
+type DisputeResponse struct {
+	ID              string                     `json:"id"`
+	OrderID         string                     `json:"order_id"`
+	BuyerID         string                     `json:"buyer_id"`
+	SellerID        string                     `json:"seller_id"`
+	Reason          string                     `json:"reason"`
+	Description     string                     `json:"description"`
+	RequestedAmount float64                    `json:"requested_amount"`
+	Status          string                     `json:"status"`
+	SellerResponse  string                     `json:"seller_response,omitempty"`
+	OfferedAmount   float64                    `json:"offered_amount,omitempty"`
+	RespondedAt     *time.Time                 `json:"responded_at,omitempty"`
+	DeadlineAt      *time.Time                 `json:"deadline_at,omitempty"`
+	EscalatedAt     *time.Time                 `json:"escalated_at,omitempty"`
+	Resolution      *DisputeResolution         `json:"resolution,omitempty"`
+	Evidence        []*DisputeEvidenceResponse `json:"evidence,omitempty"`
+	CreatedAt       time.Time                  `json:"created_at"`
+	UpdatedAt       time.Time                  `json:"updated_at"`
+}
+
+// DisputeResolution represents how a dispute was settled
+type DisputeResolution struct {
+	Outcome      string    `json:"outcome"`
+	RefundAmount float64   `json:"refund_amount"`
+	RefundID     *string   `json:"refund_id,omitempty"`
+	ResolvedBy   string    `json:"resolved_by"`
+	Note         string    `json:"note,omitempty"`
+	ResolvedAt   time.Time `json:"resolved_at"`
+}
+
+// DisputeEvidenceResponse represents evidence added to a dispute
+type DisputeEvidenceResponse struct {
+	ID          string    `json:"id"`
+	AuthorID    string    `json:"author_id"`
+	Note        string    `json:"note,omitempty"`
+	FileURL     string    `json:"file_url,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// OpenDispute opens a dispute against one of the current user's orders
+func (h *DisputeHandler) OpenDispute(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	var req OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	d, err := h.service.Open(c.Request.Context(), disputeApp.OpenRequest{
+		OrderID:     orderID,
+		BuyerID:     userID,
+		Reason:      disputeDomain.Reason(req.Reason),
+		Description: req.Description,
+		Amount:      req.Amount,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toDisputeResponse(d, nil))
+}
+
+// ListOrderDisputes lists an order's disputes
+func (h *DisputeHandler) ListOrderDisputes(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	disputes, err := h.service.ListOrderDisputes(c.Request.Context(), orderID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*DisputeResponse, len(disputes))
+	for i, d := range disputes {
+		responses[i] = toDisputeResponse(d, nil)
+	}
+	respondJSON(c, http.StatusOK, gin.H{"disputes": responses})
+}
+
+// ListDisputes lists the current user's disputes as a buyer, or as a seller
+// with ?role=seller
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	filter := disputeFilter(c)
+	switch c.DefaultQuery("role", "buyer") {
+	case "buyer":
+		filter.BuyerID = &userID
+	case "seller":
+		filter.SellerID = &userID
+	default:
+		respondError(c, appErrors.New(appErrors.ErrValidation, "role must be buyer or seller"))
+		return
+	}
+
+	h.listDisputes(c, filter)
+}
+
+// ListAllDisputes lists disputes across the platform for admins, e.g.
+// ?status=escalated for the ones awaiting a decision
+func (h *DisputeHandler) ListAllDisputes(c *gin.Context) {
+	h.listDisputes(c, disputeFilter(c))
+}
+
+// GetDispute gets a dispute with its evidence
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	actor, disputeID, ok := disputeParams(c)
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetDispute(c.Request.Context(), disputeID, actor)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toDisputeResponse(detail.Dispute, detail.Evidence))
+}
+
+// RespondDispute records the seller's response to a dispute
+func (h *DisputeHandler) RespondDispute(c *gin.Context) {
+	actor, disputeID, ok := disputeParams(c)
+	if !ok {
+		return
+	}
+
+	var req RespondDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	d, err := h.service.Respond(c.Request.Context(), disputeID, actor.UserID, req.Response, req.OfferedAmount)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toDisputeResponse(d, nil))
+}
+
+// EscalateDispute hands a dispute to admins
+func (h *DisputeHandler) EscalateDispute(c *gin.Context) {
+	actor, disputeID, ok := disputeParams(c)
+	if !ok {
+		return
+	}
+
+	d, err := h.service.Escalate(c.Request.Context(), disputeID, actor.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toDisputeResponse(d, nil))
+}
+
+// AcceptDisputeResponse settles a dispute on the terms the seller offered
+func (h *DisputeHandler) AcceptDisputeResponse(c *gin.Context) {
+	actor, disputeID, ok := disputeParams(c)
+	if !ok {
+		return
+	}
+
+	d, err := h.service.AcceptResponse(c.Request.Context(), disputeID, actor.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toDisputeResponse(d, nil))
+}
+
+// ResolveDispute settles a dispute with a refund or in the seller's favor
+func (h *DisputeHandler) ResolveDispute(c *gin.Context) {
+	actor, disputeID, ok := disputeParams(c)
+	if !ok {
+		return
+	}
+
+	var req ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	d, err := h.service.Resolve(c.Request.Context(), disputeID, actor, disputeApp.ResolveRequest{
+		Outcome: disputeDomain.Outcome(req.Outcome),
+		Amount:  req.Amount,
+		Note:    req.Note,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toDisputeResponse(d, nil))
+}
+
+// AddEvidence adds a note and an optional file (multipart "file") to a dispute
+func (h *DisputeHandler) AddEvidence(c *gin.Context) {
+	actor, disputeID, ok := disputeParams(c)
+	if !ok {
+		return
+	}
+
+	req := disputeApp.AddEvidenceRequest{
+		DisputeID: disputeID,
+		Actor:     actor,
+		Note:      c.PostForm("note"),
+	}
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		req.File = file
+		req.Filename = header.Filename
+		req.Size = header.Size
+	}
+
+	e, err := h.service.AddEvidence(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toDisputeEvidenceResponse(e))
+}
+
+func (h *DisputeHandler) listDisputes(c *gin.Context, filter disputeDomain.Filter) {
+	result, err := h.service.ListDisputes(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	disputes := make([]*DisputeResponse, len(result.Disputes))
+	for i, d := range result.Disputes {
+		disputes[i] = toDisputeResponse(d, nil)
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"disputes":    disputes,
+		"total_count": result.TotalCount,
+		"page":        result.Page,
+		"page_size":   result.PageSize,
+	})
+}
+
+// Helper functions
+func disputeParams(c *gin.Context) (disputeApp.Actor, uuid.UUID, bool) {
+	userID, disputeID, ok := userAndIDParams(c, "invalid dispute id")
+	if !ok {
+		return disputeApp.Actor{}, uuid.Nil, false
+	}
+	return disputeApp.Actor{
+		UserID:  userID,
+		IsAdmin: c.GetString("user_role") == string(userDomain.RoleAdmin),
+	}, disputeID, true
+}
+
+func disputeFilter(c *gin.Context) disputeDomain.Filter {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	filter := disputeDomain.Filter{Page: page, PageSize: pageSize}
+	if s := c.Query("status"); s != "" {
+		status := disputeDomain.Status(s)
+		filter.Status = &status
+	}
+	return filter
+}
+
+func toDisputeResponse(d *disputeDomain.Dispute, evidence []*disputeDomain.Evidence) *DisputeResponse {
+	resp := &DisputeResponse{
+		ID:              d.ID.String(),
+		OrderID:         d.OrderID.String(),
+		BuyerID:         d.BuyerID.String(),
+		SellerID:        d.SellerID.String(),
+		Reason:          string(d.Reason),
+		Description:     d.Description,
+		RequestedAmount: d.RequestedAmount,
+		Status:          string(d.Status),
+		SellerResponse:  d.SellerResponse,
+		OfferedAmount:   d.OfferedAmount,
+		RespondedAt:     d.RespondedAt,
+		DeadlineAt:      d.DeadlineAt,
+		EscalatedAt:     d.EscalatedAt,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+
+	if r := d.Resolution; r != nil {
+		resp.Resolution = &DisputeResolution{
+			Outcome:      string(r.Outcome),
+			RefundAmount: r.RefundAmount,
+			ResolvedBy:   r.ResolvedBy.String(),
+			Note:         r.Note,
+			ResolvedAt:   r.ResolvedAt,
+		}
+		if r.RefundID != nil {
+			refundID := r.RefundID.String()
+			resp.Resolution.RefundID = &refundID
+		}
+	}
+
+	if evidence != nil {
+		resp.Evidence = make([]*DisputeEvidenceResponse, len(evidence))
+		for i, e := range evidence {
+			resp.Evidence[i] = toDisputeEvidenceResponse(e)
+		}
+	}
+
+	return resp
+}
+
+func toDisputeEvidenceResponse(e *disputeDomain.Evidence) *DisputeEvidenceResponse {
+	return &DisputeEvidenceResponse{
+		ID:          e.ID.String(),
+		AuthorID:    e.AuthorID.String(),
+		Note:        e.Note,
+		FileURL:     e.FileURL,
+		ContentType: e.ContentType,
+		CreatedAt:   e.CreatedAt,
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	flashsaleApp "github.com/blytz/live/backend/internal/application/flashsale"
+	flashsaleDomain "github.com/blytz/live/backend/internal/domain/flashsale"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FlashSaleHandler handles flash sale HTTP requests
+type FlashSaleHandler struct {
+	service *flashsaleApp.Service
+}
+
+// NewFlashSaleHandler creates a new flash sale handler
+func NewFlashSaleHandler(service *flashsaleApp.Service) *FlashSaleHandler {
+	return &FlashSaleHandler{service: service}
+}
+
+// FlashSaleRequest represents flash sale create/update request
+type FlashSaleRequest struct {
+	ProductID    string    `json:"product_id"`
+	SalePrice    float64   `json:"sale_price" binding:"required,gt=0"`
+	Quantity     int       `json:"quantity" binding:"required,gt=0"`
+	PerUserLimit *int      `json:"per_user_limit"`
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	EndsAt       time.Time `json:"ends_at" binding:"required"`
+}
+
+// FlashSaleResponse represents flash sale response
+type FlashSaleResponse struct {
+	ID           string    `json:"id"`
+	ProductID    string    `json:"product_id"`
+	SalePrice    float64   `json:"sale_price"`
+	Quantity     int       `json:"quantity"`
+	SoldCount    int       `json:"sold_count"`
+	Remaining    int       `json:"remaining"`
+	PerUserLimit *int      `json:"per_user_limit,omitempty"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// FlashSaleFeedItem represents a running sale in the active feed. EndsInSeconds
+// drives the countdown relative to the response's server time.
+type FlashSaleFeedItem struct {
+	FlashSaleResponse
+	ProductName   string  `json:"product_name"`
+	ImageURL      string  `json:"image_url,omitempty"`
+	RegularPrice  float64 `json:"regular_price"`
+	EndsInSeconds int64   `json:"ends_in_seconds"`
+}
+
+// ActiveFeed lists the flash sales running now, ending soonest first
+func (h *FlashSaleHandler) ActiveFeed(c *gin.Context) {
+	feed, err := h.service.ActiveFeed(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	now := time.Now()
+	items := make([]*FlashSaleFeedItem, len(feed))
+	for i, f := range feed {
+		item := &FlashSaleFeedItem{
+			FlashSaleResponse: *toFlashSaleResponse(f.Sale),
+			ProductName:       f.Product.Name,
+			RegularPrice:      f.Product.BasePrice,
+			EndsInSeconds:     int64(f.Sale.EndsAt.Sub(now).Seconds()),
+		}
+		if img := f.Product.GetPrimaryImage(); img != nil {
+			item.ImageURL = img.URL
+		}
+		items[i] = item
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"flash_sales": items,
+		"server_time": now,
+	})
+}
+
+// ListSellerSales lists the seller's flash sales
+func (h *FlashSaleHandler) ListSellerSales(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	sales, err := h.service.ListSellerSales(c.Request.Context(), sellerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*FlashSaleResponse, len(sales))
+	for i, sale := range sales {
+		responses[i] = toFlashSaleResponse(sale)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// CreateSale schedules a flash sale for one of the seller's products
+func (h *FlashSaleHandler) CreateSale(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req FlashSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid product_id"))
+		return
+	}
+
+	sale := req.toDomain()
+	sale.ProductID = productID
+
+	created, err := h.service.CreateSale(c.Request.Context(), sellerID, sale)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toFlashSaleResponse(created))
+}
+
+// UpdateSale changes a flash sale before it starts
+func (h *FlashSaleHandler) UpdateSale(c *gin.Context) {
+	sellerID, saleID, ok := userAndIDParams(c, "invalid flash sale id")
+	if !ok {
+		return
+	}
+
+	var req FlashSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	updated, err := h.service.UpdateSale(c.Request.Context(), sellerID, saleID, req.toDomain())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toFlashSaleResponse(updated))
+}
+
+// DeleteSale removes a flash sale before it starts
+func (h *FlashSaleHandler) DeleteSale(c *gin.Context) {
+	sellerID, saleID, ok := userAndIDParams(c, "invalid flash sale id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSale(c.Request.Context(), sellerID, saleID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "flash sale deleted"})
+}
+
+// Helper functions
+func (r *FlashSaleRequest) toDomain() *flashsaleDomain.Sale {
+	return &flashsaleDomain.Sale{
+		SalePrice:    r.SalePrice,
+		Quantity:     r.Quantity,
+		PerUserLimit: r.PerUserLimit,
+		StartsAt:     r.StartsAt,
+		EndsAt:       r.EndsAt,
+	}
+}
+
+func toFlashSaleResponse(s *flashsaleDomain.Sale) *FlashSaleResponse {
+	return &FlashSaleResponse{
+		ID:           s.ID.String(),
+		ProductID:    s.ProductID.String(),
+		SalePrice:    s.SalePrice,
+		Quantity:     s.Quantity,
+		SoldCount:    s.SoldCount,
+		Remaining:    s.Remaining(),
+		PerUserLimit: s.PerUserLimit,
+		StartsAt:     s.StartsAt,
+		EndsAt:       s.EndsAt,
+		CreatedAt:    s.CreatedAt,
+	}
+}
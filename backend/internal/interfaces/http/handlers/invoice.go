@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	invoiceApp "github.com/blytz/live/backend/internal/application/invoice"
+	invoiceDomain "github.com/blytz/live/backend/internal/domain/invoice"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// InvoiceHandler handles invoice HTTP requests
+type InvoiceHandler struct {
+	service *invoiceApp.Service
+}
+
+// NewInvoiceHandler creates a new invoice handler
+func NewInvoiceHandler(service *invoiceApp.Service) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+// InvoiceResponse represents invoice response, also used for accounting exports
+type InvoiceResponse struct {
+	ID           string                `json:"id"`
+	Number       string                `json:"number"`
+	OrderID      string                `json:"order_id"`
+	Seller       invoiceDomain.Party   `json:"seller"`
+	Buyer        invoiceDomain.Party   `json:"buyer"`
+	Lines        []invoiceDomain.Line  `json:"lines"`
+	Subtotal     float64               `json:"subtotal"`
+	Discount     float64               `json:"discount"`
+	CouponCode   string                `json:"coupon_code,omitempty"`
+	ShippingCost float64               `json:"shipping_cost"`
+	TaxAmount    float64               `json:"tax_amount"`
+	TaxInclusive bool                  `json:"tax_inclusive"`
+	TaxSummary   []InvoiceTaxBreakdown `json:"tax_summary"`
+	Total        float64               `json:"total"`
+	PaidAt       time.Time             `json:"paid_at"`
+	IssuedAt     time.Time             `json:"issued_at"`
+}
+
+// InvoiceTaxBreakdown totals an invoice's tax by jurisdiction and rate
+type InvoiceTaxBreakdown struct {
+	Jurisdiction string  `json:"jurisdiction"`
+	RateBps      int     `json:"rate_bps"`
+	TaxableBase  float64 `json:"taxable_base"`
+	TaxAmount    float64 `json:"tax_amount"`
+}
+
+// GetInvoice returns an order's invoice as a PDF, or as JSON with ?format=json
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("format") == "json" {
+		inv, err := h.service.GetInvoice(c.Request.Context(), orderID, userID)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		respondJSON(c, http.StatusOK, toInvoiceResponse(inv))
+		return
+	}
+
+	doc, err := h.service.GetDocument(c.Request.Context(), orderID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", doc.Invoice.Number+".pdf"))
+	c.Data(http.StatusOK, doc.ContentType, doc.Data)
+}
+
+// ExportInvoices exports the seller's invoices issued between from and to
+// (YYYY-MM-DD, to exclusive) for accounting tools. Defaults to the current month.
+func (h *InvoiceHandler) ExportInvoices(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse("2006-01-02", v); err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, "invalid from date, expected YYYY-MM-DD"))
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse("2006-01-02", v); err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, "invalid to date, expected YYYY-MM-DD"))
+			return
+		}
+	}
+
+	invoices, err := h.service.ExportSellerInvoices(c.Request.Context(), sellerID, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*InvoiceResponse, len(invoices))
+	for i, inv := range invoices {
+		responses[i] = toInvoiceResponse(inv)
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"from":     from.Format("2006-01-02"),
+		"to":       to.Format("2006-01-02"),
+		"invoices": responses,
+	})
+}
+
+// Helper functions
+func toInvoiceResponse(inv *invoiceDomain.Invoice) *InvoiceResponse {
+	resp := &InvoiceResponse{
+		ID:           inv.ID.String(),
+		Number:       inv.Number,
+		OrderID:      inv.OrderID.String(),
+		Seller:       inv.Seller,
+		Buyer:        inv.Buyer,
+		Lines:        inv.Lines,
+		Subtotal:     inv.Subtotal,
+		Discount:     inv.Discount,
+		CouponCode:   inv.CouponCode,
+		ShippingCost: inv.ShippingCost,
+		TaxAmount:    inv.TaxAmount,
+		TaxInclusive: inv.TaxInclusive,
+		TaxSummary:   []InvoiceTaxBreakdown{},
+		Total:        inv.Total,
+		PaidAt:       inv.PaidAt,
+		IssuedAt:     inv.IssuedAt,
+	}
+
+	index := make(map[string]int)
+	for _, line := range inv.Lines {
+		key := fmt.Sprintf("%s/%d", line.TaxJurisdiction, line.TaxRateBps)
+		i, ok := index[key]
+		if !ok {
+			i = len(resp.TaxSummary)
+			index[key] = i
+			resp.TaxSummary = append(resp.TaxSummary, InvoiceTaxBreakdown{
+				Jurisdiction: line.TaxJurisdiction,
+				RateBps:      line.TaxRateBps,
+			})
+		}
+		resp.TaxSummary[i].TaxableBase += line.Total
+		resp.TaxSummary[i].TaxAmount += line.TaxAmount
+	}
+
+	return resp
+}
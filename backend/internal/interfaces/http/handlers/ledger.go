@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	ledgerApp "github.com/blytz/live/backend/internal/application/ledger"
+	ledgerDomain "github.com/blytz/live/backend/internal/domain/ledger"
+	paymentDomain "github.com/blytz/live/backend/internal/domain/payment"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LedgerHandler handles seller balance, payout and fee rule HTTP requests
+type LedgerHandler struct {
+	service *ledgerApp.Service
+}
+
+// NewLedgerHandler creates a new ledger handler
+func NewLedgerHandler(service *ledgerApp.Service) *LedgerHandler {
+	return &LedgerHandler{service: service}
+}
+
+// CreateFeeRuleRequest represents fee rule creation request
+type CreateFeeRuleRequest struct {
+	CategoryID  *string `json:"category_id"`
+	SellerTier  string  `json:"seller_tier"`
+	PercentBps  int     `json:"percent_bps" binding:"gte=0,lte=10000"`
+	FixedAmount float64 `json:"fixed_amount" binding:"gte=0"`
+}
+
+// BalanceResponse represents seller balance response
+type BalanceResponse struct {
+	Pending   float64 `json:"pending"`
+	Available float64 `json:"available"`
+	PaidOut   float64 `json:"paid_out"`
+}
+
+// LedgerEntryResponse represents a line of a seller's transaction history
+type LedgerEntryResponse struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	Reference   string    `json:"reference"`
+	OrderID     *string   `json:"order_id,omitempty"`
+	Description string    `json:"description"`
+	Account     string    `json:"account"`
+	Amount      float64   `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PayoutResponse represents payout response
+type PayoutResponse struct {
+	ID                string    `json:"id"`
+	Amount            float64   `json:"amount"`
+	Currency          string    `json:"currency"`
+	Status            string    `json:"status"`
+	Provider          string    `json:"provider"`
+	ProviderReference string    `json:"provider_reference,omitempty"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// FeeRuleResponse represents fee rule response
+type FeeRuleResponse struct {
+	ID          string    `json:"id"`
+	CategoryID  *string   `json:"category_id,omitempty"`
+	SellerTier  string    `json:"seller_tier,omitempty"`
+	PercentBps  int       `json:"percent_bps"`
+	FixedAmount float64   `json:"fixed_amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// GetBalance gets the seller's balance
+func (h *LedgerHandler) GetBalance(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	b, err := h.service.GetSellerBalance(c.Request.Context(), sellerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, &BalanceResponse{
+		Pending:   paymentDomain.FromMinorUnits(b.Pending),
+		Available: paymentDomain.FromMinorUnits(b.Available),
+		PaidOut:   paymentDomain.FromMinorUnits(b.PaidOut),
+	})
+}
+
+// ListTransactions lists the seller's ledger entries
+func (h *LedgerHandler) ListTransactions(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	lines, total, err := h.service.ListSellerTransactions(c.Request.Context(), sellerID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	entries := make([]*LedgerEntryResponse, len(lines))
+	for i, line := range lines {
+		entries[i] = toLedgerEntryResponse(line)
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"transactions": entries,
+		"total_count":  total,
+		"page":         page,
+		"page_size":    pageSize,
+	})
+}
+
+// ListPayouts lists the seller's payouts
+func (h *LedgerHandler) ListPayouts(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	payouts, total, err := h.service.ListSellerPayouts(c.Request.Context(), sellerID, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*PayoutResponse, len(payouts))
+	for i, p := range payouts {
+		responses[i] = toPayoutResponse(p)
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"payouts":     responses,
+		"total_count": total,
+		"page":        page,
+		"page_size":   pageSize,
+	})
+}
+
+// ListFeeRules lists the marketplace fee rules
+func (h *LedgerHandler) ListFeeRules(c *gin.Context) {
+	rules, err := h.service.ListFeeRules(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*FeeRuleResponse, len(rules))
+	for i, r := range rules {
+		responses[i] = toFeeRuleResponse(r)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// CreateFeeRule creates a fee rule
+func (h *LedgerHandler) CreateFeeRule(c *gin.Context) {
+	var req CreateFeeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	rule := &ledgerDomain.FeeRule{
+		SellerTier:  req.SellerTier,
+		PercentBps:  req.PercentBps,
+		FixedAmount: paymentDomain.ToMinorUnits(req.FixedAmount),
+	}
+	if req.CategoryID != nil {
+		categoryID, err := uuid.Parse(*req.CategoryID)
+		if err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, "invalid category_id"))
+			return
+		}
+		rule.CategoryID = &categoryID
+	}
+
+	rule, err := h.service.CreateFeeRule(c.Request.Context(), rule)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toFeeRuleResponse(rule))
+}
+
+// DeleteFeeRule deletes a fee rule
+func (h *LedgerHandler) DeleteFeeRule(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid fee rule id"))
+		return
+	}
+
+	if err := h.service.DeleteFeeRule(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "fee rule deleted"})
+}
+
+// Helper functions
+func toLedgerEntryResponse(line *ledgerDomain.StatementLine) *LedgerEntryResponse {
+	resp := &LedgerEntryResponse{
+		ID:          line.EntryID.String(),
+		Kind:        string(line.Kind),
+		Reference:   line.Reference.String(),
+		Description: line.Description,
+		Account:     string(line.Account),
+		Amount:      paymentDomain.FromMinorUnits(line.Amount),
+		CreatedAt:   line.CreatedAt,
+	}
+	if line.OrderID != nil {
+		orderID := line.OrderID.String()
+		resp.OrderID = &orderID
+	}
+	return resp
+}
+
+func toPayoutResponse(p *ledgerDomain.Payout) *PayoutResponse {
+	return &PayoutResponse{
+		ID:                p.ID.String(),
+		Amount:            paymentDomain.FromMinorUnits(p.Amount),
+		Currency:          p.Currency,
+		Status:            string(p.Status),
+		Provider:          p.Provider,
+		ProviderReference: p.ProviderReference,
+		FailureReason:     p.FailureReason,
+		CreatedAt:         p.CreatedAt,
+	}
+}
+
+func toFeeRuleResponse(r *ledgerDomain.FeeRule) *FeeRuleResponse {
+	resp := &FeeRuleResponse{
+		ID:          r.ID.String(),
+		SellerTier:  r.SellerTier,
+		PercentBps:  r.PercentBps,
+		FixedAmount: paymentDomain.FromMinorUnits(r.FixedAmount),
+		CreatedAt:   r.CreatedAt,
+	}
+	if r.CategoryID != nil {
+		categoryID := r.CategoryID.String()
+		resp.CategoryID = &categoryID
+	}
+	return resp
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	orderApp "github.com/blytz/live/backend/internal/application/order"
+	orderDomain "github.com/blytz/live/backend/internal/domain/order"
+	shippingDomain "github.com/blytz/live/backend/internal/domain/shipping"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OrderHandler handles order HTTP requests
+type OrderHandler struct {
+	service *orderApp.Service
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(service *orderApp.Service) *OrderHandler {
+	return &OrderHandler{service: service}
+}
+
+// CancelOrderRequest represents order cancellation request
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ShipOrderRequest represents order shipment request
+type ShipOrderRequest struct {
+	Carrier        string `json:"carrier" binding:"required"`
+	TrackingNumber string `json:"tracking_number" binding:"required"`
+}
+
+// ShippingAddressRequest represents order shipping address request. With
+// neither address_id nor address the user's default address is used.
+type ShippingAddressRequest struct {
+	AddressID string               `json:"address_id"`
+	Address   *orderDomain.Address `json:"address"`
+}
+
+// OrderResponse represents order response
+type OrderResponse struct {
+	ID              string               `json:"id"`
+	BuyerID         string               `json:"buyer_id"`
+	SellerID        string               `json:"seller_id"`
+	AuctionID       *string              `json:"auction_id,omitempty"`
+	Status          string               `json:"status"`
+	Items           []OrderItemResponse  `json:"items"`
+	Subtotal        float64              `json:"subtotal"`
+	TaxAmount       float64              `json:"tax_amount"`
+	TaxInclusive    bool                 `json:"tax_inclusive"`
+	ShippingCost    float64              `json:"shipping_cost"`
+	DiscountAmount  float64              `json:"discount_amount"`
+	CouponCode      string               `json:"coupon_code,omitempty"`
+	TotalAmount     float64              `json:"total_amount"`
+	ShippingAddress *orderDomain.Address `json:"shipping_address,omitempty"`
+	Carrier         string               `json:"carrier,omitempty"`
+	TrackingNumber  string               `json:"tracking_number,omitempty"`
+	TrackingURL     string               `json:"tracking_url,omitempty"`
+	CancelReason    string               `json:"cancel_reason,omitempty"`
+	PaymentDueAt    *time.Time           `json:"payment_due_at,omitempty"`
+	PaidAt          *time.Time           `json:"paid_at,omitempty"`
+	ShippedAt       *time.Time           `json:"shipped_at,omitempty"`
+	DeliveredAt     *time.Time           `json:"delivered_at,omitempty"`
+	CancelledAt     *time.Time           `json:"cancelled_at,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+}
+
+// OrderItemResponse represents order item response
+type OrderItemResponse struct {
+	ProductID       string  `json:"product_id"`
+	Quantity        int     `json:"quantity"`
+	UnitPrice       float64 `json:"unit_price"`
+	Total           float64 `json:"total"`
+	DiscountAmount  float64 `json:"discount_amount"`
+	TaxJurisdiction string  `json:"tax_jurisdiction,omitempty"`
+	TaxRateBps      int     `json:"tax_rate_bps"`
+	TaxAmount       float64 `json:"tax_amount"`
+}
+
+// ListOrders lists the current user's orders as a buyer
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	result, err := h.service.ListBuyerOrders(c.Request.Context(), userID, statusQuery(c), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toOrderListResponse(result))
+}
+
+// ListSellerOrders lists orders placed with the current seller
+func (h *OrderHandler) ListSellerOrders(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	result, err := h.service.ListSellerOrders(c.Request.Context(), userID, statusQuery(c), page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toOrderListResponse(result))
+}
+
+// GetOrder gets an order by ID
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	o, err := h.service.GetOrder(c.Request.Context(), orderID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toOrderResponse(o))
+}
+
+// CancelOrder cancels an unpaid order
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	var req CancelOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+			return
+		}
+	}
+
+	o, err := h.service.CancelOrder(c.Request.Context(), orderID, userID, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toOrderResponse(o))
+}
+
+// SetShippingAddress sets where an unpaid order ships
+func (h *OrderHandler) SetShippingAddress(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	var req ShippingAddressRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+			return
+		}
+	}
+
+	dest, err := destinationOf(req.AddressID, req.Address)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	o, err := h.service.SetShippingAddress(c.Request.Context(), orderID, userID, dest)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toOrderResponse(o))
+}
+
+// ShipOrder marks an order as shipped
+func (h *OrderHandler) ShipOrder(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	var req ShipOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	o, err := h.service.MarkShipped(c.Request.Context(), orderID, userID, req.Carrier, req.TrackingNumber)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toOrderResponse(o))
+}
+
+// ConfirmDelivery marks an order as delivered
+func (h *OrderHandler) ConfirmDelivery(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	o, err := h.service.MarkDelivered(c.Request.Context(), orderID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toOrderResponse(o))
+}
+
+// Helper functions
+func currentUserID(c *gin.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(viewerID(c))
+	if err != nil {
+		return uuid.Nil, appErrors.New(appErrors.ErrUnauthorized, "authentication required")
+	}
+	return userID, nil
+}
+
+func orderParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid order id"))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return orderID, userID, true
+}
+
+func statusQuery(c *gin.Context) *orderDomain.Status {
+	if s := c.Query("status"); s != "" {
+		status := orderDomain.Status(s)
+		return &status
+	}
+	return nil
+}
+
+func toOrderResponse(o *orderDomain.Order) *OrderResponse {
+	resp := &OrderResponse{
+		ID:              o.ID.String(),
+		BuyerID:         o.BuyerID.String(),
+		SellerID:        o.SellerID.String(),
+		Status:          string(o.Status),
+		Items:           make([]OrderItemResponse, len(o.Items)),
+		Subtotal:        o.Subtotal,
+		TaxAmount:       o.TaxAmount,
+		TaxInclusive:    o.TaxInclusive,
+		ShippingCost:    o.ShippingCost,
+		DiscountAmount:  o.DiscountAmount,
+		CouponCode:      o.CouponCode,
+		TotalAmount:     o.TotalAmount,
+		ShippingAddress: o.ShippingAddress,
+		Carrier:         o.Carrier,
+		TrackingNumber:  o.TrackingNumber,
+		TrackingURL:     shippingDomain.TrackingURL(o.Carrier, o.TrackingNumber),
+		CancelReason:    o.CancelReason,
+		PaymentDueAt:    o.PaymentDueAt,
+		PaidAt:          o.PaidAt,
+		ShippedAt:       o.ShippedAt,
+		DeliveredAt:     o.DeliveredAt,
+		CancelledAt:     o.CancelledAt,
+		CreatedAt:       o.CreatedAt,
+	}
+
+	if o.AuctionID != nil {
+		auctionID := o.AuctionID.String()
+		resp.AuctionID = &auctionID
+	}
+
+	for i, item := range o.Items {
+		resp.Items[i] = OrderItemResponse{
+			ProductID:       item.ProductID.String(),
+			Quantity:        item.Quantity,
+			UnitPrice:       item.UnitPrice,
+			Total:           item.Total,
+			DiscountAmount:  item.DiscountAmount,
+			TaxJurisdiction: item.TaxJurisdiction,
+			TaxRateBps:      item.TaxRateBps,
+			TaxAmount:       item.TaxAmount,
+		}
+	}
+
+	return resp
+}
+
+func toOrderListResponse(result *orderApp.ListResult) gin.H {
+	orders := make([]*OrderResponse, len(result.Orders))
+	for i, o := range result.Orders {
+		orders[i] = toOrderResponse(o)
+	}
+
+	return gin.H{
+		"orders":      orders,
+		"total_count": result.TotalCount,
+		"page":        result.Page,
+		"page_size":   result.PageSize,
+	}
+}
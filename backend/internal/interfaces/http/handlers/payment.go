@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	paymentApp "github.com/blytz/live/backend/internal/application/payment"
+	paymentDomain "github.com/blytz/live/backend/internal/domain/payment"
+	userDomain "github.com/blytz/live/backend/internal/domain/user"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxWebhookSize bounds webhook request bodies
+const maxWebhookSize = 1 << 20
+
+// PaymentHandler handles payment HTTP requests
+type PaymentHandler struct {
+	service *paymentApp.Service
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(service *paymentApp.Service) *PaymentHandler {
+	return &PaymentHandler{service: service}
+}
+
+// PayOrderRequest represents order payment request. With neither field set
+// the buyer's default saved method is used.
+type PayOrderRequest struct {
+	PaymentMethod   string `json:"payment_method"`
+	PaymentMethodID string `json:"payment_method_id"`
+}
+
+// AddPaymentMethodRequest represents saved payment method creation request
+type AddPaymentMethodRequest struct {
+	Token       string `json:"token" binding:"required"`
+	MakeDefault bool   `json:"make_default"`
+}
+
+// PaymentMethodResponse represents saved payment method response
+type PaymentMethodResponse struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	Provider     string     `json:"provider"`
+	Last4        string     `json:"last4"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	IsDefault    bool       `json:"is_default"`
+	Expired      bool       `json:"expired"`
+	ExpiringSoon bool       `json:"expiring_soon"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// RefundOrderRequest represents order refund request
+type RefundOrderRequest struct {
+	Amount  float64              `json:"amount" binding:"gte=0"`
+	Reason  string               `json:"reason" binding:"required"`
+	Restock []RestockItemRequest `json:"restock"`
+}
+
+// RestockItemRequest represents a quantity of a product to return to stock
+type RestockItemRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// RefundResponse represents refund response
+type RefundResponse struct {
+	ID          string                      `json:"id"`
+	OrderID     string                      `json:"order_id"`
+	PaymentID   string                      `json:"payment_id"`
+	Amount      float64                     `json:"amount"`
+	Reason      string                      `json:"reason"`
+	InitiatedBy string                      `json:"initiated_by"`
+	Status      string                      `json:"status"`
+	Restocked   []paymentDomain.RestockItem `json:"restocked"`
+	CreatedAt   time.Time                   `json:"created_at"`
+}
+
+// PaymentResponse represents payment response
+type PaymentResponse struct {
+	ID             string     `json:"id"`
+	OrderID        string     `json:"order_id"`
+	Amount         float64    `json:"amount"`
+	Currency       string     `json:"currency"`
+	Status         string     `json:"status"`
+	Provider       string     `json:"provider"`
+	FailureReason  string     `json:"failure_reason,omitempty"`
+	RefundedAmount float64    `json:"refunded_amount"`
+	RefundedAt     *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PayOrder pays for an order
+func (h *PaymentHandler) PayOrder(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	var req PayOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	payReq := paymentApp.PayOrderRequest{
+		OrderID:        orderID,
+		BuyerID:        userID,
+		MethodRef:      req.PaymentMethod,
+		IdempotencyKey: c.GetHeader("Idempotency-Key"),
+	}
+	if req.PaymentMethodID != "" {
+		methodID, err := uuid.Parse(req.PaymentMethodID)
+		if err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, "invalid payment_method_id"))
+			return
+		}
+		payReq.MethodID = &methodID
+	}
+
+	p, err := h.service.PayOrder(c.Request.Context(), payReq)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toPaymentResponse(p))
+}
+
+// ListPayments lists the payments of an order
+func (h *PaymentHandler) ListPayments(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	payments, err := h.service.ListOrderPayments(c.Request.Context(), orderID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*PaymentResponse, len(payments))
+	for i, p := range payments {
+		responses[i] = toPaymentResponse(p)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// RefundOrder refunds all or part of an order
+func (h *PaymentHandler) RefundOrder(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	var req RefundOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	restock := make([]paymentDomain.RestockItem, len(req.Restock))
+	for i, item := range req.Restock {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, "invalid product_id"))
+			return
+		}
+		restock[i] = paymentDomain.RestockItem{ProductID: productID, Quantity: item.Quantity}
+	}
+
+	refund, err := h.service.RefundOrder(c.Request.Context(), paymentApp.RefundOrderRequest{
+		OrderID:        orderID,
+		ActorID:        userID,
+		IsAdmin:        c.GetString("user_role") == string(userDomain.RoleAdmin),
+		Amount:         req.Amount,
+		Reason:         req.Reason,
+		Restock:        restock,
+		IdempotencyKey: c.GetHeader("Idempotency-Key"),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toRefundResponse(refund))
+}
+
+// ListRefunds lists the refunds of an order
+func (h *PaymentHandler) ListRefunds(c *gin.Context) {
+	orderID, userID, ok := orderParams(c)
+	if !ok {
+		return
+	}
+
+	refunds, err := h.service.ListOrderRefunds(c.Request.Context(), orderID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*RefundResponse, len(refunds))
+	for i, r := range refunds {
+		responses[i] = toRefundResponse(r)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// ListPaymentMethods lists the user's saved payment methods
+func (h *PaymentHandler) ListPaymentMethods(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	methods, err := h.service.ListPaymentMethods(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	now := time.Now()
+	responses := make([]*PaymentMethodResponse, len(methods))
+	for i, m := range methods {
+		responses[i] = toPaymentMethodResponse(m, now)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// AddPaymentMethod saves a tokenized payment method
+func (h *PaymentHandler) AddPaymentMethod(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req AddPaymentMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	m, err := h.service.AddPaymentMethod(c.Request.Context(), userID, req.Token, req.MakeDefault)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toPaymentMethodResponse(m, time.Now()))
+}
+
+// SetDefaultPaymentMethod makes a saved payment method the default
+func (h *PaymentHandler) SetDefaultPaymentMethod(c *gin.Context) {
+	userID, methodID, ok := paymentMethodParams(c)
+	if !ok {
+		return
+	}
+
+	m, err := h.service.SetDefaultPaymentMethod(c.Request.Context(), userID, methodID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toPaymentMethodResponse(m, time.Now()))
+}
+
+// RemovePaymentMethod removes a saved payment method
+func (h *PaymentHandler) RemovePaymentMethod(c *gin.Context) {
+	userID, methodID, ok := paymentMethodParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemovePaymentMethod(c.Request.Context(), userID, methodID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "payment method removed"})
+}
+
+// Webhook receives payment provider notifications
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookSize))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "failed to read body"))
+		return
+	}
+
+	signature := c.GetHeader("Stripe-Signature")
+	if signature == "" {
+		signature = c.GetHeader("X-Webhook-Signature")
+	}
+
+	if err := h.service.HandleWebhook(c.Request.Context(), payload, signature); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"received": true})
+}
+
+// Helper functions
+func toPaymentResponse(p *paymentDomain.Payment) *PaymentResponse {
+	return &PaymentResponse{
+		ID:             p.ID.String(),
+		OrderID:        p.OrderID.String(),
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		Status:         string(p.Status),
+		Provider:       p.Provider,
+		FailureReason:  p.FailureReason,
+		RefundedAmount: p.RefundedAmount,
+		RefundedAt:     p.RefundedAt,
+		CreatedAt:      p.CreatedAt,
+	}
+}
+
+func toRefundResponse(r *paymentDomain.Refund) *RefundResponse {
+	restocked := r.Restocked
+	if restocked == nil {
+		restocked = []paymentDomain.RestockItem{}
+	}
+	return &RefundResponse{
+		ID:          r.ID.String(),
+		OrderID:     r.OrderID.String(),
+		PaymentID:   r.PaymentID.String(),
+		Amount:      r.Amount,
+		Reason:      r.Reason,
+		InitiatedBy: r.InitiatedBy.String(),
+		Status:      string(r.Status),
+		Restocked:   restocked,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+func toPaymentMethodResponse(m *paymentDomain.Method, now time.Time) *PaymentMethodResponse {
+	return &PaymentMethodResponse{
+		ID:           m.ID.String(),
+		Type:         m.Type,
+		Provider:     m.Provider,
+		Last4:        m.Last4,
+		ExpiresAt:    m.ExpiresAt,
+		IsDefault:    m.IsDefault,
+		Expired:      m.IsExpired(now),
+		ExpiringSoon: m.IsExpiringSoon(now),
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func paymentMethodParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	methodID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid payment method id"))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, methodID, true
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	promotionApp "github.com/blytz/live/backend/internal/application/promotion"
+	promotionDomain "github.com/blytz/live/backend/internal/domain/promotion"
+	userDomain "github.com/blytz/live/backend/internal/domain/user"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PromotionHandler handles coupon management HTTP requests. Admins manage
+// platform-wide coupons, sellers coupons for their own products.
+type PromotionHandler struct {
+	service *promotionApp.Service
+}
+
+// NewPromotionHandler creates a new promotion handler
+func NewPromotionHandler(service *promotionApp.Service) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+// CouponRequest represents coupon create/update request
+type CouponRequest struct {
+	Code           string     `json:"code"`
+	Description    string     `json:"description"`
+	Type           string     `json:"type" binding:"required,oneof=percentage fixed"`
+	PercentBps     int        `json:"percent_bps"`
+	Amount         float64    `json:"amount"`
+	MaxDiscount    *float64   `json:"max_discount"`
+	MinOrderAmount float64    `json:"min_order_amount"`
+	ProductIDs     []string   `json:"product_ids"`
+	CategoryIDs    []string   `json:"category_ids"`
+	UsageLimit     *int       `json:"usage_limit"`
+	PerUserLimit   *int       `json:"per_user_limit"`
+	StartsAt       *time.Time `json:"starts_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	Active         *bool      `json:"active"`
+}
+
+// CouponResponse represents coupon response
+type CouponResponse struct {
+	ID             string     `json:"id"`
+	Code           string     `json:"code"`
+	Description    string     `json:"description,omitempty"`
+	SellerID       *string    `json:"seller_id,omitempty"`
+	Type           string     `json:"type"`
+	PercentBps     int        `json:"percent_bps,omitempty"`
+	Amount         float64    `json:"amount,omitempty"`
+	MaxDiscount    *float64   `json:"max_discount,omitempty"`
+	MinOrderAmount float64    `json:"min_order_amount"`
+	ProductIDs     []string   `json:"product_ids"`
+	CategoryIDs    []string   `json:"category_ids"`
+	UsageLimit     *int       `json:"usage_limit,omitempty"`
+	PerUserLimit   *int       `json:"per_user_limit,omitempty"`
+	UsedCount      int        `json:"used_count"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ListCoupons lists the coupons the caller manages
+func (h *PromotionHandler) ListCoupons(c *gin.Context) {
+	actor, err := couponActor(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	result, err := h.service.ListCoupons(c.Request.Context(), actor, page, pageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	coupons := make([]*CouponResponse, len(result.Coupons))
+	for i, coupon := range result.Coupons {
+		coupons[i] = toCouponResponse(coupon)
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"coupons":     coupons,
+		"total_count": result.TotalCount,
+		"page":        result.Page,
+		"page_size":   result.PageSize,
+	})
+}
+
+// CreateCoupon creates a coupon
+func (h *PromotionHandler) CreateCoupon(c *gin.Context) {
+	actor, err := couponActor(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	coupon, err := bindCoupon(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	created, err := h.service.CreateCoupon(c.Request.Context(), actor, coupon)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toCouponResponse(created))
+}
+
+// UpdateCoupon replaces a coupon's settings
+func (h *PromotionHandler) UpdateCoupon(c *gin.Context) {
+	actor, couponID, ok := couponParams(c)
+	if !ok {
+		return
+	}
+
+	coupon, err := bindCoupon(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	updated, err := h.service.UpdateCoupon(c.Request.Context(), actor, couponID, coupon)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toCouponResponse(updated))
+}
+
+// DeleteCoupon deletes a coupon
+func (h *PromotionHandler) DeleteCoupon(c *gin.Context) {
+	actor, couponID, ok := couponParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCoupon(c.Request.Context(), actor, couponID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "coupon deleted"})
+}
+
+// Helper functions
+func couponActor(c *gin.Context) (promotionApp.Actor, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return promotionApp.Actor{}, err
+	}
+	return promotionApp.Actor{
+		UserID:  userID,
+		IsAdmin: c.GetString("user_role") == string(userDomain.RoleAdmin),
+	}, nil
+}
+
+func couponParams(c *gin.Context) (promotionApp.Actor, uuid.UUID, bool) {
+	actor, err := couponActor(c)
+	if err != nil {
+		respondError(c, err)
+		return promotionApp.Actor{}, uuid.Nil, false
+	}
+
+	couponID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, "invalid coupon id"))
+		return promotionApp.Actor{}, uuid.Nil, false
+	}
+	return actor, couponID, true
+}
+
+func bindCoupon(c *gin.Context) (*promotionDomain.Coupon, error) {
+	var req CouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, appErrors.New(appErrors.ErrValidation, err.Error())
+	}
+
+	productIDs, err := parseUUIDs(req.ProductIDs)
+	if err != nil {
+		return nil, appErrors.New(appErrors.ErrValidation, "invalid product_ids")
+	}
+	categoryIDs, err := parseUUIDs(req.CategoryIDs)
+	if err != nil {
+		return nil, appErrors.New(appErrors.ErrValidation, "invalid category_ids")
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	return &promotionDomain.Coupon{
+		Code:           req.Code,
+		Description:    req.Description,
+		Type:           promotionDomain.DiscountType(req.Type),
+		PercentBps:     req.PercentBps,
+		Amount:         req.Amount,
+		MaxDiscount:    req.MaxDiscount,
+		MinOrderAmount: req.MinOrderAmount,
+		ProductIDs:     productIDs,
+		CategoryIDs:    categoryIDs,
+		UsageLimit:     req.UsageLimit,
+		PerUserLimit:   req.PerUserLimit,
+		StartsAt:       req.StartsAt,
+		ExpiresAt:      req.ExpiresAt,
+		Active:         active,
+	}, nil
+}
+
+func parseUUIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(values))
+	for i, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
+
+func toCouponResponse(coupon *promotionDomain.Coupon) *CouponResponse {
+	resp := &CouponResponse{
+		ID:             coupon.ID.String(),
+		Code:           coupon.Code,
+		Description:    coupon.Description,
+		Type:           string(coupon.Type),
+		PercentBps:     coupon.PercentBps,
+		Amount:         coupon.Amount,
+		MaxDiscount:    coupon.MaxDiscount,
+		MinOrderAmount: coupon.MinOrderAmount,
+		ProductIDs:     uuidStrings(coupon.ProductIDs),
+		CategoryIDs:    uuidStrings(coupon.CategoryIDs),
+		UsageLimit:     coupon.UsageLimit,
+		PerUserLimit:   coupon.PerUserLimit,
+		UsedCount:      coupon.UsedCount,
+		StartsAt:       coupon.StartsAt,
+		ExpiresAt:      coupon.ExpiresAt,
+		Active:         coupon.Active,
+		CreatedAt:      coupon.CreatedAt,
+		UpdatedAt:      coupon.UpdatedAt,
+	}
+
+	if coupon.SellerID != nil {
+		sellerID := coupon.SellerID.String()
+		resp.SellerID = &sellerID
+	}
+
+	return resp
+}
@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	shippingApp "github.com/blytz/live/backend/internal/application/shipping"
+	orderDomain "github.com/blytz/live/backend/internal/domain/order"
+	shippingDomain "github.com/blytz/live/backend/internal/domain/shipping"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ShippingHandler handles address book, shipping profile and shipping quote HTTP requests
+type ShippingHandler struct {
+	service *shippingApp.Service
+}
+
+// NewShippingHandler creates a new shipping handler
+func NewShippingHandler(service *shippingApp.Service) *ShippingHandler {
+	return &ShippingHandler{service: service}
+}
+
+// AddressRequest represents address book entry create/update request
+type AddressRequest struct {
+	Label       string              `json:"label"`
+	Address     orderDomain.Address `json:"address" binding:"required"`
+	MakeDefault bool                `json:"make_default"`
+}
+
+// AddressResponse represents address book entry response
+type AddressResponse struct {
+	ID        string              `json:"id"`
+	Label     string              `json:"label,omitempty"`
+	Address   orderDomain.Address `json:"address"`
+	IsDefault bool                `json:"is_default"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// ShippingProfileRequest represents shipping profile create/update request
+type ShippingProfileRequest struct {
+	Name           string                `json:"name" binding:"required"`
+	Countries      []string              `json:"countries"`
+	RateType       string                `json:"rate_type" binding:"required,oneof=flat weight_tiered"`
+	FlatRate       float64               `json:"flat_rate" binding:"gte=0"`
+	Tiers          []shippingDomain.Tier `json:"tiers"`
+	FreeOverAmount *float64              `json:"free_over_amount"`
+}
+
+// ShippingProfileResponse represents shipping profile response
+type ShippingProfileResponse struct {
+	ID             string                `json:"id"`
+	Name           string                `json:"name"`
+	Countries      []string              `json:"countries"`
+	RateType       string                `json:"rate_type"`
+	FlatRate       float64               `json:"flat_rate"`
+	Tiers          []shippingDomain.Tier `json:"tiers"`
+	FreeOverAmount *float64              `json:"free_over_amount,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// ShippingQuoteRequest represents shipping quote request. With neither
+// address_id nor address the user's default address is used.
+type ShippingQuoteRequest struct {
+	Items     []ShippingQuoteItem  `json:"items" binding:"required,min=1,dive"`
+	AddressID string               `json:"address_id"`
+	Address   *orderDomain.Address `json:"address"`
+}
+
+// ShippingQuoteItem represents a product to quote shipping for
+type ShippingQuoteItem struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// ShippingQuoteResponse represents one seller's shipping quote
+type ShippingQuoteResponse struct {
+	SellerID            string  `json:"seller_id"`
+	ProfileID           *string `json:"profile_id,omitempty"`
+	ProfileName         string  `json:"profile_name,omitempty"`
+	BillableWeightGrams int     `json:"billable_weight_grams"`
+	Cost                float64 `json:"cost"`
+}
+
+// CarrierResponse represents a supported carrier
+type CarrierResponse struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// ListAddresses lists the user's address book
+func (h *ShippingHandler) ListAddresses(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	addresses, err := h.service.ListAddresses(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*AddressResponse, len(addresses))
+	for i, a := range addresses {
+		responses[i] = toAddressResponse(a)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// AddAddress adds an address to the user's address book
+func (h *ShippingHandler) AddAddress(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	a, err := h.service.AddAddress(c.Request.Context(), userID, req.Label, req.Address, req.MakeDefault)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toAddressResponse(a))
+}
+
+// UpdateAddress updates an address book entry
+func (h *ShippingHandler) UpdateAddress(c *gin.Context) {
+	userID, addressID, ok := addressParams(c)
+	if !ok {
+		return
+	}
+
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	a, err := h.service.UpdateAddress(c.Request.Context(), userID, addressID, req.Label, req.Address)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toAddressResponse(a))
+}
+
+// SetDefaultAddress makes an address book entry the default
+func (h *ShippingHandler) SetDefaultAddress(c *gin.Context) {
+	userID, addressID, ok := addressParams(c)
+	if !ok {
+		return
+	}
+
+	a, err := h.service.SetDefaultAddress(c.Request.Context(), userID, addressID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toAddressResponse(a))
+}
+
+// DeleteAddress removes an address book entry
+func (h *ShippingHandler) DeleteAddress(c *gin.Context) {
+	userID, addressID, ok := addressParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAddress(c.Request.Context(), userID, addressID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "address deleted"})
+}
+
+// ListProfiles lists the seller's shipping profiles
+func (h *ShippingHandler) ListProfiles(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	profiles, err := h.service.ListProfiles(c.Request.Context(), sellerID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*ShippingProfileResponse, len(profiles))
+	for i, p := range profiles {
+		responses[i] = toShippingProfileResponse(p)
+	}
+
+	respondJSON(c, http.StatusOK, responses)
+}
+
+// CreateProfile creates a shipping profile
+func (h *ShippingHandler) CreateProfile(c *gin.Context) {
+	sellerID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req ShippingProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	p, err := h.service.CreateProfile(c.Request.Context(), sellerID, req.toDomain())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, toShippingProfileResponse(p))
+}
+
+// UpdateProfile updates a shipping profile
+func (h *ShippingHandler) UpdateProfile(c *gin.Context) {
+	sellerID, profileID, ok := shippingProfileParams(c)
+	if !ok {
+		return
+	}
+
+	var req ShippingProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	p, err := h.service.UpdateProfile(c.Request.Context(), sellerID, profileID, req.toDomain())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toShippingProfileResponse(p))
+}
+
+// DeleteProfile deletes a shipping profile
+func (h *ShippingHandler) DeleteProfile(c *gin.Context) {
+	sellerID, profileID, ok := shippingProfileParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteProfile(c.Request.Context(), sellerID, profileID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "shipping profile deleted"})
+}
+
+// Quote quotes shipping for products, one quote per seller
+func (h *ShippingHandler) Quote(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req ShippingQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	dest, err := destinationOf(req.AddressID, req.Address)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items := make([]shippingApp.QuoteItem, len(req.Items))
+	for i, item := range req.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			respondError(c, appErrors.New(appErrors.ErrValidation, "invalid product_id"))
+			return
+		}
+		items[i] = shippingApp.QuoteItem{ProductID: productID, Quantity: item.Quantity}
+	}
+
+	quotes, err := h.service.QuoteItems(c.Request.Context(), userID, items, dest)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*ShippingQuoteResponse, len(quotes))
+	var total float64
+	for i, q := range quotes {
+		responses[i] = toShippingQuoteResponse(q)
+		total += q.Cost
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"quotes": responses,
+		"total":  total,
+	})
+}
+
+// ListCarriers lists the carriers orders can be shipped with
+func (h *ShippingHandler) ListCarriers(c *gin.Context) {
+	carriers := make([]CarrierResponse, 0, len(shippingDomain.Carriers))
+	for _, carrier := range shippingDomain.Carriers {
+		carriers = append(carriers, CarrierResponse{Code: carrier.Code, Name: carrier.Name})
+	}
+	sort.Slice(carriers, func(i, j int) bool { return carriers[i].Name < carriers[j].Name })
+
+	respondJSON(c, http.StatusOK, carriers)
+}
+
+// Helper functions
+func (r *ShippingProfileRequest) toDomain() *shippingDomain.Profile {
+	return &shippingDomain.Profile{
+		Name:           r.Name,
+		Countries:      r.Countries,
+		RateType:       shippingDomain.RateType(r.RateType),
+		FlatRate:       r.FlatRate,
+		Tiers:          r.Tiers,
+		FreeOverAmount: r.FreeOverAmount,
+	}
+}
+
+func toAddressResponse(a *shippingDomain.SavedAddress) *AddressResponse {
+	return &AddressResponse{
+		ID:        a.ID.String(),
+		Label:     a.Label,
+		Address:   a.Address,
+		IsDefault: a.IsDefault,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func toShippingProfileResponse(p *shippingDomain.Profile) *ShippingProfileResponse {
+	countries := p.Countries
+	if countries == nil {
+		countries = []string{}
+	}
+	tiers := p.Tiers
+	if tiers == nil {
+		tiers = []shippingDomain.Tier{}
+	}
+	return &ShippingProfileResponse{
+		ID:             p.ID.String(),
+		Name:           p.Name,
+		Countries:      countries,
+		RateType:       string(p.RateType),
+		FlatRate:       p.FlatRate,
+		Tiers:          tiers,
+		FreeOverAmount: p.FreeOverAmount,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+func toShippingQuoteResponse(q *shippingDomain.Quote) *ShippingQuoteResponse {
+	resp := &ShippingQuoteResponse{
+		SellerID:            q.SellerID.String(),
+		ProfileName:         q.ProfileName,
+		BillableWeightGrams: q.BillableWeightGrams,
+		Cost:                q.Cost,
+	}
+	if q.ProfileID != nil {
+		profileID := q.ProfileID.String()
+		resp.ProfileID = &profileID
+	}
+	return resp
+}
+
+// destinationOf builds a shipping destination from an optional saved address
+// ID and an optional inline address
+func destinationOf(addressID string, address *orderDomain.Address) (shippingApp.Destination, error) {
+	dest := shippingApp.Destination{Address: address}
+	if addressID != "" {
+		id, err := uuid.Parse(addressID)
+		if err != nil {
+			return dest, appErrors.New(appErrors.ErrValidation, "invalid address_id")
+		}
+		dest.AddressID = &id
+	}
+	return dest, nil
+}
+
+func addressParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	return userAndIDParams(c, "invalid address id")
+}
+
+func shippingProfileParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	return userAndIDParams(c, "invalid shipping profile id")
+}
+
+func userAndIDParams(c *gin.Context, invalidMessage string) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, invalidMessage))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/blytz/live/backend/internal/application/auth"
+	"github.com/blytz/live/backend/internal/domain/user"
+	appErrors "github.com/blytz/live/backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TwoFactorLoginRequest represents the second step of a two-factor login
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// TwoFactorCodeRequest represents a request confirmed with a two-factor code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest represents two-factor disable request
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// TwoFactorPolicyRequest represents two-factor policy update request
+type TwoFactorPolicyRequest struct {
+	Role     string `json:"role" binding:"required"`
+	Required *bool  `json:"required" binding:"required"`
+}
+
+// TwoFactorChallengeResponse is returned by a password login when a second
+// factor is needed
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"`
+}
+
+// TwoFactorSetupResponse represents two-factor enrolment response
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorStatusResponse represents two-factor status response
+type TwoFactorStatusResponse struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+// RecoveryCodesResponse carries recovery codes, shown to the user only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorPolicyResponse lists the roles that must use two-factor authentication
+type TwoFactorPolicyResponse struct {
+	RequiredRoles []string `json:"required_roles"`
+}
+
+// VerifyTwoFactorLogin completes a login with a two-factor code
+func (h *AuthHandler) VerifyTwoFactorLogin(c *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	resp, err := h.service.VerifyTwoFactorLogin(c.Request.Context(), &auth.TwoFactorLoginRequest{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		Client:         clientInfo(c),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toAuthResponse(resp))
+}
+
+// GetTwoFactorStatus gets the current user's two-factor status
+func (h *AuthHandler) GetTwoFactorStatus(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	status, err := h.service.GetTwoFactorStatus(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, &TwoFactorStatusResponse{
+		Enabled:           status.Enabled,
+		Required:          status.Required,
+		RecoveryCodesLeft: status.RecoveryCodesLeft,
+	})
+}
+
+// SetupTwoFactor starts two-factor enrolment
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setup, err := h.service.SetupTwoFactor(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, &TwoFactorSetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	})
+}
+
+// ConfirmTwoFactor enables two-factor authentication with a code from the
+// newly enrolled authenticator
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	sessionID, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		respondError(c, appErrors.ErrUnauthorizedAccess)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	codes, err := h.service.ConfirmTwoFactor(c.Request.Context(), userID, sessionID, req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, &RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor turns two-factor authentication off
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	if err := h.service.DisableTwoFactor(c.Request.Context(), userID, req.Password, req.Code); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, &RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// GetTwoFactorPolicy lists the roles that must use two-factor authentication
+func (h *AuthHandler) GetTwoFactorPolicy(c *gin.Context) {
+	roles, err := h.service.ListTwoFactorPolicy(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toTwoFactorPolicyResponse(roles))
+}
+
+// SetTwoFactorPolicy makes two-factor authentication mandatory or optional
+// for a role
+func (h *AuthHandler) SetTwoFactorPolicy(c *gin.Context) {
+	adminID, err := currentUserID(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var req TwoFactorPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, appErrors.New(appErrors.ErrValidation, err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.SetTwoFactorPolicy(ctx, adminID, user.Role(req.Role), *req.Required); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	roles, err := h.service.ListTwoFactorPolicy(ctx)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, toTwoFactorPolicyResponse(roles))
+}
+
+// Helper functions
+func toTwoFactorPolicyResponse(roles []user.Role) *TwoFactorPolicyResponse {
+	resp := &TwoFactorPolicyResponse{RequiredRoles: make([]string, len(roles))}
+	for i, r := range roles {
+		resp.RequiredRoles[i] = string(r)
+	}
+	return resp
+}
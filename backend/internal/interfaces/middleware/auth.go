@@ -1,15 +1,20 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/blytz/live/backend/internal/domain/user"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-// AuthMiddleware creates authentication middleware
-func AuthMiddleware(tokenManager user.TokenManager) gin.HandlerFunc {
+// AuthMiddleware creates authentication middleware. Tokens are only accepted
+// while they are the current access token of a session that has not been
+// revoked, so refreshing retires the previous access token.
+func AuthMiddleware(tokenManager user.TokenManager, sessions user.SessionRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -43,8 +48,26 @@ func AuthMiddleware(tokenManager user.TokenManager) gin.HandlerFunc {
 			return
 		}
 
+		if err := checkSession(c.Request.Context(), sessions, tokenString, claims); err != nil {
+			if errors.Is(err, user.ErrSessionNotFound) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"error":   "UNAUTHORIZED",
+					"message": "session has been revoked or token replaced",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "INTERNAL_ERROR",
+				"message": "failed to verify session",
+			})
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID.String())
+		c.Set("session_id", claims.SessionID.String())
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", string(claims.Role))
 
@@ -83,8 +106,99 @@ func RequireRole(roles ...user.Role) gin.HandlerFunc {
 	}
 }
 
-// OptionalAuth creates optional authentication middleware
-func OptionalAuth(tokenManager user.TokenManager) gin.HandlerFunc {
+// EmailVerifier reports whether a user has verified their email address
+type EmailVerifier interface {
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// RequireVerifiedEmail creates middleware rejecting users who have not
+// verified their email. With a nil verifier every user is let through, which
+// is how the requirement is switched off.
+func RequireVerifiedEmail(verifier EmailVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil {
+			c.Next()
+			return
+		}
+
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "UNAUTHORIZED",
+				"message": "authentication required",
+			})
+			return
+		}
+
+		verified, err := verifier.IsEmailVerified(c.Request.Context(), userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "INTERNAL_ERROR",
+				"message": "failed to check email verification",
+			})
+			return
+		}
+		if !verified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "FORBIDDEN",
+				"message": "verify your email address first",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// TwoFactorChecker reports whether a user meets their role's two-factor
+// authentication requirement
+type TwoFactorChecker interface {
+	TwoFactorSatisfied(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// RequireTwoFactor creates middleware rejecting users whose role requires
+// two-factor authentication they have not enabled
+func RequireTwoFactor(checker TwoFactorChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   "UNAUTHORIZED",
+				"message": "authentication required",
+			})
+			return
+		}
+
+		ok, err := checker.TwoFactorSatisfied(c.Request.Context(), userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "INTERNAL_ERROR",
+				"message": "failed to check two-factor authentication",
+			})
+			return
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "FORBIDDEN",
+				"message": "enable two-factor authentication to continue",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// OptionalAuth creates optional authentication middleware. Requests with
+// tokens of revoked sessions, or tokens since replaced, are treated as
+// anonymous.
+func OptionalAuth(tokenManager user.TokenManager, sessions user.SessionRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -103,11 +217,29 @@ func OptionalAuth(tokenManager user.TokenManager) gin.HandlerFunc {
 			c.Next()
 			return
 		}
+		if err := checkSession(c.Request.Context(), sessions, parts[1], claims); err != nil {
+			c.Next()
+			return
+		}
 
 		c.Set("user_id", claims.UserID.String())
+		c.Set("session_id", claims.SessionID.String())
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", string(claims.Role))
 
 		c.Next()
 	}
+}
+
+// checkSession verifies that token is the current access token of the live
+// session its claims name, returning user.ErrSessionNotFound otherwise
+func checkSession(ctx context.Context, sessions user.SessionRepository, token string, claims *user.TokenClaims) error {
+	session, err := sessions.GetByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if session.ID != claims.SessionID || session.UserID != claims.UserID {
+		return user.ErrSessionNotFound
+	}
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/blytz/live/backend/internal/domain/user"
+	"github.com/blytz/live/backend/internal/infrastructure/cache/redis"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fakeTokenManager accepts the access tokens it has been told about
+type fakeTokenManager struct {
+	user.TokenManager
+	claims map[string]*user.TokenClaims
+}
+
+func (m *fakeTokenManager) Validate(token string) (*user.TokenClaims, error) {
+	claims, ok := m.claims[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func TestAuthMiddlewareRejectsReplacedAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient(redis.Config{Host: mr.Host(), Port: mr.Port()})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	sessions := redis.NewSessionStore(client)
+
+	now := time.Now()
+	session := &user.Session{
+		ID:           uuid.New(),
+		UserID:       uuid.New(),
+		Token:        "access-1",
+		RefreshToken: "refresh-1",
+		ExpiresAt:    now.Add(time.Hour),
+		CreatedAt:    now,
+	}
+	if err := sessions.Create(ctx, session); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	claims := &user.TokenClaims{UserID: session.UserID, SessionID: session.ID, Role: user.RoleBuyer}
+	other := &user.TokenClaims{UserID: uuid.New(), SessionID: uuid.New(), Role: user.RoleBuyer}
+	tokens := &fakeTokenManager{claims: map[string]*user.TokenClaims{
+		"access-1": claims,
+		"access-2": claims,
+		"forged":   other,
+	}}
+
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(tokens, sessions), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	status := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if got := status("access-1"); got != http.StatusOK {
+		t.Fatalf("current token: status %d, want %d", got, http.StatusOK)
+	}
+
+	// Refreshing replaces the access token
+	next := *session
+	next.Token, next.RefreshToken = "access-2", "refresh-2"
+	if err := sessions.Rotate(ctx, &next, session.RefreshToken); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{name: "replaced token", token: "access-1", want: http.StatusUnauthorized},
+		{name: "new token", token: "access-2", want: http.StatusOK},
+		{name: "token of another session", token: "forged", want: http.StatusUnauthorized},
+		{name: "invalid token", token: "garbage", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status(tt.token); got != tt.want {
+				t.Errorf("status %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
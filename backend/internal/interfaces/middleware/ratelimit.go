@@ -108,6 +108,41 @@ func AuctionBidRateLimit(client *redis.Client) gin.HandlerFunc {
 			return
 		}
 		
+		c.Next()
+	}
+}
+
+// ReactionRateLimit limits reactions sent over REST per IP and, for signed-in
+// viewers, per user, as the hub limits each WebSocket client
+func ReactionRateLimit(client *redis.Client) gin.HandlerFunc {
+	// 10 reactions per second per user; an IP may be shared by several viewers
+	perUser := NewRateLimiter(client, 10, time.Second)
+	perIP := NewRateLimiter(client, 30, time.Second)
+	
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		
+		allowed, err := perIP.Allow(ctx, fmt.Sprintf("ratelimit:reaction:ip:%s", c.ClientIP()))
+		if err == nil && allowed {
+			if userID := c.GetString("user_id"); userID != "" {
+				allowed, err = perUser.Allow(ctx, fmt.Sprintf("ratelimit:reaction:user:%s", userID))
+			}
+		}
+		if err != nil {
+			// Fail open on error
+			c.Next()
+			return
+		}
+		
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"error":   "RATE_LIMIT_EXCEEDED",
+				"message": "too many reactions, please slow down",
+			})
+			return
+		}
+		
 		c.Next()
 	}
 }
\ No newline at end of file
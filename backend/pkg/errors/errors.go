@@ -23,6 +23,7 @@ const (
 	ErrInsufficientFunds   ErrorCode = "INSUFFICIENT_FUNDS"
 	ErrPaymentFailed       ErrorCode = "PAYMENT_FAILED"
 	ErrOrderNotCancellable ErrorCode = "ORDER_NOT_CANCELLABLE"
+	ErrBiddingSuspended    ErrorCode = "BIDDING_SUSPENDED"
 )
 
 type AppError struct {
@@ -81,7 +82,7 @@ func HTTPStatusFromCode(code ErrorCode) int {
 		return 400
 	case ErrUnauthorized:
 		return 401
-	case ErrForbidden:
+	case ErrForbidden, ErrBiddingSuspended:
 		return 403
 	case ErrConflict:
 		return 409
@@ -89,6 +90,10 @@ func HTTPStatusFromCode(code ErrorCode) int {
 		return 504
 	case ErrRateLimit:
 		return 429
+	case ErrOrderNotCancellable:
+		return 409
+	case ErrPaymentFailed, ErrInsufficientFunds:
+		return 402
 	default:
 		return 500
 	}